@@ -0,0 +1,500 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package deploymentapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/api/deploymentapi/deputil"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/plan"
+	"github.com/elastic/cloud-sdk-go/pkg/plan/planutil"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+// ignoredPlanFields aren't part of the deployment state and thus are never
+// considered a change.
+var ignoredPlanFields = map[string]bool{"transient": true}
+
+// ApplyParams is consumed by Apply.
+type ApplyParams struct {
+	*api.API
+
+	// Request is the desired deployment specification.
+	Request *models.DeploymentCreateRequest
+
+	// DeploymentID of the deployment to converge, when empty the deployment
+	// is looked up by Query or by the Request name.
+	DeploymentID string
+
+	// Query, when specified, is used to find the deployment instead of its
+	// name, for example to match a deployment by its metadata. The query
+	// cannot match more than one deployment.
+	Query *models.SearchRequest
+
+	// RequestID is used as the idempotency key when the deployment is
+	// created.
+	RequestID string
+
+	// Wait blocks until the applied plan changes have finished.
+	Wait bool
+
+	// TrackFrequency controls how often the plan changes are polled when
+	// Wait is set.
+	TrackFrequency plan.TrackFrequencyConfig
+}
+
+// Validate ensures the parameters are usable.
+func (params ApplyParams) Validate() error {
+	var merr = multierror.NewPrefixed("deployment apply")
+	if params.API == nil {
+		merr = merr.Append(apierror.ErrMissingAPI)
+	}
+
+	if params.Request == nil {
+		merr = merr.Append(errors.New("request payload cannot be empty"))
+	}
+
+	if params.Request != nil && params.Request.Resources == nil {
+		merr = merr.Append(errors.New("request resources cannot be empty"))
+	}
+
+	if params.DeploymentID != "" && len(params.DeploymentID) != 32 {
+		merr = merr.Append(deputil.NewInvalidDeploymentIDError(params.DeploymentID))
+	}
+
+	var noName = params.Request != nil && params.Request.Name == ""
+	if params.DeploymentID == "" && params.Query == nil && noName {
+		merr = merr.Append(errors.New(
+			"one of deployment id, query or request name must be specified",
+		))
+	}
+
+	return merr.ErrorOrNil()
+}
+
+// ApplyChange is a single difference between the deployment and its desired
+// specification.
+type ApplyChange struct {
+	// Path of the changed field, i.e. resources.elasticsearch[main-elasticsearch].plan.elasticsearch.version.
+	Path string
+
+	// Current value of the field, nil when the field or resource is missing.
+	Current interface{}
+
+	// Desired value of the field.
+	Desired interface{}
+}
+
+func (c ApplyChange) String() string {
+	current, _ := json.Marshal(c.Current)
+	desired, _ := json.Marshal(c.Desired)
+	return fmt.Sprintf("%s: %s -> %s", c.Path, current, desired)
+}
+
+// ApplyResponse is returned by Apply.
+type ApplyResponse struct {
+	DeploymentID string
+
+	// Created is true when the deployment didn't exist and was created.
+	Created bool
+
+	// Updated is true when the deployment differed and was updated.
+	Updated bool
+
+	// Changes contains the applied differences, it's empty when the
+	// deployment has been created.
+	Changes []ApplyChange
+}
+
+// Apply converges a deployment to the desired specification. The deployment
+// is created when it can't be found, otherwise the specification is merged
+// onto the current plans and settings of its resources and only when the
+// result differs from them, the deployment is updated with it. Fields and
+// topology elements which the specification doesn't set keep their current
+// values, a topology element is removed by setting its size to zero.
+// Resources which exist in the deployment but not in the specification are
+// left untouched. When Wait is set, it blocks until the plan changes have
+// finished.
+func Apply(params ApplyParams) (*ApplyResponse, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	id, err := findDeployment(params)
+	if err != nil {
+		return nil, err
+	}
+
+	var response ApplyResponse
+	if id == "" {
+		res, err := Create(CreateParams{
+			API: params.API, Request: params.Request, RequestID: params.RequestID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		response.DeploymentID, response.Created = *res.ID, true
+		return &response, waitApply(params, response.DeploymentID)
+	}
+
+	current, err := Get(GetParams{
+		API: params.API, DeploymentID: id,
+		QueryParams: deputil.QueryParams{ShowPlans: true, ShowSettings: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response.DeploymentID = id
+	req, changes, err := mergeDeployment(current, params.Request)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Changes = changes; len(response.Changes) == 0 {
+		return &response, nil
+	}
+
+	if _, err := Update(UpdateParams{
+		API: params.API, DeploymentID: id, Request: req,
+	}); err != nil {
+		return nil, err
+	}
+	response.Updated = true
+
+	return &response, waitApply(params, id)
+}
+
+// findDeployment returns the ID of the deployment matching the parameters or
+// an empty string when it doesn't exist.
+func findDeployment(params ApplyParams) (string, error) {
+	if params.DeploymentID != "" {
+		return params.DeploymentID, nil
+	}
+
+	var matches []string
+	if params.Query != nil {
+		res, err := Search(SearchParams{API: params.API, Request: params.Query})
+		if err != nil {
+			return "", err
+		}
+		for _, d := range res.Deployments {
+			matches = append(matches, *d.ID)
+		}
+	} else {
+		res, err := List(ListParams{API: params.API})
+		if err != nil {
+			return "", err
+		}
+		for _, d := range res.Deployments {
+			if d.Name != nil && *d.Name == params.Request.Name {
+				matches = append(matches, *d.ID)
+			}
+		}
+	}
+
+	if len(matches) > 1 {
+		return "", fmt.Errorf(
+			"deployment apply: found %d matching deployments %v, expected at most one",
+			len(matches), matches,
+		)
+	}
+
+	if len(matches) == 1 {
+		return matches[0], nil
+	}
+	return "", nil
+}
+
+func waitApply(params ApplyParams, id string) error {
+	if !params.Wait {
+		return nil
+	}
+
+	return planutil.Wait(plan.TrackChangeParams{
+		API: params.API, DeploymentID: id, Config: params.TrackFrequency,
+	})
+}
+
+// mergeDeployment merges the desired specification onto the current plans
+// and settings of the deployment resources, matched by their RefID. It returns
+// the update request with the merged resources and its differences with the
+// current deployment.
+func mergeDeployment(current *models.DeploymentGetResponse, desired *models.DeploymentCreateRequest) (*models.DeploymentUpdateRequest, []ApplyChange, error) {
+	base, err := NewUpdateRequest(current, ConvertOptions{DropTransient: true})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var req = models.DeploymentUpdateRequest{
+		Name:         base.Name,
+		PruneOrphans: ec.Bool(false),
+	}
+
+	var changes []ApplyChange
+	if desired.Name != "" && desired.Name != base.Name {
+		var name interface{}
+		if current.Name != nil {
+			name = *current.Name
+		}
+		changes = append(changes, ApplyChange{Path: "name", Current: name, Desired: desired.Name})
+		req.Name = desired.Name
+	}
+
+	currentResources, err := toJSONMap(base.Resources)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	desiredResources, err := toJSONMap(desired.Resources)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var kinds = make([]string, 0, len(desiredResources))
+	for kind := range desiredResources {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	for _, kind := range kinds {
+		var resources = append([]interface{}(nil), toSlice(currentResources[kind])...)
+		var existing = make(map[string]int, len(resources))
+		for i, r := range resources {
+			if resource, ok := r.(map[string]interface{}); ok {
+				existing[fmt.Sprint(resource["ref_id"])] = i
+			}
+		}
+
+		for i, r := range toSlice(desiredResources[kind]) {
+			resource, ok := r.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			var refID = strconv.Itoa(i)
+			if id, ok := resource["ref_id"].(string); ok {
+				refID = id
+			}
+
+			var path = fmt.Sprintf("resources.%s[%s]", kind, refID)
+			index, ok := existing[refID]
+			if !ok {
+				changes = append(changes, ApplyChange{Path: path, Desired: resource})
+				resources = append(resources, resource)
+				continue
+			}
+
+			var merged = mergeValues("", resources[index], resource)
+			changes = append(changes, diffValues(path, resources[index], merged)...)
+			resources[index] = merged
+		}
+		currentResources[kind] = resources
+	}
+
+	b, err := json.Marshal(currentResources)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := json.Unmarshal(b, &req.Resources); err != nil {
+		return nil, nil, err
+	}
+
+	return &req, changes, nil
+}
+
+// mergeValues returns the current value with the fields set in the desired
+// value replaced. Topology elements are merged with the current ones they
+// match by topologyKey, while the rest of arrays are replaced.
+func mergeValues(key string, current, desired interface{}) interface{} {
+	switch d := desired.(type) {
+	case nil:
+		return current
+	case map[string]interface{}:
+		c, _ := current.(map[string]interface{})
+		var merged = make(map[string]interface{}, len(c)+len(d))
+		for k, v := range c {
+			merged[k] = v
+		}
+		for k, v := range d {
+			merged[k] = mergeValues(k, c[k], v)
+		}
+		return merged
+	case []interface{}:
+		if key == "cluster_topology" {
+			return mergeTopology(toSlice(current), d)
+		}
+	}
+	return desired
+}
+
+// mergeTopology merges the desired topology elements onto the current ones
+// they match by topologyKey, elements without a key are matched by index.
+// Unmatched desired elements are appended.
+func mergeTopology(current, desired []interface{}) []interface{} {
+	var merged = append([]interface{}(nil), current...)
+	var keys = make(map[string]int, len(current))
+	for i, item := range current {
+		if key := topologyKey(item); key != "" {
+			keys[key] = i
+		}
+	}
+
+	for i, item := range desired {
+		var index, ok = i, i < len(current)
+		if key := topologyKey(item); key != "" {
+			index, ok = keys[key]
+		}
+
+		if !ok {
+			merged = append(merged, item)
+			continue
+		}
+		merged[index] = mergeValues("", current[index], item)
+	}
+
+	return merged
+}
+
+// diffValues returns the differences between the current and desired values,
+// only taking into account the fields which are set in the desired value.
+// Fields which aren't part of the deployment state, such as the transient
+// plan settings, are ignored.
+func diffValues(path string, current, desired interface{}) []ApplyChange {
+	switch d := desired.(type) {
+	case nil:
+		return nil
+	case map[string]interface{}:
+		c, _ := current.(map[string]interface{})
+		var keys = make([]string, 0, len(d))
+		for k := range d {
+			if !ignoredPlanFields[k] {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+
+		var changes []ApplyChange
+		for _, k := range keys {
+			changes = append(changes, diffValues(path+"."+k, c[k], d[k])...)
+		}
+		return changes
+	case []interface{}:
+		if strings.HasSuffix(path, ".cluster_topology") {
+			return diffTopology(path, toSlice(current), d)
+		}
+
+		var c = toSlice(current)
+		var changes []ApplyChange
+		for i, item := range d {
+			var currentItem interface{}
+			if i < len(c) {
+				currentItem = c[i]
+			}
+			changes = append(changes,
+				diffValues(fmt.Sprintf("%s[%d]", path, i), currentItem, item)...,
+			)
+		}
+		return changes
+	}
+
+	if reflect.DeepEqual(current, desired) {
+		return nil
+	}
+	return []ApplyChange{{Path: path, Current: current, Desired: desired}}
+}
+
+// diffTopology compares the desired topology elements with the current ones
+// they match by topologyKey. Elements without a key are matched by index.
+func diffTopology(path string, current, desired []interface{}) []ApplyChange {
+	var keys = make(map[string]int, len(current))
+	for i, item := range current {
+		if key := topologyKey(item); key != "" {
+			keys[key] = i
+		}
+	}
+
+	var changes []ApplyChange
+	for i, item := range desired {
+		var elementPath = fmt.Sprintf("%s[%d]", path, i)
+		var index, ok = i, i < len(current)
+		if key := topologyKey(item); key != "" {
+			elementPath = fmt.Sprintf("%s[%s]", path, key)
+			index, ok = keys[key]
+		}
+
+		var currentItem interface{}
+		if ok {
+			currentItem = current[index]
+		}
+		changes = append(changes, diffValues(elementPath, currentItem, item)...)
+	}
+
+	return changes
+}
+
+// topologyKey identifies a topology element by its instance configuration ID,
+// its ID or its node types, in that order. It returns an empty string when the
+// element sets none of them.
+func topologyKey(element interface{}) string {
+	e, _ := element.(map[string]interface{})
+	for _, field := range []string{"instance_configuration_id", "id"} {
+		if v, ok := e[field].(string); ok && v != "" {
+			return v
+		}
+	}
+
+	nodeType, _ := e["node_type"].(map[string]interface{})
+	var types = make([]string, 0, len(nodeType))
+	for t, enabled := range nodeType {
+		if v, ok := enabled.(bool); ok && v {
+			types = append(types, t)
+		}
+	}
+	sort.Strings(types)
+	return strings.Join(types, ",")
+}
+
+func toJSONMap(v interface{}) (map[string]interface{}, error) {
+	var res = make(map[string]interface{})
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(b, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func toSlice(v interface{}) []interface{} {
+	s, _ := v.([]interface{})
+	return s
+}
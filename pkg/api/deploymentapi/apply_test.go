@@ -0,0 +1,477 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package deploymentapi
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/api/deploymentapi/deputil"
+	"github.com/elastic/cloud-sdk-go/pkg/api/mock"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+func TestApply(t *testing.T) {
+	const id = "f1d329b0fb34470ba8b18361cabdd2bc"
+	var desired = func(version string, size int32) *models.DeploymentCreateRequest {
+		return &models.DeploymentCreateRequest{
+			Name: "my-deployment",
+			Resources: &models.DeploymentCreateResources{
+				Elasticsearch: []*models.ElasticsearchPayload{{
+					RefID:  ec.String("main-elasticsearch"),
+					Region: ec.String("us-east-1"),
+					Plan: &models.ElasticsearchClusterPlan{
+						Elasticsearch: &models.ElasticsearchConfiguration{Version: version},
+						ClusterTopology: []*models.ElasticsearchClusterTopologyElement{{
+							ZoneCount:               2,
+							InstanceConfigurationID: "data.default",
+							Size: &models.TopologySize{
+								Resource: ec.String("memory"), Value: ec.Int32(size),
+							},
+						}},
+						Transient: &models.TransientElasticsearchPlanConfiguration{
+							Strategy: &models.PlanStrategy{Rolling: &models.RollingStrategyConfig{}},
+						},
+					},
+				}},
+			},
+		}
+	}
+	var withKibana = func(req *models.DeploymentCreateRequest) *models.DeploymentCreateRequest {
+		req.Resources.Kibana = []*models.KibanaPayload{{
+			RefID:                     ec.String("main-kibana"),
+			ElasticsearchClusterRefID: ec.String("main-elasticsearch"),
+			Region:                    ec.String("us-east-1"),
+			Plan:                      &models.KibanaClusterPlan{Kibana: &models.KibanaConfiguration{}},
+		}}
+		return req
+	}
+
+	var listResponse = func(names ...string) mock.Response {
+		var res models.DeploymentsListResponse
+		for _, name := range names {
+			res.Deployments = append(res.Deployments, &models.DeploymentsListingData{
+				ID: ec.String(id), Name: ec.String(name),
+			})
+		}
+		return mock.New200ResponseAssertion(&mock.RequestAssertion{
+			Header: api.DefaultReadMockHeaders,
+			Method: "GET",
+			Host:   api.DefaultMockHost,
+			Path:   "/api/v1/deployments",
+		}, mock.NewStructBody(res))
+	}
+	var getResponse = func() mock.Response {
+		return mock.New200ResponseAssertion(&mock.RequestAssertion{
+			Header: api.DefaultReadMockHeaders,
+			Method: "GET",
+			Host:   api.DefaultMockHost,
+			Path:   "/api/v1/deployments/" + id,
+			Query: url.Values{
+				"convert_legacy_plans": {"false"},
+				"enrich_with_template": {"true"},
+				"show_metadata":        {"false"},
+				"show_plan_defaults":   {"false"},
+				"show_plan_history":    {"false"},
+				"show_plan_logs":       {"false"},
+				"show_plans":           {"true"},
+				"show_security":        {"false"},
+				"show_settings":        {"true"},
+				"show_system_alerts":   {"5"},
+			},
+		}, mock.NewStructBody(models.DeploymentGetResponse{
+			ID:   ec.String(id),
+			Name: ec.String("my-deployment"),
+			Resources: &models.DeploymentResources{
+				Elasticsearch: []*models.ElasticsearchResourceInfo{{
+					ID:     ec.String("3ee11eb40eda22cac0cce259625c6734"),
+					RefID:  ec.String("main-elasticsearch"),
+					Region: ec.String("us-east-1"),
+					Info: &models.ElasticsearchClusterInfo{
+						Status: ec.String("started"),
+						Settings: &models.ElasticsearchClusterSettings{
+							Curation: &models.ClusterCurationSettings{},
+						},
+						PlanInfo: &models.ElasticsearchClusterPlansInfo{
+							Current: &models.ElasticsearchClusterPlanInfo{
+								Plan: &models.ElasticsearchClusterPlan{
+									Elasticsearch: &models.ElasticsearchConfiguration{
+										Version:          "7.10.0",
+										UserSettingsYaml: "a: b",
+									},
+									ClusterTopology: []*models.ElasticsearchClusterTopologyElement{
+										{
+											ZoneCount:               1,
+											InstanceConfigurationID: "master",
+											Size: &models.TopologySize{
+												Resource: ec.String("memory"), Value: ec.Int32(0),
+											},
+										},
+										{
+											ZoneCount:               1,
+											InstanceConfigurationID: "ml",
+											Size: &models.TopologySize{
+												Resource: ec.String("memory"), Value: ec.Int32(0),
+											},
+										},
+										{
+											ZoneCount:               2,
+											InstanceConfigurationID: "data.default",
+											Size: &models.TopologySize{
+												Resource: ec.String("memory"), Value: ec.Int32(1024),
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				}},
+			},
+		}))
+	}
+	// merged is the elasticsearch payload which results from merging the
+	// desired specification onto the current deployment.
+	var merged = func(version string, size int32) *models.ElasticsearchPayload {
+		var payload = desired(version, size).Resources.Elasticsearch[0]
+		payload.Settings = &models.ElasticsearchClusterSettings{
+			Curation: &models.ClusterCurationSettings{},
+		}
+		payload.Plan.Elasticsearch.UserSettingsYaml = "a: b"
+		payload.Plan.ClusterTopology = append([]*models.ElasticsearchClusterTopologyElement{
+			{
+				ZoneCount:               1,
+				InstanceConfigurationID: "master",
+				Size:                    &models.TopologySize{Resource: ec.String("memory"), Value: ec.Int32(0)},
+			},
+			{
+				ZoneCount:               1,
+				InstanceConfigurationID: "ml",
+				Size:                    &models.TopologySize{Resource: ec.String("memory"), Value: ec.Int32(0)},
+			},
+		}, payload.Plan.ClusterTopology...)
+		return payload
+	}
+	var updateResponse = func(resources *models.DeploymentUpdateResources) mock.Response {
+		return mock.New200ResponseAssertion(&mock.RequestAssertion{
+			Header: api.DefaultWriteMockHeaders,
+			Method: "PUT",
+			Host:   api.DefaultMockHost,
+			Path:   "/api/v1/deployments/" + id,
+			Query: url.Values{
+				"hide_pruned_orphans": {"false"},
+				"skip_snapshot":       {"false"},
+				"validate_only":       {"false"},
+			},
+			Body: mock.NewStructBody(models.DeploymentUpdateRequest{
+				Name:         "my-deployment",
+				PruneOrphans: ec.Bool(false),
+				Resources:    resources,
+			}),
+		}, mock.NewStructBody(models.DeploymentUpdateResponse{ID: ec.String(id)}))
+	}
+
+	tests := []struct {
+		name   string
+		params ApplyParams
+		want   *ApplyResponse
+		err    string
+	}{
+		{
+			name: "fails due to parameter validation",
+			params: ApplyParams{
+				Request:      &models.DeploymentCreateRequest{},
+				DeploymentID: "invalid",
+			},
+			err: multierror.NewPrefixed("deployment apply",
+				apierror.ErrMissingAPI,
+				errors.New("request resources cannot be empty"),
+				deputil.NewInvalidDeploymentIDError("invalid"),
+			).Error(),
+		},
+		{
+			name: "fails when no lookup criteria is specified",
+			params: ApplyParams{
+				API:     api.NewMock(),
+				Request: &models.DeploymentCreateRequest{Resources: &models.DeploymentCreateResources{}},
+			},
+			err: multierror.NewPrefixed("deployment apply",
+				errors.New("one of deployment id, query or request name must be specified"),
+			).Error(),
+		},
+		{
+			name: "fails listing the deployments",
+			params: ApplyParams{
+				API:     api.NewMock(mock.New500Response(mock.NewStringBody(`{"error": "some error"}`))),
+				Request: desired("7.10.0", 1024),
+			},
+			err: `{"error": "some error"}`,
+		},
+		{
+			name: "fails when multiple deployments match the name",
+			params: ApplyParams{
+				API:     api.NewMock(listResponse("my-deployment", "other", "my-deployment")),
+				Request: desired("7.10.0", 1024),
+			},
+			err: "deployment apply: found 2 matching deployments [f1d329b0fb34470ba8b18361cabdd2bc f1d329b0fb34470ba8b18361cabdd2bc], expected at most one",
+		},
+		{
+			name: "creates the deployment when it's not found",
+			params: ApplyParams{
+				API: api.NewMock(
+					listResponse("other"),
+					mock.New201Response(mock.NewStructBody(models.DeploymentCreateResponse{
+						ID: ec.String(id), Name: ec.String("my-deployment"),
+					})),
+				),
+				Request: desired("7.10.0", 1024),
+			},
+			want: &ApplyResponse{DeploymentID: id, Created: true},
+		},
+		{
+			name: "doesn't update the deployment when it's unchanged",
+			params: ApplyParams{
+				API:     api.NewMock(listResponse("my-deployment"), getResponse()),
+				Request: desired("7.10.0", 1024),
+			},
+			want: &ApplyResponse{DeploymentID: id},
+		},
+		{
+			name: "updates the changed deployment found by its ID keeping the unset fields",
+			params: ApplyParams{
+				API: api.NewMock(getResponse(), updateResponse(&models.DeploymentUpdateResources{
+					Elasticsearch: []*models.ElasticsearchPayload{merged("7.11.0", 2048)},
+				})),
+				Request:      desired("7.11.0", 2048),
+				DeploymentID: id,
+			},
+			want: &ApplyResponse{DeploymentID: id, Updated: true, Changes: []ApplyChange{
+				{
+					Path:    "resources.elasticsearch[main-elasticsearch].plan.cluster_topology[data.default].size.value",
+					Current: float64(1024),
+					Desired: float64(2048),
+				},
+				{
+					Path:    "resources.elasticsearch[main-elasticsearch].plan.elasticsearch.version",
+					Current: "7.10.0",
+					Desired: "7.11.0",
+				},
+			}},
+		},
+		{
+			name: "adds the missing resources of the deployment found by query",
+			params: ApplyParams{
+				API: api.NewMock(
+					mock.New200Response(mock.NewStructBody(models.DeploymentsSearchResponse{
+						Deployments: []*models.DeploymentSearchResponse{{ID: ec.String(id)}},
+					})),
+					getResponse(),
+					updateResponse(&models.DeploymentUpdateResources{
+						Elasticsearch: []*models.ElasticsearchPayload{merged("7.10.0", 1024)},
+						Kibana:        withKibana(desired("7.10.0", 1024)).Resources.Kibana,
+					}),
+				),
+				Request: withKibana(desired("7.10.0", 1024)),
+				Query:   &models.SearchRequest{},
+			},
+			want: &ApplyResponse{DeploymentID: id, Updated: true, Changes: []ApplyChange{
+				{
+					Path: "resources.kibana[main-kibana]",
+					Desired: map[string]interface{}{
+						"elasticsearch_cluster_ref_id": "main-elasticsearch",
+						"plan": map[string]interface{}{
+							"cluster_topology": nil,
+							"kibana":           map[string]interface{}{},
+						},
+						"ref_id": "main-kibana",
+						"region": "us-east-1",
+					},
+				},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Apply(tt.params)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestApplyChange_String(t *testing.T) {
+	var change = ApplyChange{Path: "name", Current: "a", Desired: "b"}
+	assert.Equal(t, `name: "a" -> "b"`, change.String())
+}
+
+func Test_diffTopology(t *testing.T) {
+	var element = func(id string, size float64, zones float64) map[string]interface{} {
+		return map[string]interface{}{
+			"instance_configuration_id": id,
+			"size":                      map[string]interface{}{"resource": "memory", "value": size},
+			"zone_count":                zones,
+		}
+	}
+	var current = []interface{}{
+		element("master", 0, 1),
+		element("data.default", 1024, 2),
+		element("ml", 0, 1),
+		element("data.warm", 2048, 2),
+	}
+	var path = "resources.elasticsearch[main-elasticsearch].plan.cluster_topology"
+
+	tests := []struct {
+		name    string
+		current []interface{}
+		desired []interface{}
+		want    []ApplyChange
+	}{
+		{
+			name:    "reordered elements are unchanged",
+			current: current,
+			desired: []interface{}{
+				element("data.warm", 2048, 2),
+				element("ml", 0, 1),
+				element("data.default", 1024, 2),
+				element("master", 0, 1),
+			},
+		},
+		{
+			name:    "missing elements are unchanged",
+			current: current,
+			desired: []interface{}{
+				element("data.warm", 2048, 2),
+				element("data.default", 1024, 2),
+			},
+		},
+		{
+			name:    "changed elements are matched by instance configuration",
+			current: current,
+			desired: []interface{}{
+				element("data.warm", 4096, 2),
+				element("ml", 1024, 1),
+				element("data.default", 1024, 3),
+			},
+			want: []ApplyChange{
+				{Path: path + "[data.warm].size.value", Current: float64(2048), Desired: float64(4096)},
+				{Path: path + "[ml].size.value", Current: float64(0), Desired: float64(1024)},
+				{Path: path + "[data.default].zone_count", Current: float64(2), Desired: float64(3)},
+			},
+		},
+		{
+			name: "elements are matched by node type",
+			current: []interface{}{
+				map[string]interface{}{
+					"node_type":       map[string]interface{}{"master": true, "data": false},
+					"memory_per_node": float64(1024),
+				},
+				map[string]interface{}{
+					"node_type":       map[string]interface{}{"data": true, "ingest": true},
+					"memory_per_node": float64(2048),
+				},
+			},
+			desired: []interface{}{
+				map[string]interface{}{
+					"node_type":       map[string]interface{}{"ingest": true, "data": true},
+					"memory_per_node": float64(4096),
+				},
+				map[string]interface{}{
+					"node_type":       map[string]interface{}{"master": true},
+					"memory_per_node": float64(1024),
+				},
+			},
+			want: []ApplyChange{
+				{Path: path + "[data,ingest].memory_per_node", Current: float64(2048), Desired: float64(4096)},
+			},
+		},
+		{
+			name:    "elements without a key are matched by index",
+			current: []interface{}{map[string]interface{}{"zone_count": float64(1)}},
+			desired: []interface{}{
+				map[string]interface{}{"zone_count": float64(2)},
+				map[string]interface{}{"zone_count": float64(1)},
+			},
+			want: []ApplyChange{
+				{Path: path + "[0].zone_count", Current: float64(1), Desired: float64(2)},
+				{Path: path + "[1].zone_count", Desired: float64(1)},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, diffTopology(path, tt.current, tt.desired))
+		})
+	}
+}
+
+func Test_mergeValues(t *testing.T) {
+	var current = map[string]interface{}{
+		"plan": map[string]interface{}{
+			"elasticsearch": map[string]interface{}{
+				"version":            "7.10.0",
+				"user_settings_yaml": "a: b",
+				"user_plugins":       []interface{}{"a", "b"},
+			},
+			"cluster_topology": []interface{}{
+				map[string]interface{}{"instance_configuration_id": "master", "zone_count": float64(1)},
+				map[string]interface{}{"instance_configuration_id": "data.default", "zone_count": float64(2)},
+			},
+		},
+	}
+	var desired = map[string]interface{}{
+		"plan": map[string]interface{}{
+			"elasticsearch": map[string]interface{}{
+				"version":      "7.11.0",
+				"user_plugins": []interface{}{"c"},
+			},
+			"cluster_topology": []interface{}{
+				map[string]interface{}{"instance_configuration_id": "data.default", "zone_count": float64(3)},
+				map[string]interface{}{"instance_configuration_id": "ml", "zone_count": float64(1)},
+			},
+		},
+	}
+
+	assert.Equal(t, map[string]interface{}{
+		"plan": map[string]interface{}{
+			"elasticsearch": map[string]interface{}{
+				"version":            "7.11.0",
+				"user_settings_yaml": "a: b",
+				"user_plugins":       []interface{}{"c"},
+			},
+			"cluster_topology": []interface{}{
+				map[string]interface{}{"instance_configuration_id": "master", "zone_count": float64(1)},
+				map[string]interface{}{"instance_configuration_id": "data.default", "zone_count": float64(3)},
+				map[string]interface{}{"instance_configuration_id": "ml", "zone_count": float64(1)},
+			},
+		},
+	}, mergeValues("", current, desired))
+	assert.Equal(t, "7.10.0", current["plan"].(map[string]interface{})["elasticsearch"].(map[string]interface{})["version"],
+		"the current value is not modified",
+	)
+}
@@ -0,0 +1,184 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ccsapi
+
+import (
+	"context"
+	"sort"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/client/clusters_elasticsearch"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+// GetParams is consumed by the GetSettings and ListReferrers functions.
+type GetParams struct {
+	Params
+}
+
+// Validate ensures the parameters are usable by the consuming function.
+func (params GetParams) Validate() error {
+	return multierror.NewPrefixed("deployment ccs get",
+		params.errors()...,
+	).ErrorOrNil()
+}
+
+// GetSettings returns the cross-cluster search settings of a deployment,
+// which contain the remote clusters keyed by their aliases.
+func GetSettings(params GetParams) (*models.CrossClusterSearchSettings, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	es, err := getElasticsearch(params.Params, params.DeploymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return getSettings(params.Params, es.ClusterID)
+}
+
+// ListReferrers returns the sorted IDs of the deployments which are using the
+// specified deployment as a cross-cluster search remote.
+func ListReferrers(params GetParams) ([]string, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	es, err := getElasticsearch(params.Params, params.DeploymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	var ctx = api.WithRegion(context.Background(), params.Region)
+	res, err := params.V1API.ClustersElasticsearch.GetEsClusterCcs(
+		clusters_elasticsearch.NewGetEsClusterCcsParams().
+			WithContext(ctx).
+			WithClusterID(es.ClusterID),
+		params.AuthWriter,
+	)
+	if err != nil {
+		return nil, apierror.Unwrap(err)
+	}
+
+	var deployments = make([]string, 0, len(res.Payload.CcsClusters))
+	for _, clusterID := range res.Payload.CcsClusters {
+		cluster, err := params.V1API.ClustersElasticsearch.GetEsCluster(
+			clusters_elasticsearch.NewGetEsClusterParams().
+				WithContext(ctx).
+				WithClusterID(clusterID).
+				WithShowPlans(ec.Bool(false)),
+			params.AuthWriter,
+		)
+		if err != nil {
+			return nil, apierror.Unwrap(err)
+		}
+
+		deployments = append(deployments, cluster.Payload.DeploymentID)
+	}
+	sort.Strings(deployments)
+
+	return deployments, nil
+}
+
+// ListEligibleRemotesParams is consumed by the ListEligibleRemotes function.
+type ListEligibleRemotesParams struct {
+	Params
+
+	// Optional cluster name or ID prefix to filter the candidates.
+	Query string
+
+	// Optional maximum number of candidates to return.
+	Size int64
+}
+
+// Validate ensures the parameters are usable by the consuming function.
+func (params ListEligibleRemotesParams) Validate() error {
+	return multierror.NewPrefixed("deployment ccs list eligible remotes",
+		params.errors()...,
+	).ErrorOrNil()
+}
+
+// ListEligibleRemotes returns the Elasticsearch clusters which can be used as
+// cross-cluster search remotes by the deployment, based on its version.
+func ListEligibleRemotes(params ListEligibleRemotesParams) (*models.ElasticsearchClustersInfo, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	es, err := getElasticsearch(params.Params, params.DeploymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := requireVersion(es); err != nil {
+		return nil, err
+	}
+
+	return listEligibleRemotes(params.Params, es.Version, params.Query, params.Size)
+}
+
+func listEligibleRemotes(params Params, version, query string, size int64) (*models.ElasticsearchClustersInfo, error) {
+	var p = clusters_elasticsearch.NewGetEsCcsEligibleRemotesParams().
+		WithContext(api.WithRegion(context.Background(), params.Region)).
+		WithVersion(version)
+	if query != "" {
+		p.SetQ(ec.String(query))
+	}
+	if size > 0 {
+		p.SetSize(ec.Int64(size))
+	}
+
+	res, err := params.V1API.ClustersElasticsearch.GetEsCcsEligibleRemotes(
+		p, params.AuthWriter,
+	)
+	if err != nil {
+		return nil, apierror.Unwrap(err)
+	}
+
+	return res.Payload, nil
+}
+
+func getSettings(params Params, clusterID string) (*models.CrossClusterSearchSettings, error) {
+	res, err := params.V1API.ClustersElasticsearch.GetEsClusterCcsSettings(
+		clusters_elasticsearch.NewGetEsClusterCcsSettingsParams().
+			WithContext(api.WithRegion(context.Background(), params.Region)).
+			WithClusterID(clusterID),
+		params.AuthWriter,
+	)
+	if err != nil {
+		return nil, apierror.Unwrap(err)
+	}
+
+	return res.Payload, nil
+}
+
+func setSettings(params Params, clusterID string, settings *models.CrossClusterSearchSettings) error {
+	return api.ReturnErrOnly(
+		params.V1API.ClustersElasticsearch.SetEsClusterCcsSettings(
+			clusters_elasticsearch.NewSetEsClusterCcsSettingsParams().
+				WithContext(api.WithRegion(context.Background(), params.Region)).
+				WithClusterID(clusterID).
+				WithBody(settings),
+			params.AuthWriter,
+		),
+	)
+}
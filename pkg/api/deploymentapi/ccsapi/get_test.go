@@ -0,0 +1,268 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ccsapi
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/api/mock"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+const (
+	localDeploymentID  = "f1d329b0fb34470ba8b18361cabdd2bc"
+	localClusterID     = "cde7b6b605424a54ce9d56316eab13a1"
+	remoteDeploymentID = "a4b3e2c1d0f94e8b8a7c6d5e4f3a2b1c"
+	remoteClusterID    = "9a8b7c6d5e4f4a3b2c1d0e9f8a7b6c5d"
+)
+
+func newDeploymentResponse(deploymentID, clusterID, version string) string {
+	return fmt.Sprintf(`{
+  "id": "%s",
+  "resources": {
+    "elasticsearch": [{
+      "id": "%s",
+      "ref_id": "main-elasticsearch",
+      "region": "us-east-1",
+      "info": {
+        "plan_info": {
+          "current": {
+            "plan": {"elasticsearch": {"version": "%s"}}
+          }
+        }
+      }
+    }]
+  }
+}`, deploymentID, clusterID, version)
+}
+
+var (
+	localDeployment  = newDeploymentResponse(localDeploymentID, localClusterID, "7.8.0")
+	remoteDeployment = newDeploymentResponse(remoteDeploymentID, remoteClusterID, "7.7.1")
+)
+
+func TestGetSettings(t *testing.T) {
+	type args struct {
+		params GetParams
+	}
+	tests := []struct {
+		name string
+		args args
+		want *models.CrossClusterSearchSettings
+		err  error
+	}{
+		{
+			name: "fails due to parameter validation",
+			err: multierror.NewPrefixed("deployment ccs get",
+				apierror.ErrMissingAPI,
+				errors.New(`id "" is invalid`),
+				errors.New("region not specified and is required for this operation"),
+			),
+		},
+		{
+			name: "fails when the deployment has no elasticsearch resources",
+			args: args{params: GetParams{Params: Params{
+				DeploymentID: localDeploymentID,
+				Region:       "us-east-1",
+				API: api.NewMock(mock.New200Response(
+					mock.NewStringBody(`{"id": "f1d329b0fb34470ba8b18361cabdd2bc", "resources": {}}`),
+				)),
+			}}},
+			err: errors.New("deployment f1d329b0fb34470ba8b18361cabdd2bc has no elasticsearch resources"),
+		},
+		{
+			name: "succeeds",
+			args: args{params: GetParams{Params: Params{
+				DeploymentID: localDeploymentID,
+				Region:       "us-east-1",
+				API: api.NewMock(
+					mock.New200Response(mock.NewStringBody(localDeployment)),
+					mock.New200ResponseAssertion(&mock.RequestAssertion{
+						Header: api.DefaultReadMockHeaders,
+						Method: "GET",
+						Host:   api.DefaultMockHost,
+						Path:   "/api/v1/regions/us-east-1/clusters/elasticsearch/cde7b6b605424a54ce9d56316eab13a1/ccs/settings",
+					}, mock.NewStringBody(`{"remote_clusters": {
+  "remote": {"cluster_id": "9a8b7c6d5e4f4a3b2c1d0e9f8a7b6c5d", "skip_unavailable": true}
+}}`)),
+				),
+			}}},
+			want: &models.CrossClusterSearchSettings{RemoteClusters: map[string]models.RemoteClusterRef{
+				"remote": {ClusterID: ec.String(remoteClusterID), SkipUnavailable: ec.Bool(true)},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetSettings(tt.args.params)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestListReferrers(t *testing.T) {
+	type args struct {
+		params GetParams
+	}
+	tests := []struct {
+		name string
+		args args
+		want []string
+		err  error
+	}{
+		{
+			name: "fails due to parameter validation",
+			err: multierror.NewPrefixed("deployment ccs get",
+				apierror.ErrMissingAPI,
+				errors.New(`id "" is invalid`),
+				errors.New("region not specified and is required for this operation"),
+			),
+		},
+		{
+			name: "fails obtaining a referrer cluster",
+			args: args{params: GetParams{Params: Params{
+				DeploymentID: remoteDeploymentID,
+				Region:       "us-east-1",
+				API: api.NewMock(
+					mock.New200Response(mock.NewStringBody(remoteDeployment)),
+					mock.New200Response(mock.NewStringBody(`{"ccs_clusters": ["cde7b6b605424a54ce9d56316eab13a1"]}`)),
+					mock.Response{Error: errors.New("error")},
+				),
+			}}},
+			err: &url.Error{
+				Op:  "Get",
+				URL: "https://mock.elastic.co/api/v1/regions/us-east-1/clusters/elasticsearch/cde7b6b605424a54ce9d56316eab13a1?convert_legacy_plans=false&enrich_with_template=false&show_metadata=false&show_plan_defaults=false&show_plan_logs=false&show_plans=false&show_security=false&show_settings=false&show_system_alerts=0",
+				Err: errors.New("error"),
+			},
+		},
+		{
+			name: "succeeds",
+			args: args{params: GetParams{Params: Params{
+				DeploymentID: remoteDeploymentID,
+				Region:       "us-east-1",
+				API: api.NewMock(
+					mock.New200Response(mock.NewStringBody(remoteDeployment)),
+					mock.New200ResponseAssertion(&mock.RequestAssertion{
+						Header: api.DefaultReadMockHeaders,
+						Method: "GET",
+						Host:   api.DefaultMockHost,
+						Path:   "/api/v1/regions/us-east-1/clusters/elasticsearch/9a8b7c6d5e4f4a3b2c1d0e9f8a7b6c5d/ccs",
+					}, mock.NewStringBody(`{"ccs_clusters": [
+  "cde7b6b605424a54ce9d56316eab13a1", "0b1c2d3e4f5a4b6c7d8e9f0a1b2c3d4e"
+]}`)),
+					mock.New200Response(mock.NewStringBody(`{"cluster_id": "cde7b6b605424a54ce9d56316eab13a1", "deployment_id": "f1d329b0fb34470ba8b18361cabdd2bc"}`)),
+					mock.New200Response(mock.NewStringBody(`{"cluster_id": "0b1c2d3e4f5a4b6c7d8e9f0a1b2c3d4e", "deployment_id": "0dd8b4b2f7c64fdb9e2bb1f1b8a1c0e0"}`)),
+				),
+			}}},
+			want: []string{
+				"0dd8b4b2f7c64fdb9e2bb1f1b8a1c0e0",
+				localDeploymentID,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ListReferrers(tt.args.params)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestListEligibleRemotes(t *testing.T) {
+	type args struct {
+		params ListEligibleRemotesParams
+	}
+	tests := []struct {
+		name string
+		args args
+		want *models.ElasticsearchClustersInfo
+		err  error
+	}{
+		{
+			name: "fails due to parameter validation",
+			err: multierror.NewPrefixed("deployment ccs list eligible remotes",
+				apierror.ErrMissingAPI,
+				errors.New(`id "" is invalid`),
+				errors.New("region not specified and is required for this operation"),
+			),
+		},
+		{
+			name: "fails when the deployment version cannot be obtained",
+			args: args{params: ListEligibleRemotesParams{Params: Params{
+				DeploymentID: localDeploymentID,
+				Region:       "us-east-1",
+				API: api.NewMock(mock.New200Response(
+					mock.NewStringBody(newDeploymentResponse(localDeploymentID, localClusterID, "")),
+				)),
+			}}},
+			err: errors.New("unable to obtain the elasticsearch version for deployment f1d329b0fb34470ba8b18361cabdd2bc"),
+		},
+		{
+			name: "succeeds",
+			args: args{params: ListEligibleRemotesParams{
+				Params: Params{
+					DeploymentID: localDeploymentID,
+					Region:       "us-east-1",
+					API: api.NewMock(
+						mock.New200Response(mock.NewStringBody(localDeployment)),
+						mock.New200ResponseAssertion(&mock.RequestAssertion{
+							Header: api.DefaultReadMockHeaders,
+							Method: "GET",
+							Host:   api.DefaultMockHost,
+							Path:   "/api/v1/regions/us-east-1/clusters/elasticsearch/ccs/eligible_remotes",
+							Query: url.Values{
+								"q":       {"my"},
+								"size":    {"10"},
+								"version": {"7.8.0"},
+							},
+						}, mock.NewStringBody(`{"return_count": 1, "elasticsearch_clusters": [
+  {"cluster_id": "9a8b7c6d5e4f4a3b2c1d0e9f8a7b6c5d", "cluster_name": "my-remote"}
+]}`)),
+					),
+				},
+				Query: "my",
+				Size:  10,
+			}},
+			want: &models.ElasticsearchClustersInfo{
+				ReturnCount: ec.Int32(1),
+				ElasticsearchClusters: []*models.ElasticsearchClusterInfo{{
+					ClusterID:   ec.String(remoteClusterID),
+					ClusterName: ec.String("my-remote"),
+				}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ListEligibleRemotes(tt.args.params)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,99 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ccsapi
+
+import (
+	"fmt"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/api/deploymentapi"
+	"github.com/elastic/cloud-sdk-go/pkg/api/deploymentapi/deputil"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+// Params is embedded in all of the cross-cluster search parameter structures,
+// it contains the fields which are required to locate the deployment.
+type Params struct {
+	*api.API
+
+	DeploymentID string
+	Region       string
+}
+
+// errors returns a list of errors for any parameters which aren't set or are
+// invalid.
+func (params Params) errors() []error {
+	var errs []error
+	if params.API == nil {
+		errs = append(errs, apierror.ErrMissingAPI)
+	}
+
+	if len(params.DeploymentID) != 32 {
+		errs = append(errs, deputil.NewInvalidDeploymentIDError(params.DeploymentID))
+	}
+
+	if err := ec.RequireRegionSet(params.Region); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errs
+}
+
+// elasticsearch contains the information of a deployment's Elasticsearch
+// resource which is needed for cross-cluster search operations.
+type elasticsearch struct {
+	DeploymentID string
+	ClusterID    string
+	Version      string
+}
+
+// getElasticsearch resolves the Elasticsearch resource ID and version from a
+// deployment ID. The version is left empty when the resource has no current
+// plan.
+func getElasticsearch(params Params, deploymentID string) (elasticsearch, error) {
+	res, err := deploymentapi.Get(deploymentapi.GetParams{
+		API:          params.API,
+		DeploymentID: deploymentID,
+		QueryParams:  deputil.QueryParams{ShowPlans: true},
+	})
+	if err != nil {
+		return elasticsearch{}, err
+	}
+
+	if res.Resources == nil || len(res.Resources.Elasticsearch) == 0 {
+		return elasticsearch{}, fmt.Errorf(
+			"deployment %s has no elasticsearch resources", deploymentID,
+		)
+	}
+
+	var resource = res.Resources.Elasticsearch[0]
+	var es = elasticsearch{
+		DeploymentID: deploymentID,
+		ClusterID:    *resource.ID,
+	}
+
+	if info := resource.Info; info != nil && info.PlanInfo != nil {
+		var current = info.PlanInfo.Current
+		if current != nil && current.Plan != nil && current.Plan.Elasticsearch != nil {
+			es.Version = current.Plan.Elasticsearch.Version
+		}
+	}
+
+	return es, nil
+}
@@ -0,0 +1,242 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ccsapi
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api/deploymentapi/deputil"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+// aliasRegex matches the valid remote cluster aliases, which must only
+// contain letters, digits, dashes and underscores.
+var aliasRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// RemoteCluster represents a remote deployment which is used for cross-cluster
+// search.
+type RemoteCluster struct {
+	// Alias by which the remote cluster is referenced in search requests.
+	Alias string
+
+	// DeploymentID of the remote deployment.
+	DeploymentID string
+
+	// SkipUnavailable when set, skips the remote cluster during searches if
+	// it's disconnected.
+	SkipUnavailable bool
+}
+
+// AddRemoteParams is consumed by the AddRemote function.
+type AddRemoteParams struct {
+	Params
+
+	// Remotes to add to the deployment. Any existing remote with the same
+	// alias is replaced.
+	Remotes []RemoteCluster
+}
+
+// Validate ensures the parameters are usable by the consuming function.
+func (params AddRemoteParams) Validate() error {
+	var merr = multierror.NewPrefixed("deployment ccs add remote",
+		params.errors()...,
+	)
+
+	if len(params.Remotes) == 0 {
+		merr = merr.Append(errors.New("at least one remote cluster must be specified"))
+	}
+
+	var aliases = make(map[string]struct{}, len(params.Remotes))
+	for _, remote := range params.Remotes {
+		if !aliasRegex.MatchString(remote.Alias) {
+			merr = merr.Append(fmt.Errorf(
+				`alias "%s" is invalid, it must only contain letters, digits, dashes and underscores`,
+				remote.Alias,
+			))
+		}
+
+		if _, ok := aliases[remote.Alias]; ok {
+			merr = merr.Append(fmt.Errorf(`alias "%s" is specified more than once`, remote.Alias))
+		}
+		aliases[remote.Alias] = struct{}{}
+
+		if len(remote.DeploymentID) != 32 {
+			merr = merr.Append(deputil.NewInvalidDeploymentIDError(remote.DeploymentID))
+		}
+
+		if remote.DeploymentID == params.DeploymentID {
+			merr = merr.Append(errors.New("a deployment cannot be a remote of itself"))
+		}
+	}
+
+	return merr.ErrorOrNil()
+}
+
+// AddRemote adds one or more remote deployments to the cross-cluster search
+// settings of a deployment. Before any changes are applied, the remotes are
+// checked to be compatible with the deployment's Elasticsearch version. The
+// resulting cross-cluster search settings are returned.
+func AddRemote(params AddRemoteParams) (*models.CrossClusterSearchSettings, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	local, err := getElasticsearch(params.Params, params.DeploymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := requireVersion(local); err != nil {
+		return nil, err
+	}
+
+	settings, err := getSettings(params.Params, local.ClusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	var merr = multierror.NewPrefixed("deployment ccs add remote")
+	var refs = make(map[string]models.RemoteClusterRef, len(params.Remotes))
+	for _, remote := range params.Remotes {
+		es, err := getElasticsearch(params.Params, remote.DeploymentID)
+		if err != nil {
+			merr = merr.Append(err)
+			continue
+		}
+
+		if err := checkCompatibility(params.Params, local, es); err != nil {
+			merr = merr.Append(err)
+			continue
+		}
+
+		refs[remote.Alias] = models.RemoteClusterRef{
+			ClusterID:       ec.String(es.ClusterID),
+			SkipUnavailable: ec.Bool(remote.SkipUnavailable),
+		}
+	}
+
+	if err := merr.ErrorOrNil(); err != nil {
+		return nil, err
+	}
+
+	if settings.RemoteClusters == nil {
+		settings.RemoteClusters = make(map[string]models.RemoteClusterRef, len(refs))
+	}
+	for alias, ref := range refs {
+		settings.RemoteClusters[alias] = ref
+	}
+
+	if err := setSettings(params.Params, local.ClusterID, settings); err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+// RemoveRemoteParams is consumed by the RemoveRemote function.
+type RemoveRemoteParams struct {
+	Params
+
+	// Aliases of the remote clusters to remove.
+	Aliases []string
+}
+
+// Validate ensures the parameters are usable by the consuming function.
+func (params RemoveRemoteParams) Validate() error {
+	var merr = multierror.NewPrefixed("deployment ccs remove remote",
+		params.errors()...,
+	)
+
+	if len(params.Aliases) == 0 {
+		merr = merr.Append(errors.New("at least one remote cluster alias must be specified"))
+	}
+
+	return merr.ErrorOrNil()
+}
+
+// RemoveRemote removes one or more remote clusters by alias from the
+// cross-cluster search settings of a deployment. The resulting cross-cluster
+// search settings are returned.
+func RemoveRemote(params RemoveRemoteParams) (*models.CrossClusterSearchSettings, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	local, err := getElasticsearch(params.Params, params.DeploymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := getSettings(params.Params, local.ClusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	var merr = multierror.NewPrefixed("deployment ccs remove remote")
+	for _, alias := range params.Aliases {
+		if _, ok := settings.RemoteClusters[alias]; !ok {
+			merr = merr.Append(fmt.Errorf(`remote cluster alias "%s" not found`, alias))
+			continue
+		}
+		delete(settings.RemoteClusters, alias)
+	}
+
+	if err := merr.ErrorOrNil(); err != nil {
+		return nil, err
+	}
+
+	if err := setSettings(params.Params, local.ClusterID, settings); err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+// checkCompatibility ensures that the remote Elasticsearch cluster is eligible
+// to be used as a cross-cluster search remote by the local cluster, relying on
+// the API's version compatibility rules.
+func checkCompatibility(params Params, local, remote elasticsearch) error {
+	res, err := listEligibleRemotes(params, local.Version, remote.ClusterID, 0)
+	if err != nil {
+		return err
+	}
+
+	for _, cluster := range res.ElasticsearchClusters {
+		if cluster.ClusterID != nil && *cluster.ClusterID == remote.ClusterID {
+			return nil
+		}
+	}
+
+	return fmt.Errorf(
+		"remote deployment %s (version %s) is not compatible with deployment %s (version %s)",
+		remote.DeploymentID, remote.Version, local.DeploymentID, local.Version,
+	)
+}
+
+func requireVersion(es elasticsearch) error {
+	if es.Version == "" {
+		return fmt.Errorf(
+			"unable to obtain the elasticsearch version for deployment %s", es.DeploymentID,
+		)
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ccsapi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/api/mock"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+const ccsSettingsPath = "/api/v1/regions/us-east-1/clusters/elasticsearch/cde7b6b605424a54ce9d56316eab13a1/ccs/settings"
+
+func TestAddRemote(t *testing.T) {
+	type args struct {
+		params AddRemoteParams
+	}
+	tests := []struct {
+		name string
+		args args
+		want *models.CrossClusterSearchSettings
+		err  error
+	}{
+		{
+			name: "fails due to parameter validation",
+			err: multierror.NewPrefixed("deployment ccs add remote",
+				apierror.ErrMissingAPI,
+				errors.New(`id "" is invalid`),
+				errors.New("region not specified and is required for this operation"),
+				errors.New("at least one remote cluster must be specified"),
+			),
+		},
+		{
+			name: "fails due to invalid remotes",
+			args: args{params: AddRemoteParams{
+				Params: Params{
+					API:          api.NewMock(),
+					DeploymentID: localDeploymentID,
+					Region:       "us-east-1",
+				},
+				Remotes: []RemoteCluster{
+					{Alias: "my remote", DeploymentID: remoteDeploymentID},
+					{Alias: "other", DeploymentID: "invalid"},
+					{Alias: "other", DeploymentID: localDeploymentID},
+				},
+			}},
+			err: multierror.NewPrefixed("deployment ccs add remote",
+				errors.New(`alias "my remote" is invalid, it must only contain letters, digits, dashes and underscores`),
+				errors.New(`id "invalid" is invalid`),
+				errors.New(`alias "other" is specified more than once`),
+				errors.New("a deployment cannot be a remote of itself"),
+			),
+		},
+		{
+			name: "fails when the remote is not compatible",
+			args: args{params: AddRemoteParams{
+				Params: Params{
+					DeploymentID: localDeploymentID,
+					Region:       "us-east-1",
+					API: api.NewMock(
+						mock.New200Response(mock.NewStringBody(localDeployment)),
+						mock.New200Response(mock.NewStringBody(`{"remote_clusters": {}}`)),
+						mock.New200Response(mock.NewStringBody(
+							newDeploymentResponse(remoteDeploymentID, remoteClusterID, "6.5.0"),
+						)),
+						mock.New200Response(mock.NewStringBody(`{"return_count": 0, "elasticsearch_clusters": []}`)),
+					),
+				},
+				Remotes: []RemoteCluster{
+					{Alias: "remote", DeploymentID: remoteDeploymentID},
+				},
+			}},
+			err: multierror.NewPrefixed("deployment ccs add remote",
+				errors.New("remote deployment a4b3e2c1d0f94e8b8a7c6d5e4f3a2b1c (version 6.5.0) is not compatible with deployment f1d329b0fb34470ba8b18361cabdd2bc (version 7.8.0)"),
+			),
+		},
+		{
+			name: "adds a remote to the existing ones",
+			args: args{params: AddRemoteParams{
+				Params: Params{
+					DeploymentID: localDeploymentID,
+					Region:       "us-east-1",
+					API: api.NewMock(
+						mock.New200Response(mock.NewStringBody(localDeployment)),
+						mock.New200Response(mock.NewStringBody(`{"remote_clusters": {
+  "existing": {"cluster_id": "0b1c2d3e4f5a4b6c7d8e9f0a1b2c3d4e"}
+}}`)),
+						mock.New200Response(mock.NewStringBody(remoteDeployment)),
+						mock.New200Response(mock.NewStringBody(`{"return_count": 1, "elasticsearch_clusters": [
+  {"cluster_id": "9a8b7c6d5e4f4a3b2c1d0e9f8a7b6c5d"}
+]}`)),
+						mock.New202ResponseAssertion(&mock.RequestAssertion{
+							Header: api.DefaultWriteMockHeaders,
+							Method: "PUT",
+							Host:   api.DefaultMockHost,
+							Path:   ccsSettingsPath,
+							Body:   mock.NewStringBody(`{"remote_clusters":{"existing":{"cluster_id":"0b1c2d3e4f5a4b6c7d8e9f0a1b2c3d4e"},"remote":{"cluster_id":"9a8b7c6d5e4f4a3b2c1d0e9f8a7b6c5d","skip_unavailable":true}}}` + "\n"),
+						}, mock.NewStringBody(`{}`)),
+					),
+				},
+				Remotes: []RemoteCluster{
+					{Alias: "remote", DeploymentID: remoteDeploymentID, SkipUnavailable: true},
+				},
+			}},
+			want: &models.CrossClusterSearchSettings{RemoteClusters: map[string]models.RemoteClusterRef{
+				"existing": {ClusterID: ec.String("0b1c2d3e4f5a4b6c7d8e9f0a1b2c3d4e")},
+				"remote": {
+					ClusterID:       ec.String(remoteClusterID),
+					SkipUnavailable: ec.Bool(true),
+				},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := AddRemote(tt.args.params)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRemoveRemote(t *testing.T) {
+	const currentSettings = `{"remote_clusters": {
+  "existing": {"cluster_id": "0b1c2d3e4f5a4b6c7d8e9f0a1b2c3d4e"},
+  "remote": {"cluster_id": "9a8b7c6d5e4f4a3b2c1d0e9f8a7b6c5d", "skip_unavailable": true}
+}}`
+	type args struct {
+		params RemoveRemoteParams
+	}
+	tests := []struct {
+		name string
+		args args
+		want *models.CrossClusterSearchSettings
+		err  error
+	}{
+		{
+			name: "fails due to parameter validation",
+			err: multierror.NewPrefixed("deployment ccs remove remote",
+				apierror.ErrMissingAPI,
+				errors.New(`id "" is invalid`),
+				errors.New("region not specified and is required for this operation"),
+				errors.New("at least one remote cluster alias must be specified"),
+			),
+		},
+		{
+			name: "fails when an alias doesn't exist",
+			args: args{params: RemoveRemoteParams{
+				Params: Params{
+					DeploymentID: localDeploymentID,
+					Region:       "us-east-1",
+					API: api.NewMock(
+						mock.New200Response(mock.NewStringBody(localDeployment)),
+						mock.New200Response(mock.NewStringBody(currentSettings)),
+					),
+				},
+				Aliases: []string{"remote", "unknown"},
+			}},
+			err: multierror.NewPrefixed("deployment ccs remove remote",
+				errors.New(`remote cluster alias "unknown" not found`),
+			),
+		},
+		{
+			name: "removes a remote",
+			args: args{params: RemoveRemoteParams{
+				Params: Params{
+					DeploymentID: localDeploymentID,
+					Region:       "us-east-1",
+					API: api.NewMock(
+						mock.New200Response(mock.NewStringBody(localDeployment)),
+						mock.New200Response(mock.NewStringBody(currentSettings)),
+						mock.New202ResponseAssertion(&mock.RequestAssertion{
+							Header: api.DefaultWriteMockHeaders,
+							Method: "PUT",
+							Host:   api.DefaultMockHost,
+							Path:   ccsSettingsPath,
+							Body:   mock.NewStringBody(`{"remote_clusters":{"existing":{"cluster_id":"0b1c2d3e4f5a4b6c7d8e9f0a1b2c3d4e"}}}` + "\n"),
+						}, mock.NewStringBody(`{}`)),
+					),
+				},
+				Aliases: []string{"remote"},
+			}},
+			want: &models.CrossClusterSearchSettings{RemoteClusters: map[string]models.RemoteClusterRef{
+				"existing": {ClusterID: ec.String("0b1c2d3e4f5a4b6c7d8e9f0a1b2c3d4e")},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RemoveRemote(tt.args.params)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
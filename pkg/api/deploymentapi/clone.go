@@ -0,0 +1,242 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package deploymentapi
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/api/deploymentapi/deputil"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/plan"
+	"github.com/elastic/cloud-sdk-go/pkg/resourcekind"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+// LatestSuccessfulSnapshot is the snapshot name which restores the latest
+// successful snapshot of the source cluster.
+const LatestSuccessfulSnapshot = "__latest_success__"
+
+// CloneParams is consumed by Clone.
+type CloneParams struct {
+	// API used to obtain the source deployment.
+	*api.API
+
+	// DeploymentID of the source deployment.
+	DeploymentID string
+
+	// Target API where the clone is created, defaults to API when empty,
+	// which allows cloning deployments across ECE installations.
+	Target *api.API
+
+	// Region of the cloned resources, when empty the regions of the source
+	// deployment resources are kept.
+	Region string
+
+	// Name of the cloned deployment, defaults to the source deployment name.
+	Name string
+
+	// TemplateID of the deployment template used by the cloned deployment,
+	// defaults to the template used by the source deployment.
+	TemplateID string
+
+	// InstanceConfigurations maps the instance configuration IDs of the
+	// source deployment to the ones in the target region.
+	InstanceConfigurations map[string]string
+
+	// RestoreLatestSnapshot restores the latest successful snapshot of each
+	// of the source Elasticsearch resources into the cloned ones. The source
+	// snapshot repository needs to be reachable from the target region.
+	RestoreLatestSnapshot bool
+
+	// RequestID is used as the idempotency key of the create request.
+	RequestID string
+
+	// TrackFrequency controls how often the clone plan changes are polled.
+	TrackFrequency plan.TrackFrequencyConfig
+
+	// Writer where the plan tracking progress is written, the progress is
+	// discarded when empty.
+	Writer io.Writer
+}
+
+// Validate ensures the parameters are usable.
+func (params CloneParams) Validate() error {
+	var merr = multierror.NewPrefixed("deployment clone")
+	if params.API == nil {
+		merr = merr.Append(apierror.ErrMissingAPI)
+	}
+
+	if len(params.DeploymentID) != 32 {
+		merr = merr.Append(deputil.NewInvalidDeploymentIDError(params.DeploymentID))
+	}
+
+	for from, to := range params.InstanceConfigurations {
+		if from == "" || to == "" {
+			merr = merr.Append(fmt.Errorf(
+				`invalid instance configuration mapping "%s" to "%s"`, from, to,
+			))
+		}
+	}
+
+	return merr.ErrorOrNil()
+}
+
+func (params *CloneParams) fillDefaults() {
+	if params.Target == nil {
+		params.Target = params.API
+	}
+
+	if params.Writer == nil {
+		params.Writer = ioutil.Discard
+	}
+}
+
+// CloneResponse is returned by Clone.
+type CloneResponse struct {
+	// Request used to create the cloned deployment.
+	Request *models.DeploymentCreateRequest
+
+	// Response of the create request, contains the cloned deployment ID and
+	// its credentials.
+	Response *models.DeploymentCreateResponse
+}
+
+// Clone creates a copy of a deployment out of its current plans, optionally
+// in a different region or ECE installation, and tracks the creation until
+// its plans have finished. Settings referencing other clusters by their ID
+// aren't cloned. When RestoreLatestSnapshot is set, the cloned Elasticsearch
+// resources are restored from the latest successful snapshot of the source
+// ones. When the clone has been created but its plan tracking fails, both
+// the response and the error are returned.
+func Clone(params CloneParams) (*CloneResponse, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	params.fillDefaults()
+
+	source, err := Get(GetParams{
+		API: params.API, DeploymentID: params.DeploymentID,
+		QueryParams: deputil.QueryParams{
+			ShowPlans: true, ShowSettings: true, ShowMetadata: true,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := NewCloneRequest(source, params)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := Create(CreateParams{
+		API: params.Target, Request: req, RequestID: params.RequestID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var response = CloneResponse{Request: req, Response: res}
+	channel, err := plan.TrackChange(plan.TrackChangeParams{
+		API: params.Target, DeploymentID: stringValue(res.ID),
+		Config: params.TrackFrequency,
+	})
+	if err != nil {
+		return &response, err
+	}
+
+	return &response, plan.Stream(channel, params.Writer)
+}
+
+// NewCloneRequest returns the create request used by Clone to copy the source
+// deployment.
+func NewCloneRequest(source *models.DeploymentGetResponse, params CloneParams) (*models.DeploymentCreateRequest, error) {
+	req, err := NewCreateRequest(source, ConvertOptions{
+		DropTransient: true, StripClusterIDs: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if params.Name != "" {
+		req.Name = params.Name
+	}
+
+	var sourceIDs = make(map[string]string)
+	for _, r := range source.Resources.Elasticsearch {
+		sourceIDs[stringValue(r.RefID)] = stringValue(r.ID)
+	}
+
+	for _, r := range req.Resources.Elasticsearch {
+		if r.Plan == nil {
+			return nil, errors.New("deployment clone: elasticsearch resource has no plan")
+		}
+
+		if params.TemplateID != "" {
+			r.Plan.DeploymentTemplate = &models.DeploymentTemplateReference{
+				ID: ec.String(params.TemplateID),
+			}
+		}
+
+		if params.RestoreLatestSnapshot {
+			r.Plan.Transient = &models.TransientElasticsearchPlanConfiguration{
+				RestoreSnapshot: &models.RestoreSnapshotConfiguration{
+					SnapshotName:    ec.String(LatestSuccessfulSnapshot),
+					SourceClusterID: sourceIDs[stringValue(r.RefID)],
+				},
+			}
+		}
+	}
+
+	for _, kind := range resourcekind.All() {
+		for _, payload := range kind.Payloads(req.Resources) {
+			for _, t := range kind.Topology(payload) {
+				resourcekind.SetInstanceConfigurationID(t, mapInstanceConfiguration(
+					resourcekind.InstanceConfigurationID(t), params.InstanceConfigurations,
+				))
+			}
+		}
+	}
+
+	if params.Region != "" {
+		setRegion(req.Resources, params.Region)
+	}
+
+	return req, nil
+}
+
+func mapInstanceConfiguration(id string, mapping map[string]string) string {
+	if to, ok := mapping[id]; ok {
+		return to
+	}
+	return id
+}
+
+func setRegion(resources *models.DeploymentCreateResources, region string) {
+	for _, kind := range resourcekind.All() {
+		for _, payload := range kind.Payloads(resources) {
+			kind.ReplaceRegion(payload, region)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package deploymentapi
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/api/deploymentapi/deputil"
+	"github.com/elastic/cloud-sdk-go/pkg/api/mock"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/plan"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+func TestClone(t *testing.T) {
+	const cloneID = "e3dac8bf3dc64c528c295a94d0f19a77"
+	var sourceResponse = func() mock.Response {
+		return mock.New200Response(mock.NewStructBody(newConvertDeployment()))
+	}
+	var trackResponse = func() mock.Response {
+		return mock.New200Response(mock.NewStructBody(models.DeploymentGetResponse{
+			ID:        ec.String(cloneID),
+			Resources: &models.DeploymentResources{},
+		}))
+	}
+	var cloneRequest = &models.DeploymentCreateRequest{
+		Name: "my-clone",
+		Settings: &models.DeploymentCreateSettings{
+			IPFilteringSettings: &models.IPFilteringSettings{Rulesets: []string{"office"}},
+		},
+		Resources: &models.DeploymentCreateResources{
+			Elasticsearch: []*models.ElasticsearchPayload{{
+				DisplayName: "my-cluster",
+				RefID:       ec.String("es"),
+				Region:      ec.String("eu-west-1"),
+				Settings:    &models.ElasticsearchClusterSettings{DedicatedMastersThreshold: 6},
+				Plan: &models.ElasticsearchClusterPlan{
+					DeploymentTemplate: &models.DeploymentTemplateReference{
+						ID: ec.String("aws-io-optimized"),
+					},
+					Elasticsearch: &models.ElasticsearchConfiguration{Version: "7.10.0"},
+					ClusterTopology: []*models.ElasticsearchClusterTopologyElement{{
+						InstanceConfigurationID: "aws.data.highio.i3",
+						ZoneCount:               2,
+						Size: &models.TopologySize{
+							Resource: ec.String("memory"), Value: ec.Int32(1024),
+						},
+					}},
+					Transient: &models.TransientElasticsearchPlanConfiguration{
+						RestoreSnapshot: &models.RestoreSnapshotConfiguration{
+							SnapshotName:    ec.String("__latest_success__"),
+							SourceClusterID: "3ee11eb40eda22cac0cce259625c6734",
+						},
+					},
+				},
+			}},
+			Kibana: []*models.KibanaPayload{{
+				DisplayName:               "my-kibana",
+				RefID:                     ec.String("kb"),
+				ElasticsearchClusterRefID: ec.String("es"),
+				Region:                    ec.String("eu-west-1"),
+				Plan: &models.KibanaClusterPlan{
+					Kibana: &models.KibanaConfiguration{Version: "7.10.0"},
+				},
+			}},
+		},
+	}
+	var cloneResponse = &models.DeploymentCreateResponse{
+		ID: ec.String(cloneID), Name: ec.String("my-clone"), Created: ec.Bool(true),
+	}
+	var trackFrequency = plan.TrackFrequencyConfig{
+		PollFrequency: time.Millisecond, MaxRetries: 1,
+	}
+
+	tests := []struct {
+		name   string
+		params CloneParams
+		want   *CloneResponse
+		err    string
+	}{
+		{
+			name: "fails due to parameter validation",
+			params: CloneParams{
+				InstanceConfigurations: map[string]string{"data.default": ""},
+			},
+			err: multierror.NewPrefixed("deployment clone",
+				apierror.ErrMissingAPI,
+				deputil.NewInvalidDeploymentIDError(""),
+				errors.New(`invalid instance configuration mapping "data.default" to ""`),
+			).Error(),
+		},
+		{
+			name: "fails obtaining the source deployment",
+			params: CloneParams{
+				API:          api.NewMock(mock.New500Response(mock.NewStringBody(`{"error": "some error"}`))),
+				DeploymentID: "f1d329b0fb34470ba8b18361cabdd2bc",
+			},
+			err: `{"error": "some error"}`,
+		},
+		{
+			name: "fails creating the clone",
+			params: CloneParams{
+				API:          api.NewMock(sourceResponse()),
+				Target:       api.NewMock(mock.New500Response(mock.NewStringBody(`{"error": "some error"}`))),
+				DeploymentID: "f1d329b0fb34470ba8b18361cabdd2bc",
+			},
+			err: `{"error": "some error"}`,
+		},
+		{
+			name: "clones the deployment into another region restoring its latest snapshot",
+			params: CloneParams{
+				API: api.NewMock(sourceResponse()),
+				Target: api.NewMock(
+					mock.Response{
+						Response: mock.New201Response(mock.NewStructBody(cloneResponse)).Response,
+						Assert: &mock.RequestAssertion{
+							Header: api.DefaultWriteMockHeaders,
+							Method: "POST",
+							Host:   api.DefaultMockHost,
+							Path:   "/api/v1/deployments",
+							Query:  url.Values{"validate_only": {"false"}},
+							Body:   mock.NewStructBody(cloneRequest),
+						},
+					},
+					trackResponse(),
+					trackResponse(),
+				),
+				DeploymentID:           "f1d329b0fb34470ba8b18361cabdd2bc",
+				Region:                 "eu-west-1",
+				Name:                   "my-clone",
+				TemplateID:             "aws-io-optimized",
+				InstanceConfigurations: map[string]string{"data.default": "aws.data.highio.i3"},
+				RestoreLatestSnapshot:  true,
+				TrackFrequency:         trackFrequency,
+			},
+			want: &CloneResponse{Request: cloneRequest, Response: cloneResponse},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Clone(tt.params)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewCloneRequest(t *testing.T) {
+	got, err := NewCloneRequest(newConvertDeployment(), CloneParams{})
+	assert.NoError(t, err)
+	assert.Equal(t, "my-deployment", got.Name)
+
+	var es = got.Resources.Elasticsearch[0]
+	assert.Equal(t, ec.String("us-east-1"), es.Region)
+	assert.Nil(t, es.Plan.Transient)
+	assert.Nil(t, es.Plan.DeploymentTemplate)
+	assert.Nil(t, es.Settings.Ccs)
+	assert.Equal(t, "data.default", es.Plan.ClusterTopology[0].InstanceConfigurationID)
+}
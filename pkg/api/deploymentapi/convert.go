@@ -0,0 +1,206 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package deploymentapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/resourcekind"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+// ConvertOptions control how a deployment is converted into a create or
+// update request.
+type ConvertOptions struct {
+	// DropTransient removes the transient settings of the resource plans,
+	// such as the plan strategy or the snapshot to restore.
+	DropTransient bool
+
+	// StripClusterIDs removes the settings which reference other clusters
+	// by their ID: cross-cluster search remotes, the monitoring target
+	// cluster and the source cluster of the snapshot to restore.
+	StripClusterIDs bool
+
+	// ResetRefIDs replaces the resource RefIDs with the default ones, the
+	// first resource of each kind is named main-<kind> and the subsequent
+	// ones main-<kind>-<index>. Elasticsearch RefID references are updated.
+	ResetRefIDs bool
+}
+
+// NewCreateRequest returns a deployment create request out of the current
+// plans and settings of the deployment resources. The deployment response
+// needs to have been obtained with the plans and settings shown. Since system
+// ownership is managed by the platform, it's never part of the request.
+func NewCreateRequest(res *models.DeploymentGetResponse, opts ConvertOptions) (*models.DeploymentCreateRequest, error) {
+	resources, err := convertResources(res, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var req = models.DeploymentCreateRequest{
+		Name:      stringValue(res.Name),
+		Resources: newCreateResources(resources),
+	}
+
+	if res.Settings != nil && res.Settings.IPFilteringSettings != nil {
+		req.Settings = &models.DeploymentCreateSettings{
+			IPFilteringSettings: res.Settings.IPFilteringSettings,
+		}
+	}
+
+	return &req, nil
+}
+
+// NewUpdateRequest returns a deployment update request out of the current
+// plans of the deployment resources, which can be modified and sent back to
+// update the deployment. Orphaned resources aren't pruned.
+func NewUpdateRequest(res *models.DeploymentGetResponse, opts ConvertOptions) (*models.DeploymentUpdateRequest, error) {
+	resources, err := convertResources(res, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var req = models.DeploymentUpdateRequest{
+		Name:         stringValue(res.Name),
+		PruneOrphans: ec.Bool(false),
+		Resources:    resources,
+	}
+
+	if res.Metadata != nil && (res.Metadata.Hidden != nil || res.Metadata.SystemOwned != nil) {
+		req.Metadata = &models.DeploymentUpdateMetadata{
+			Hidden:      res.Metadata.Hidden,
+			SystemOwned: res.Metadata.SystemOwned,
+		}
+	}
+
+	return &req, nil
+}
+
+func convertResources(res *models.DeploymentGetResponse, opts ConvertOptions) (*models.DeploymentUpdateResources, error) {
+	if res == nil || res.Resources == nil {
+		return nil, errors.New("deployment convert: deployment resources cannot be empty")
+	}
+
+	var resources models.DeploymentUpdateResources
+	for _, kind := range resourcekind.All() {
+		var payloads []interface{}
+		for _, info := range kind.Infos(res.Resources) {
+			payload, err := kind.NewPayload(info)
+			if err != nil {
+				return nil, fmt.Errorf("deployment convert: %s", err)
+			}
+			payloads = append(payloads, payload)
+		}
+		kind.SetPayloads(&resources, payloads)
+	}
+
+	// The payloads are copied so the options don't modify the deployment.
+	var copied models.DeploymentUpdateResources
+	b, err := json.Marshal(resources)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &copied); err != nil {
+		return nil, err
+	}
+
+	if opts.DropTransient {
+		dropTransient(&copied)
+	}
+
+	if opts.StripClusterIDs {
+		stripClusterIDs(&copied)
+	}
+
+	if opts.ResetRefIDs {
+		resetRefIDs(&copied)
+	}
+
+	return &copied, nil
+}
+
+func dropTransient(resources *models.DeploymentUpdateResources) {
+	for _, kind := range resourcekind.All() {
+		for _, payload := range kind.Payloads(resources) {
+			kind.DropTransient(payload)
+		}
+	}
+}
+
+func stripClusterIDs(resources *models.DeploymentUpdateResources) {
+	for _, r := range resources.Elasticsearch {
+		if r.Settings != nil {
+			r.Settings.Ccs = nil
+			r.Settings.Monitoring = nil
+		}
+
+		if r.Plan != nil && r.Plan.Transient != nil && r.Plan.Transient.RestoreSnapshot != nil {
+			r.Plan.Transient.RestoreSnapshot.SourceClusterID = ""
+		}
+	}
+}
+
+func resetRefIDs(resources *models.DeploymentUpdateResources) {
+	var elasticsearch, _ = resourcekind.Get(resourcekind.Elasticsearch)
+	var esRefIDs = make(map[string]string, len(resources.Elasticsearch))
+	for i, payload := range elasticsearch.Payloads(resources) {
+		var refID = newRefID(elasticsearch.DefaultRefID, i)
+		esRefIDs[elasticsearch.RefID(payload)] = refID
+		elasticsearch.SetRefID(payload, refID)
+	}
+
+	for _, kind := range resourcekind.All() {
+		if kind.Name == resourcekind.Elasticsearch {
+			continue
+		}
+
+		for i, payload := range kind.Payloads(resources) {
+			kind.SetRefID(payload, newRefID(kind.DefaultRefID, i))
+			if refID, ok := esRefIDs[kind.ElasticsearchRefID(payload)]; ok {
+				kind.SetElasticsearchRefID(payload, refID)
+			}
+		}
+	}
+}
+
+// newCreateResources returns the create resources with the payloads of the
+// update resources.
+func newCreateResources(resources *models.DeploymentUpdateResources) *models.DeploymentCreateResources {
+	var res models.DeploymentCreateResources
+	for _, kind := range resourcekind.All() {
+		kind.SetPayloads(&res, kind.Payloads(resources))
+	}
+	return &res
+}
+
+func newRefID(base string, index int) string {
+	if index == 0 {
+		return base
+	}
+	return fmt.Sprintf("%s-%d", base, index)
+}
+
+func stringValue(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
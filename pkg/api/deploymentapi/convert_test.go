@@ -0,0 +1,241 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package deploymentapi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+func newConvertDeployment() *models.DeploymentGetResponse {
+	return &models.DeploymentGetResponse{
+		ID:       ec.String("f1d329b0fb34470ba8b18361cabdd2bc"),
+		Name:     ec.String("my-deployment"),
+		Metadata: &models.DeploymentMetadata{SystemOwned: ec.Bool(true)},
+		Settings: &models.DeploymentSettings{
+			IPFilteringSettings: &models.IPFilteringSettings{Rulesets: []string{"office"}},
+		},
+		Resources: &models.DeploymentResources{
+			Elasticsearch: []*models.ElasticsearchResourceInfo{{
+				ID:     ec.String("3ee11eb40eda22cac0cce259625c6734"),
+				RefID:  ec.String("es"),
+				Region: ec.String("us-east-1"),
+				Info: &models.ElasticsearchClusterInfo{
+					ClusterName: ec.String("my-cluster"),
+					Settings: &models.ElasticsearchClusterSettings{
+						Ccs: &models.CrossClusterSearchSettings{
+							RemoteClusters: map[string]models.RemoteClusterRef{
+								"remote": {ClusterID: ec.String("d324608c97154bdba2dff97511d40368")},
+							},
+						},
+						Monitoring: &models.ManagedMonitoringSettings{
+							TargetClusterID: ec.String("d324608c97154bdba2dff97511d40368"),
+						},
+						DedicatedMastersThreshold: 6,
+					},
+					PlanInfo: &models.ElasticsearchClusterPlansInfo{
+						Current: &models.ElasticsearchClusterPlanInfo{
+							Plan: &models.ElasticsearchClusterPlan{
+								Elasticsearch: &models.ElasticsearchConfiguration{Version: "7.10.0"},
+								ClusterTopology: []*models.ElasticsearchClusterTopologyElement{{
+									InstanceConfigurationID: "data.default",
+									ZoneCount:               2,
+									Size: &models.TopologySize{
+										Resource: ec.String("memory"), Value: ec.Int32(1024),
+									},
+								}},
+								Transient: &models.TransientElasticsearchPlanConfiguration{
+									RestoreSnapshot: &models.RestoreSnapshotConfiguration{
+										SnapshotName:    ec.String("__latest_success__"),
+										SourceClusterID: "d324608c97154bdba2dff97511d40368",
+									},
+								},
+							},
+						},
+					},
+				},
+			}},
+			Kibana: []*models.KibanaResourceInfo{{
+				ID:                        ec.String("4ee11eb40eda22cac0cce259625c6734"),
+				RefID:                     ec.String("kb"),
+				ElasticsearchClusterRefID: ec.String("es"),
+				Region:                    ec.String("us-east-1"),
+				Info: &models.KibanaClusterInfo{
+					ClusterName: ec.String("my-kibana"),
+					PlanInfo: &models.KibanaClusterPlansInfo{
+						Current: &models.KibanaClusterPlanInfo{
+							Plan: &models.KibanaClusterPlan{
+								Kibana: &models.KibanaConfiguration{Version: "7.10.0"},
+								Transient: &models.TransientKibanaPlanConfiguration{
+									Strategy: &models.PlanStrategy{Rolling: &models.RollingStrategyConfig{}},
+								},
+							},
+						},
+					},
+				},
+			}},
+		},
+	}
+}
+
+func TestNewCreateRequest(t *testing.T) {
+	var esPayload = func(refID string, transient bool, clusterIDs bool) *models.ElasticsearchPayload {
+		var payload = models.ElasticsearchPayload{
+			DisplayName: "my-cluster",
+			RefID:       ec.String(refID),
+			Region:      ec.String("us-east-1"),
+			Settings:    &models.ElasticsearchClusterSettings{DedicatedMastersThreshold: 6},
+			Plan: &models.ElasticsearchClusterPlan{
+				Elasticsearch: &models.ElasticsearchConfiguration{Version: "7.10.0"},
+				ClusterTopology: []*models.ElasticsearchClusterTopologyElement{{
+					InstanceConfigurationID: "data.default",
+					ZoneCount:               2,
+					Size: &models.TopologySize{
+						Resource: ec.String("memory"), Value: ec.Int32(1024),
+					},
+				}},
+			},
+		}
+		if clusterIDs {
+			payload.Settings.Ccs = &models.CrossClusterSearchSettings{
+				RemoteClusters: map[string]models.RemoteClusterRef{
+					"remote": {ClusterID: ec.String("d324608c97154bdba2dff97511d40368")},
+				},
+			}
+			payload.Settings.Monitoring = &models.ManagedMonitoringSettings{
+				TargetClusterID: ec.String("d324608c97154bdba2dff97511d40368"),
+			}
+		}
+		if transient {
+			payload.Plan.Transient = &models.TransientElasticsearchPlanConfiguration{
+				RestoreSnapshot: &models.RestoreSnapshotConfiguration{
+					SnapshotName: ec.String("__latest_success__"),
+				},
+			}
+			if clusterIDs {
+				payload.Plan.Transient.RestoreSnapshot.SourceClusterID = "d324608c97154bdba2dff97511d40368"
+			}
+		}
+		return &payload
+	}
+	var kibanaPayload = func(refID, esRefID string, transient bool) *models.KibanaPayload {
+		var payload = models.KibanaPayload{
+			DisplayName:               "my-kibana",
+			RefID:                     ec.String(refID),
+			ElasticsearchClusterRefID: ec.String(esRefID),
+			Region:                    ec.String("us-east-1"),
+			Plan: &models.KibanaClusterPlan{
+				Kibana: &models.KibanaConfiguration{Version: "7.10.0"},
+			},
+		}
+		if transient {
+			payload.Plan.Transient = &models.TransientKibanaPlanConfiguration{
+				Strategy: &models.PlanStrategy{Rolling: &models.RollingStrategyConfig{}},
+			}
+		}
+		return &payload
+	}
+	var request = func(es *models.ElasticsearchPayload, kibana *models.KibanaPayload) *models.DeploymentCreateRequest {
+		return &models.DeploymentCreateRequest{
+			Name: "my-deployment",
+			Settings: &models.DeploymentCreateSettings{
+				IPFilteringSettings: &models.IPFilteringSettings{Rulesets: []string{"office"}},
+			},
+			Resources: &models.DeploymentCreateResources{
+				Elasticsearch: []*models.ElasticsearchPayload{es},
+				Kibana:        []*models.KibanaPayload{kibana},
+			},
+		}
+	}
+	tests := []struct {
+		name string
+		res  *models.DeploymentGetResponse
+		opts ConvertOptions
+		want *models.DeploymentCreateRequest
+		err  error
+	}{
+		{
+			name: "fails when the deployment has no resources",
+			res:  &models.DeploymentGetResponse{},
+			err:  errors.New("deployment convert: deployment resources cannot be empty"),
+		},
+		{
+			name: "fails when a resource has no current plan",
+			res: &models.DeploymentGetResponse{Resources: &models.DeploymentResources{
+				Apm: []*models.ApmResourceInfo{{RefID: ec.String("main-apm"), Info: &models.ApmInfo{}}},
+			}},
+			err: errors.New("deployment convert: apm resource main-apm has no current plan"),
+		},
+		{
+			name: "converts the deployment as is",
+			res:  newConvertDeployment(),
+			want: request(esPayload("es", true, true), kibanaPayload("kb", "es", true)),
+		},
+		{
+			name: "converts the deployment without transient settings",
+			res:  newConvertDeployment(),
+			opts: ConvertOptions{DropTransient: true},
+			want: request(esPayload("es", false, true), kibanaPayload("kb", "es", false)),
+		},
+		{
+			name: "converts the deployment without cluster ids and default ref ids",
+			res:  newConvertDeployment(),
+			opts: ConvertOptions{StripClusterIDs: true, ResetRefIDs: true},
+			want: request(
+				esPayload("main-elasticsearch", true, false),
+				kibanaPayload("main-kibana", "main-elasticsearch", true),
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewCreateRequest(tt.res, tt.opts)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("doesn't modify the deployment", func(t *testing.T) {
+		var res = newConvertDeployment()
+		_, err := NewCreateRequest(res, ConvertOptions{
+			DropTransient: true, StripClusterIDs: true, ResetRefIDs: true,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, newConvertDeployment(), res)
+	})
+}
+
+func TestNewUpdateRequest(t *testing.T) {
+	got, err := NewUpdateRequest(newConvertDeployment(), ConvertOptions{DropTransient: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "my-deployment", got.Name)
+	assert.Equal(t, ec.Bool(false), got.PruneOrphans)
+	assert.Equal(t, &models.DeploymentUpdateMetadata{SystemOwned: ec.Bool(true)}, got.Metadata)
+	assert.Len(t, got.Resources.Elasticsearch, 1)
+	assert.Len(t, got.Resources.Kibana, 1)
+	assert.Nil(t, got.Resources.Elasticsearch[0].Plan.Transient)
+	assert.Equal(t, ec.String("es"), got.Resources.Kibana[0].ElasticsearchClusterRefID)
+
+	_, err = NewUpdateRequest(nil, ConvertOptions{})
+	assert.EqualError(t, err, "deployment convert: deployment resources cannot be empty")
+}
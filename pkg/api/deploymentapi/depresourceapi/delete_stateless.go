@@ -18,11 +18,12 @@
 package depresourceapi
 
 import (
-	"errors"
+	"fmt"
 
 	"github.com/elastic/cloud-sdk-go/pkg/api"
 	"github.com/elastic/cloud-sdk-go/pkg/client/deployments"
 	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/resourcekind"
 )
 
 // DeleteStatelessParams is consumed by Delete
@@ -35,8 +36,8 @@ func (params *DeleteStatelessParams) Validate() error {
 	var merr = multierror.NewPrefixed("deployment resource delete")
 	merr = merr.Append(params.Params.Validate())
 
-	if params.Kind == "elasticsearch" {
-		merr = merr.Append(errors.New("resource kind \"elasticsearch\" is not supported"))
+	if kind, ok := resourcekind.Get(params.Kind); ok && !kind.Supports(resourcekind.Delete) {
+		merr = merr.Append(fmt.Errorf("resource kind \"%s\" is not supported", params.Kind))
 	}
 
 	return merr.ErrorOrNil()
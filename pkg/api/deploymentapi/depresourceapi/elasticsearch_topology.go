@@ -66,6 +66,8 @@ type BuildElasticsearchTopologyParams struct {
 
 // ElasticsearchTopologyElement is a single cluster topology element, meaning
 // a number of instances (controlled by ZoneCount) for a single NodeType.
+// Autoscaling limits and policy overrides can't be set per element, since
+// the API version the models are generated from doesn't expose autoscaling.
 type ElasticsearchTopologyElement struct {
 	// Name can be one of "data", "master" or "ml".
 	Name string `json:"name"`
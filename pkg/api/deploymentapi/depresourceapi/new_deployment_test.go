@@ -98,6 +98,41 @@ var appsearchKibanaTemplateResponse = models.DeploymentTemplateInfo{
 	},
 }
 
+var enterpriseSearchKibanaTemplateResponse = models.DeploymentTemplateInfo{
+	ID: "default",
+	ClusterTemplate: &models.DeploymentTemplateDefinitionRequest{
+		EnterpriseSearch: &models.CreateEnterpriseSearchRequest{
+			Plan: &models.EnterpriseSearchPlan{
+				ClusterTopology: []*models.EnterpriseSearchTopologyElement{
+					{
+						Size: &models.TopologySize{
+							Resource: ec.String("memory"),
+							Value:    ec.Int32(1024),
+						},
+						ZoneCount: 1,
+					},
+				},
+			},
+		},
+		Kibana: &models.CreateKibanaInCreateElasticsearchRequest{
+			Plan: &models.KibanaClusterPlan{
+				ClusterTopology: []*models.KibanaClusterTopologyElement{
+					{
+						Size: &models.TopologySize{
+							Resource: ec.String("memory"),
+							Value:    ec.Int32(1024),
+						},
+						ZoneCount: 1,
+					},
+				},
+			},
+		},
+		Plan: &models.ElasticsearchClusterPlan{
+			ClusterTopology: defaultESTopologies,
+		},
+	},
+}
+
 func TestNew(t *testing.T) {
 	type args struct {
 		params NewParams
@@ -463,6 +498,98 @@ func TestNew(t *testing.T) {
 				}},
 			}},
 		},
+		{
+			name: "Succeeds to create a deployment payload with ES, Kibana and Enterprise Search instances",
+			args: args{params: NewParams{
+				Version: "7.6.1",
+				Region:  "ece-region",
+				ElasticsearchInstance: InstanceParams{
+					RefID:     "main-elasticsearch",
+					Size:      1024,
+					ZoneCount: 1,
+				},
+				KibanaInstance: InstanceParams{
+					RefID:     "main-kibana",
+					Size:      1024,
+					ZoneCount: 1,
+				},
+				EnterpriseSearchInstance: InstanceParams{
+					RefID:     "main-enterprise_search",
+					Size:      1024,
+					ZoneCount: 1,
+				},
+				DeploymentTemplateID:   "default",
+				EnterpriseSearchEnable: true,
+				API: api.NewMock(
+					mock.New200Response(mock.NewStructBody(enterpriseSearchKibanaTemplateResponse)),
+					mock.New200Response(mock.NewStructBody(enterpriseSearchKibanaTemplateResponse)),
+					mock.New200Response(mock.NewStructBody(enterpriseSearchKibanaTemplateResponse)),
+				),
+			}},
+			want: &models.DeploymentCreateRequest{Resources: &models.DeploymentCreateResources{
+				Elasticsearch: []*models.ElasticsearchPayload{{
+					RefID:  ec.String("main-elasticsearch"),
+					Region: ec.String("ece-region"),
+					Plan: &models.ElasticsearchClusterPlan{
+						Elasticsearch: &models.ElasticsearchConfiguration{
+							Version: "7.6.1",
+						},
+						DeploymentTemplate: &models.DeploymentTemplateReference{
+							ID: ec.String("default"),
+						},
+						ClusterTopology: []*models.ElasticsearchClusterTopologyElement{{
+							ZoneCount:               1,
+							InstanceConfigurationID: "default.data",
+							Size: &models.TopologySize{
+								Resource: ec.String("memory"),
+								Value:    ec.Int32(1024),
+							},
+							NodeType: &models.ElasticsearchNodeType{
+								Data: ec.Bool(true),
+							},
+						}},
+					}},
+				},
+				Kibana: []*models.KibanaPayload{{
+					ElasticsearchClusterRefID: ec.String("main-elasticsearch"),
+					Region:                    ec.String("ece-region"),
+					RefID:                     ec.String("main-kibana"),
+					Plan: &models.KibanaClusterPlan{
+						Kibana: &models.KibanaConfiguration{
+							Version: "7.6.1",
+						},
+						ClusterTopology: []*models.KibanaClusterTopologyElement{
+							{
+								Size: &models.TopologySize{
+									Resource: ec.String("memory"),
+									Value:    ec.Int32(1024),
+								},
+								ZoneCount: 1,
+							},
+						},
+					},
+				}},
+				EnterpriseSearch: []*models.EnterpriseSearchPayload{{
+					ElasticsearchClusterRefID: ec.String("main-elasticsearch"),
+					Region:                    ec.String("ece-region"),
+					RefID:                     ec.String("main-enterprise_search"),
+					Plan: &models.EnterpriseSearchPlan{
+						EnterpriseSearch: &models.EnterpriseSearchConfiguration{
+							Version: "7.6.1",
+						},
+						ClusterTopology: []*models.EnterpriseSearchTopologyElement{
+							{
+								Size: &models.TopologySize{
+									Resource: ec.String("memory"),
+									Value:    ec.Int32(1024),
+								},
+								ZoneCount: 1,
+							},
+						},
+					},
+				}},
+			}},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
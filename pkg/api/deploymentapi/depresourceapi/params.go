@@ -51,6 +51,8 @@ func (params *Params) Validate() error {
 
 	if params.Kind == "" {
 		merr = merr.Append(errors.New("resource kind cannot be empty"))
+	} else if err := deputil.ValidateKind(params.Kind); err != nil {
+		merr = merr.Append(err)
 	}
 
 	// Ensures that RefID is either populated when the RefID isn't specified or
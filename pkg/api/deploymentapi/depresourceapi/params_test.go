@@ -63,6 +63,27 @@ func TestResourceParams_Validate(t *testing.T) {
 				RefID:        "main-elasticsearch",
 			},
 		},
+		{
+			name: "fails on an invalid resource kind",
+			fields: fields{
+				API:          api.NewMock(),
+				DeploymentID: mock.ValidClusterID,
+				Kind:         "some",
+				RefID:        "main-some",
+			},
+			err: multierror.NewPrefixed("deployment resource",
+				errors.New(`resource kind "some" is not valid, must be one of elasticsearch, kibana, apm, appsearch, enterprise_search`),
+			),
+		},
+		{
+			name: "succeeds validation on an enterprise_search resource",
+			fields: fields{
+				API:          api.NewMock(),
+				DeploymentID: mock.ValidClusterID,
+				Kind:         "enterprise_search",
+				RefID:        "main-enterprise_search",
+			},
+		},
 		{
 			name: "returns error when autodiscovery of ref-id fails",
 			fields: fields{
@@ -20,6 +20,7 @@ package depresourceapi
 import (
 	"github.com/elastic/cloud-sdk-go/pkg/api"
 	"github.com/elastic/cloud-sdk-go/pkg/client/deployments"
+	"github.com/elastic/cloud-sdk-go/pkg/resourcekind"
 )
 
 // ShutdownParams is consumed by Shutdown.
@@ -41,7 +42,9 @@ func Shutdown(params ShutdownParams) error {
 		return err
 	}
 
-	if params.Kind == "elasticsearch" {
+	// Kinds without stateless shutdown support are shut down through the
+	// Elasticsearch endpoint.
+	if kind, _ := resourcekind.Get(params.Kind); !kind.Supports(resourcekind.Shutdown) {
 		return api.ReturnErrOnly(
 			params.V1API.Deployments.ShutdownDeploymentEsResource(
 				deployments.NewShutdownDeploymentEsResourceParams().
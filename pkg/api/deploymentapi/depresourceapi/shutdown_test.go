@@ -19,6 +19,7 @@ package depresourceapi
 
 import (
 	"errors"
+	"net/url"
 	"reflect"
 	"testing"
 
@@ -155,6 +156,26 @@ func TestShutdown(t *testing.T) {
 				},
 			}},
 		},
+		{
+			name: "Succeeds on kind Enterprise Search",
+			args: args{params: ShutdownParams{
+				Params: Params{
+					API: api.NewMock(mock.New200ResponseAssertion(&mock.RequestAssertion{
+						Header: api.DefaultWriteMockHeaders,
+						Method: "POST",
+						Host:   api.DefaultMockHost,
+						Path:   "/api/v1/deployments/" + mock.ValidClusterID + "/enterprise_search/main-enterprise_search/_shutdown",
+						Query: url.Values{
+							"hide":          {"false"},
+							"skip_snapshot": {"false"},
+						},
+					}, mock.NewStructBody(struct{}{}))),
+					DeploymentID: mock.ValidClusterID,
+					RefID:        "main-enterprise_search",
+					Kind:         deputil.EnterpriseSearch,
+				},
+			}},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
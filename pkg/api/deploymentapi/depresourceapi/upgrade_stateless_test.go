@@ -19,6 +19,7 @@ package depresourceapi
 
 import (
 	"errors"
+	"net/url"
 	"reflect"
 	"testing"
 
@@ -90,6 +91,33 @@ func TestUpgradeStateless(t *testing.T) {
 				Kind:         "elasticsearch",
 			}},
 		},
+		{
+			name: "succeeds on kind enterprise_search when RefID is not set",
+			args: args{params: Params{
+				API: api.NewMock(
+					mock.New200Response(mock.NewStructBody(models.DeploymentGetResponse{
+						Healthy: ec.Bool(true),
+						ID:      ec.String(mock.ValidClusterID),
+						Resources: &models.DeploymentResources{
+							EnterpriseSearch: []*models.EnterpriseSearchResourceInfo{{
+								ID:    ec.String("3531aaf988594efa87c1aabb7caed337"),
+								RefID: ec.String("main-enterprise_search"),
+							}},
+						},
+					})),
+					mock.New202ResponseAssertion(&mock.RequestAssertion{
+						Header: api.DefaultWriteMockHeaders,
+						Method: "POST",
+						Host:   api.DefaultMockHost,
+						Path:   "/api/v1/deployments/" + mock.ValidClusterID + "/enterprise_search/main-enterprise_search/_upgrade",
+						Query:  url.Values{"validate_only": {"false"}},
+					}, mock.NewStringBody("")),
+				),
+				DeploymentID: mock.ValidClusterID,
+				Kind:         "enterprise_search",
+			}},
+			want: new(models.DeploymentResourceUpgradeResponse),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
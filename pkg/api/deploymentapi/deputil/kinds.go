@@ -17,24 +17,52 @@
 
 package deputil
 
+import (
+	"fmt"
+	"strings"
+
+	"github.com/elastic/cloud-sdk-go/pkg/resourcekind"
+)
+
+// The resource kinds are defined in the resourcekind registry, these aliases
+// are kept for compatibility.
 const (
-	// Apm kind
-	Apm = "apm"
+	// Apm kind.
+	//
+	// Deprecated: use resourcekind.Apm.
+	Apm = resourcekind.Apm
 
-	// Appsearch kind
-	Appsearch = "appsearch"
+	// Appsearch kind.
+	//
+	// Deprecated: use resourcekind.Appsearch.
+	Appsearch = resourcekind.Appsearch
 
-	// Elasticsearch kind
-	Elasticsearch = "elasticsearch"
+	// Elasticsearch kind.
+	//
+	// Deprecated: use resourcekind.Elasticsearch.
+	Elasticsearch = resourcekind.Elasticsearch
 
-	// Kibana kind
-	Kibana = "kibana"
+	// EnterpriseSearch kind.
+	//
+	// Deprecated: use resourcekind.EnterpriseSearch.
+	EnterpriseSearch = resourcekind.EnterpriseSearch
+
+	// Kibana kind.
+	//
+	// Deprecated: use resourcekind.Kibana.
+	Kibana = resourcekind.Kibana
 )
 
 // ValidTypes exposes a list of the valid Elastic Cloud workload Types.
-var ValidTypes = []string{
-	Elasticsearch,
-	Kibana,
-	Apm,
-	Appsearch,
+var ValidTypes = resourcekind.Names()
+
+// ValidateKind returns an error when the kind isn't in the resourcekind
+// registry.
+func ValidateKind(kind string) error {
+	if _, ok := resourcekind.Get(kind); !ok {
+		return fmt.Errorf(`resource kind "%s" is not valid, must be one of %s`,
+			kind, strings.Join(resourcekind.Names(), ", "),
+		)
+	}
+	return nil
 }
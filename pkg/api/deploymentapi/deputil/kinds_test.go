@@ -0,0 +1,52 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package deputil
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestValidateKind(t *testing.T) {
+	tests := []struct {
+		name string
+		kind string
+		err  error
+	}{
+		{name: "elasticsearch is valid", kind: "elasticsearch"},
+		{name: "kibana is valid", kind: "kibana"},
+		{name: "apm is valid", kind: "apm"},
+		{name: "appsearch is valid", kind: "appsearch"},
+		{name: "enterprise_search is valid", kind: "enterprise_search"},
+		{
+			name: "unknown kind is invalid",
+			kind: "some",
+			err: errors.New(
+				`resource kind "some" is not valid, must be one of elasticsearch, kibana, apm, appsearch, enterprise_search`,
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateKind(tt.kind); !reflect.DeepEqual(err, tt.err) {
+				t.Errorf("ValidateKind() error = %v, wantErr %v", err, tt.err)
+			}
+		})
+	}
+}
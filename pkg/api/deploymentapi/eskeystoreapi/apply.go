@@ -0,0 +1,195 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package eskeystoreapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+)
+
+// ApplyParams is consumed by the Apply function.
+type ApplyParams struct {
+	Params
+
+	// File is the path to a JSON file containing the desired keystore in the
+	// models.KeystoreContents format. Ignored when Contents is set.
+	File string
+
+	// Contents is the desired keystore. Takes precedence over File.
+	Contents *models.KeystoreContents
+
+	// DryRun when set, only computes the diff without applying any changes.
+	DryRun bool
+}
+
+// Validate ensures the parameters are usable by the consuming function.
+func (params ApplyParams) Validate() error {
+	var merr = multierror.NewPrefixed("deployment keystore apply",
+		params.errors()...,
+	)
+
+	if params.File == "" && params.Contents == nil {
+		merr = merr.Append(errors.New("one of file or contents must be specified"))
+	}
+
+	return merr.ErrorOrNil()
+}
+
+// Diff contains the secret names which are added, updated or removed when the
+// desired keystore is applied. Since secret values are never returned by the
+// API, any secret which is present in both keystores is considered updated.
+type Diff struct {
+	Added   []string `json:"added"`
+	Updated []string `json:"updated"`
+	Removed []string `json:"removed"`
+}
+
+// Empty returns true when the diff contains no changes.
+func (diff Diff) Empty() bool {
+	return len(diff.Added)+len(diff.Updated)+len(diff.Removed) == 0
+}
+
+// ApplyResponse is returned by the Apply function.
+type ApplyResponse struct {
+	// Diff between the current and the desired keystore.
+	Diff Diff `json:"diff"`
+
+	// Contents of the keystore after the changes have been applied. It's not
+	// populated when DryRun is set or there are no changes.
+	Contents *models.KeystoreContents `json:"contents,omitempty"`
+}
+
+// Apply converges the Elasticsearch keystore of a deployment to the desired
+// keystore: Any secrets in the desired keystore are set and any secrets which
+// aren't in the desired keystore are removed. The computed diff is returned.
+func Apply(params ApplyParams) (*ApplyResponse, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	var desired = params.Contents
+	if desired == nil {
+		contents, err := readContents(params.File)
+		if err != nil {
+			return nil, multierror.NewPrefixed("deployment keystore apply", err)
+		}
+		desired = contents
+	}
+
+	if err := validateContents(desired); err != nil {
+		return nil, err
+	}
+
+	id, err := params.elasticsearchID()
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := get(params.Params, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var response = ApplyResponse{Diff: NewDiff(current, desired)}
+	if params.DryRun || response.Diff.Empty() {
+		return &response, nil
+	}
+
+	var patch = models.KeystoreContents{
+		Secrets: make(map[string]models.KeystoreSecret, len(desired.Secrets)),
+	}
+	for name, secret := range desired.Secrets {
+		patch.Secrets[name] = secret
+	}
+	for _, name := range response.Diff.Removed {
+		patch.Secrets[name] = models.KeystoreSecret{}
+	}
+
+	res, err := set(params.Params, id, &patch)
+	if err != nil {
+		return nil, err
+	}
+	response.Contents = res
+
+	return &response, nil
+}
+
+// NewDiff computes the diff between the current and desired keystores.
+func NewDiff(current, desired *models.KeystoreContents) Diff {
+	var diff = Diff{
+		Added:   make([]string, 0),
+		Updated: make([]string, 0),
+		Removed: make([]string, 0),
+	}
+
+	var existing = make(map[string]struct{})
+	for _, name := range secretNames(current) {
+		existing[name] = struct{}{}
+	}
+
+	var wanted = make(map[string]struct{})
+	for _, name := range secretNames(desired) {
+		wanted[name] = struct{}{}
+		if _, ok := existing[name]; ok {
+			diff.Updated = append(diff.Updated, name)
+			continue
+		}
+		diff.Added = append(diff.Added, name)
+	}
+
+	for name := range existing {
+		if _, ok := wanted[name]; !ok {
+			diff.Removed = append(diff.Removed, name)
+		}
+	}
+	sort.Strings(diff.Removed)
+
+	return diff
+}
+
+func readContents(path string) (*models.KeystoreContents, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var contents models.KeystoreContents
+	if err := json.NewDecoder(f).Decode(&contents); err != nil {
+		return nil, fmt.Errorf("failed decoding keystore file %s: %w", path, err)
+	}
+
+	return &contents, nil
+}
+
+func validateContents(contents *models.KeystoreContents) error {
+	var merr = multierror.NewPrefixed("invalid desired keystore")
+	for _, name := range secretNames(contents) {
+		if contents.Secrets[name].Value == nil {
+			merr = merr.Append(fmt.Errorf("secret %s has no value", name))
+		}
+	}
+
+	return merr.ErrorOrNil()
+}
@@ -0,0 +1,96 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package eskeystoreapi
+
+import (
+	"context"
+	"sort"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/client/clusters_elasticsearch"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+)
+
+// GetParams is consumed by the Get and List functions.
+type GetParams struct {
+	Params
+}
+
+// Validate ensures the parameters are usable by the consuming function.
+func (params GetParams) Validate() error {
+	return multierror.NewPrefixed("deployment keystore get",
+		params.errors()...,
+	).ErrorOrNil()
+}
+
+// Get returns the contents of the Elasticsearch keystore for a deployment.
+// The secret values are never returned by the API, only their names and
+// whether or not they're stored as files.
+func Get(params GetParams) (*models.KeystoreContents, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	id, err := params.elasticsearchID()
+	if err != nil {
+		return nil, err
+	}
+
+	return get(params.Params, id)
+}
+
+func get(params Params, id string) (*models.KeystoreContents, error) {
+	res, err := params.V1API.ClustersElasticsearch.GetEsClusterKeystore(
+		clusters_elasticsearch.NewGetEsClusterKeystoreParams().
+			WithContext(api.WithRegion(context.Background(), params.Region)).
+			WithClusterID(id),
+		params.AuthWriter,
+	)
+	if err != nil {
+		return nil, apierror.Unwrap(err)
+	}
+
+	return res.Payload, nil
+}
+
+// List returns the sorted list of secret names which are stored in the
+// Elasticsearch keystore for a deployment.
+func List(params GetParams) ([]string, error) {
+	res, err := Get(params)
+	if err != nil {
+		return nil, err
+	}
+
+	return secretNames(res), nil
+}
+
+func secretNames(contents *models.KeystoreContents) []string {
+	var names = make([]string, 0)
+	if contents == nil {
+		return names
+	}
+
+	for name := range contents.Secrets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,172 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package eskeystoreapi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/api/mock"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+const (
+	deploymentID = "f1d329b0fb34470ba8b18361cabdd2bc"
+	clusterID    = "cde7b6b605424a54ce9d56316eab13a1"
+
+	getDeploymentResponse = `{
+  "id": "f1d329b0fb34470ba8b18361cabdd2bc",
+  "resources": {
+    "elasticsearch": [{
+      "id": "cde7b6b605424a54ce9d56316eab13a1",
+      "ref_id": "main-elasticsearch",
+      "region": "us-east-1"
+    }]
+  }
+}`
+
+	getKeystoreResponse = `{
+  "secrets": {
+    "s3.client.foobar.access_key": {"as_file": false},
+    "gcs.client.default.credentials_file": {"as_file": true}
+  }
+}`
+
+	keystorePath = "/api/v1/regions/us-east-1/clusters/elasticsearch/cde7b6b605424a54ce9d56316eab13a1/keystore"
+)
+
+func TestGet(t *testing.T) {
+	type args struct {
+		params GetParams
+	}
+	tests := []struct {
+		name string
+		args args
+		want *models.KeystoreContents
+		err  error
+	}{
+		{
+			name: "fails due to parameter validation",
+			err: multierror.NewPrefixed("deployment keystore get",
+				apierror.ErrMissingAPI,
+				errors.New(`id "" is invalid`),
+				errors.New("region not specified and is required for this operation"),
+			),
+		},
+		{
+			name: "fails due to API error",
+			args: args{params: GetParams{Params: Params{
+				DeploymentID: deploymentID,
+				Region:       "us-east-1",
+				API: api.NewMock(
+					mock.New200Response(mock.NewStringBody(getDeploymentResponse)),
+					mock.New500Response(mock.NewStringBody(`{"error": "some error"}`)),
+				),
+			}}},
+			err: errors.New(`{"error": "some error"}`),
+		},
+		{
+			name: "succeeds",
+			args: args{params: GetParams{Params: Params{
+				DeploymentID: deploymentID,
+				Region:       "us-east-1",
+				API: api.NewMock(
+					mock.New200Response(mock.NewStringBody(getDeploymentResponse)),
+					mock.New200ResponseAssertion(&mock.RequestAssertion{
+						Header: api.DefaultReadMockHeaders,
+						Method: "GET",
+						Host:   api.DefaultMockHost,
+						Path:   keystorePath,
+					}, mock.NewStringBody(getKeystoreResponse)),
+				),
+			}}},
+			want: &models.KeystoreContents{Secrets: map[string]models.KeystoreSecret{
+				"s3.client.foobar.access_key":         {AsFile: ec.Bool(false)},
+				"gcs.client.default.credentials_file": {AsFile: ec.Bool(true)},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Get(tt.args.params)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestList(t *testing.T) {
+	type args struct {
+		params GetParams
+	}
+	tests := []struct {
+		name string
+		args args
+		want []string
+		err  error
+	}{
+		{
+			name: "fails due to parameter validation",
+			err: multierror.NewPrefixed("deployment keystore get",
+				apierror.ErrMissingAPI,
+				errors.New(`id "" is invalid`),
+				errors.New("region not specified and is required for this operation"),
+			),
+		},
+		{
+			name: "returns an empty list when the keystore is empty",
+			args: args{params: GetParams{Params: Params{
+				DeploymentID: deploymentID,
+				Region:       "us-east-1",
+				API: api.NewMock(
+					mock.New200Response(mock.NewStringBody(getDeploymentResponse)),
+					mock.New200Response(mock.NewStringBody(`{"secrets": {}}`)),
+				),
+			}}},
+			want: []string{},
+		},
+		{
+			name: "returns the sorted secret names",
+			args: args{params: GetParams{Params: Params{
+				DeploymentID: deploymentID,
+				Region:       "us-east-1",
+				API: api.NewMock(
+					mock.New200Response(mock.NewStringBody(getDeploymentResponse)),
+					mock.New200Response(mock.NewStringBody(getKeystoreResponse)),
+				),
+			}}},
+			want: []string{
+				"gcs.client.default.credentials_file",
+				"s3.client.foobar.access_key",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := List(tt.args.params)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
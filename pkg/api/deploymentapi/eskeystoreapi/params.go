@@ -0,0 +1,63 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package eskeystoreapi
+
+import (
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/api/deploymentapi"
+	"github.com/elastic/cloud-sdk-go/pkg/api/deploymentapi/deputil"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+// Params is embedded in all of the keystore parameter structures, it contains
+// the fields which are required to locate a deployment's Elasticsearch
+// keystore.
+type Params struct {
+	*api.API
+
+	DeploymentID string
+	Region       string
+}
+
+// errors returns a list of errors for any parameters which aren't set or are
+// invalid.
+func (params Params) errors() []error {
+	var errs []error
+	if params.API == nil {
+		errs = append(errs, apierror.ErrMissingAPI)
+	}
+
+	if len(params.DeploymentID) != 32 {
+		errs = append(errs, deputil.NewInvalidDeploymentIDError(params.DeploymentID))
+	}
+
+	if err := ec.RequireRegionSet(params.Region); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errs
+}
+
+// elasticsearchID resolves the Elasticsearch resource ID from the deployment.
+func (params Params) elasticsearchID() (string, error) {
+	return deploymentapi.GetElasticsearchID(deploymentapi.GetParams{
+		API:          params.API,
+		DeploymentID: params.DeploymentID,
+	})
+}
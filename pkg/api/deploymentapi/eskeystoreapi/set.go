@@ -0,0 +1,204 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package eskeystoreapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/client/clusters_elasticsearch"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+// SetParams is consumed by the Set function.
+type SetParams struct {
+	Params
+
+	// Contents are the secrets which will be set in the keystore. Any secret
+	// with an empty value is removed from the keystore. Secrets which aren't
+	// specified are left untouched.
+	Contents *models.KeystoreContents
+}
+
+// Validate ensures the parameters are usable by the consuming function.
+func (params SetParams) Validate() error {
+	var merr = multierror.NewPrefixed("deployment keystore set",
+		params.errors()...,
+	)
+
+	if params.Contents == nil || len(params.Contents.Secrets) == 0 {
+		merr = merr.Append(errors.New("keystore contents cannot be empty"))
+	}
+
+	return merr.ErrorOrNil()
+}
+
+// Set patches the Elasticsearch keystore for a deployment with the specified
+// contents, returning the resulting keystore contents.
+func Set(params SetParams) (*models.KeystoreContents, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	id, err := params.elasticsearchID()
+	if err != nil {
+		return nil, err
+	}
+
+	return set(params.Params, id, params.Contents)
+}
+
+// SetSecretParams is consumed by the SetSecret function.
+type SetSecretParams struct {
+	Params
+
+	// Name of the secret setting, i.e. "s3.client.default.access_key".
+	Name string
+
+	// Secret to set. Use NewSecretFromFile to create a secret from a file.
+	Secret models.KeystoreSecret
+}
+
+// Validate ensures the parameters are usable by the consuming function.
+func (params SetSecretParams) Validate() error {
+	var merr = multierror.NewPrefixed("deployment keystore set secret",
+		params.errors()...,
+	)
+
+	if params.Name == "" {
+		merr = merr.Append(errors.New("secret name cannot be empty"))
+	}
+
+	if params.Secret.Value == nil {
+		merr = merr.Append(errors.New("secret value cannot be empty"))
+	}
+
+	return merr.ErrorOrNil()
+}
+
+// SetSecret sets a single secret in the Elasticsearch keystore for a
+// deployment, creating it if it doesn't exist or overwriting its value if it
+// does.
+func SetSecret(params SetSecretParams) (*models.KeystoreContents, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	id, err := params.elasticsearchID()
+	if err != nil {
+		return nil, err
+	}
+
+	return set(params.Params, id, &models.KeystoreContents{
+		Secrets: map[string]models.KeystoreSecret{params.Name: params.Secret},
+	})
+}
+
+// RemoveSecretParams is consumed by the RemoveSecret function.
+type RemoveSecretParams struct {
+	Params
+
+	// Names of the secrets to remove.
+	Names []string
+}
+
+// Validate ensures the parameters are usable by the consuming function.
+func (params RemoveSecretParams) Validate() error {
+	var merr = multierror.NewPrefixed("deployment keystore remove secret",
+		params.errors()...,
+	)
+
+	if len(params.Names) == 0 {
+		merr = merr.Append(errors.New("at least one secret name must be specified"))
+	}
+
+	for _, name := range params.Names {
+		if name == "" {
+			merr = merr.Append(errors.New("secret name cannot be empty"))
+			break
+		}
+	}
+
+	return merr.ErrorOrNil()
+}
+
+// RemoveSecret removes one or more secrets from the Elasticsearch keystore for
+// a deployment.
+func RemoveSecret(params RemoveSecretParams) (*models.KeystoreContents, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	id, err := params.elasticsearchID()
+	if err != nil {
+		return nil, err
+	}
+
+	var secrets = make(map[string]models.KeystoreSecret, len(params.Names))
+	for _, name := range params.Names {
+		// Secrets without a value are removed from the keystore.
+		secrets[name] = models.KeystoreSecret{}
+	}
+
+	return set(params.Params, id, &models.KeystoreContents{Secrets: secrets})
+}
+
+// NewSecretFromFile reads the contents of a file and returns a KeystoreSecret
+// which is stored as a file in the keystore. When the file contains a JSON
+// object, like GCS service account credentials, the value is sent as an object
+// rather than as a plain string.
+func NewSecretFromFile(path string) (models.KeystoreSecret, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return models.KeystoreSecret{}, err
+	}
+
+	if len(bytes.TrimSpace(b)) == 0 {
+		return models.KeystoreSecret{}, errors.New("secret file cannot be empty")
+	}
+
+	var value interface{} = string(b)
+	var obj map[string]interface{}
+	if err := json.Unmarshal(b, &obj); err == nil {
+		value = obj
+	}
+
+	return models.KeystoreSecret{AsFile: ec.Bool(true), Value: value}, nil
+}
+
+func set(params Params, id string, contents *models.KeystoreContents) (*models.KeystoreContents, error) {
+	res, err := params.V1API.ClustersElasticsearch.SetEsClusterKeystore(
+		clusters_elasticsearch.NewSetEsClusterKeystoreParams().
+			WithContext(api.WithRegion(context.Background(), params.Region)).
+			WithClusterID(id).
+			WithBody(contents),
+		params.AuthWriter,
+	)
+	if err != nil {
+		return nil, apierror.Unwrap(err)
+	}
+
+	return res.Payload, nil
+}
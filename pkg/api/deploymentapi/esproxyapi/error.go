@@ -0,0 +1,93 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package esproxyapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+)
+
+// Error is returned when a proxied request results in an error response,
+// either from Elasticsearch or from the API proxy itself.
+type Error struct {
+	// StatusCode of the response.
+	StatusCode int
+
+	// Type of the Elasticsearch error (i.e. index_not_found_exception) or the
+	// API error code (i.e. clusters.cluster_not_found).
+	Type string
+
+	// Reason describing the error.
+	Reason string
+
+	// Body is the raw response body.
+	Body []byte
+}
+
+// Error returns the error message.
+func (e *Error) Error() string {
+	if e.Type == "" {
+		return fmt.Sprintf("elasticsearch proxy: %d: %s", e.StatusCode, e.Reason)
+	}
+	return fmt.Sprintf("elasticsearch proxy: %d: %s: %s", e.StatusCode, e.Type, e.Reason)
+}
+
+// esError is the error format returned by Elasticsearch.
+type esError struct {
+	Error json.RawMessage `json:"error"`
+}
+
+type esErrorCause struct {
+	Type   string `json:"type"`
+	Reason string `json:"reason"`
+}
+
+// newError creates an *Error from an error response body. It understands both
+// the Elasticsearch and the API error formats, falling back to using the raw
+// body as the reason.
+func newError(code int, body []byte) *Error {
+	var e = Error{StatusCode: code, Body: body, Reason: strings.TrimSpace(string(body))}
+
+	var es esError
+	if err := json.Unmarshal(body, &es); err == nil && len(es.Error) > 0 {
+		var cause esErrorCause
+		if err := json.Unmarshal(es.Error, &cause); err == nil {
+			e.Type, e.Reason = cause.Type, cause.Reason
+			return &e
+		}
+
+		// Older Elasticsearch versions return the error as a string.
+		var reason string
+		if err := json.Unmarshal(es.Error, &reason); err == nil {
+			e.Reason = reason
+			return &e
+		}
+	}
+
+	var reply models.BasicFailedReply
+	if err := json.Unmarshal(body, &reply); err == nil && len(reply.Errors) > 0 {
+		if el := reply.Errors[0]; el.Code != nil && el.Message != nil {
+			e.Type, e.Reason = *el.Code, *el.Message
+		}
+	}
+
+	return &e
+}
@@ -0,0 +1,157 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package esproxyapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ClusterHealth is the Elasticsearch _cluster/health response.
+type ClusterHealth struct {
+	ClusterName                 string  `json:"cluster_name"`
+	Status                      string  `json:"status"`
+	TimedOut                    bool    `json:"timed_out"`
+	NumberOfNodes               int     `json:"number_of_nodes"`
+	NumberOfDataNodes           int     `json:"number_of_data_nodes"`
+	ActivePrimaryShards         int     `json:"active_primary_shards"`
+	ActiveShards                int     `json:"active_shards"`
+	RelocatingShards            int     `json:"relocating_shards"`
+	InitializingShards          int     `json:"initializing_shards"`
+	UnassignedShards            int     `json:"unassigned_shards"`
+	DelayedUnassignedShards     int     `json:"delayed_unassigned_shards"`
+	NumberOfPendingTasks        int     `json:"number_of_pending_tasks"`
+	ActiveShardsPercentAsNumber float64 `json:"active_shards_percent_as_number"`
+}
+
+// Index is a single entry of the Elasticsearch _cat/indices response.
+type Index struct {
+	Health       string `json:"health"`
+	Status       string `json:"status"`
+	Index        string `json:"index"`
+	UUID         string `json:"uuid"`
+	Primaries    string `json:"pri"`
+	Replicas     string `json:"rep"`
+	DocsCount    string `json:"docs.count"`
+	DocsDeleted  string `json:"docs.deleted"`
+	StoreSize    string `json:"store.size"`
+	PriStoreSize string `json:"pri.store.size"`
+}
+
+// SnapshotRepository is a snapshot repository as returned by the Elasticsearch
+// _snapshot API.
+type SnapshotRepository struct {
+	Type     string                 `json:"type"`
+	Settings map[string]interface{} `json:"settings"`
+}
+
+// Snapshot is a single snapshot as returned by the Elasticsearch _snapshot API.
+type Snapshot struct {
+	Snapshot           string   `json:"snapshot"`
+	UUID               string   `json:"uuid"`
+	Version            string   `json:"version"`
+	State              string   `json:"state"`
+	Indices            []string `json:"indices"`
+	StartTime          string   `json:"start_time"`
+	EndTime            string   `json:"end_time"`
+	DurationInMillis   int64    `json:"duration_in_millis"`
+	IncludeGlobalState bool     `json:"include_global_state"`
+}
+
+// ClusterHealth returns the Elasticsearch cluster health of a deployment.
+func (c *Client) ClusterHealth(ctx context.Context, deploymentID string) (*ClusterHealth, error) {
+	var health ClusterHealth
+	if err := c.getJSON(ctx, deploymentID, "_cluster/health", &health); err != nil {
+		return nil, err
+	}
+	return &health, nil
+}
+
+// CatIndices returns the indices of a deployment's Elasticsearch resource.
+func (c *Client) CatIndices(ctx context.Context, deploymentID string) ([]Index, error) {
+	var indices []Index
+	if err := c.getJSON(ctx, deploymentID, "_cat/indices?format=json", &indices); err != nil {
+		return nil, err
+	}
+	return indices, nil
+}
+
+// SnapshotRepositories returns the snapshot repositories which are registered
+// in a deployment's Elasticsearch resource, keyed by name.
+func (c *Client) SnapshotRepositories(ctx context.Context, deploymentID string) (map[string]SnapshotRepository, error) {
+	var repositories map[string]SnapshotRepository
+	if err := c.getJSON(ctx, deploymentID, "_snapshot", &repositories); err != nil {
+		return nil, err
+	}
+	return repositories, nil
+}
+
+// Snapshots returns all of the snapshots in a deployment's Elasticsearch
+// snapshot repository.
+func (c *Client) Snapshots(ctx context.Context, deploymentID, repository string) ([]Snapshot, error) {
+	if repository == "" {
+		return nil, errors.New("elasticsearch proxy request: snapshot repository cannot be empty")
+	}
+
+	var res struct {
+		Snapshots []Snapshot `json:"snapshots"`
+	}
+	var p = "_snapshot/" + url.PathEscape(repository) + "/_all"
+	if err := c.getJSON(ctx, deploymentID, p, &res); err != nil {
+		return nil, err
+	}
+	return res.Snapshots, nil
+}
+
+// Snapshot returns a single snapshot from a deployment's Elasticsearch
+// snapshot repository.
+func (c *Client) Snapshot(ctx context.Context, deploymentID, repository, name string) (*Snapshot, error) {
+	if repository == "" {
+		return nil, errors.New("elasticsearch proxy request: snapshot repository cannot be empty")
+	}
+	if name == "" {
+		return nil, errors.New("elasticsearch proxy request: snapshot name cannot be empty")
+	}
+
+	var res struct {
+		Snapshots []Snapshot `json:"snapshots"`
+	}
+	var p = "_snapshot/" + url.PathEscape(repository) + "/" + url.PathEscape(name)
+	if err := c.getJSON(ctx, deploymentID, p, &res); err != nil {
+		return nil, err
+	}
+
+	if len(res.Snapshots) == 0 {
+		return nil, fmt.Errorf("elasticsearch proxy request: snapshot %s not found", name)
+	}
+	return &res.Snapshots[0], nil
+}
+
+func (c *Client) getJSON(ctx context.Context, deploymentID, path string, v interface{}) error {
+	res, err := c.Do(ctx, deploymentID, http.MethodGet, path, nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	return json.NewDecoder(res.Body).Decode(v)
+}
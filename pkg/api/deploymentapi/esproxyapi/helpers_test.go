@@ -0,0 +1,159 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package esproxyapi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/mock"
+)
+
+func TestClient_ClusterHealth(t *testing.T) {
+	client, err := NewClient(Params{Region: "us-east-1", API: api.NewMock(
+		mock.New200Response(mock.NewStringBody(getDeploymentResponse)),
+		newJSONResponse(200, `{
+  "cluster_name": "cde7b6b605424a54ce9d56316eab13a1",
+  "status": "green",
+  "number_of_nodes": 3,
+  "number_of_data_nodes": 2,
+  "active_primary_shards": 10,
+  "active_shards": 20,
+  "active_shards_percent_as_number": 100.0
+}`, &mock.RequestAssertion{
+			Header: proxyHeaders(false),
+			Method: "GET",
+			Host:   api.DefaultMockHost,
+			Path:   proxyPath + "/_cluster/health",
+		}),
+	)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := client.ClusterHealth(context.Background(), deploymentID)
+	assert.NoError(t, err)
+	assert.Equal(t, &ClusterHealth{
+		ClusterName:                 "cde7b6b605424a54ce9d56316eab13a1",
+		Status:                      "green",
+		NumberOfNodes:               3,
+		NumberOfDataNodes:           2,
+		ActivePrimaryShards:         10,
+		ActiveShards:                20,
+		ActiveShardsPercentAsNumber: 100,
+	}, got)
+}
+
+func TestClient_CatIndices(t *testing.T) {
+	client, err := NewClient(Params{Region: "us-east-1", API: api.NewMock(
+		mock.New200Response(mock.NewStringBody(getDeploymentResponse)),
+		newJSONResponse(200, `[
+  {"health": "green", "status": "open", "index": "logs", "pri": "1", "rep": "1", "docs.count": "10"}
+]`, &mock.RequestAssertion{
+			Header: proxyHeaders(false),
+			Method: "GET",
+			Host:   api.DefaultMockHost,
+			Path:   proxyPath + "/_cat/indices",
+			Query:  map[string][]string{"format": {"json"}},
+		}),
+	)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := client.CatIndices(context.Background(), deploymentID)
+	assert.NoError(t, err)
+	assert.Equal(t, []Index{{
+		Health: "green", Status: "open", Index: "logs",
+		Primaries: "1", Replicas: "1", DocsCount: "10",
+	}}, got)
+}
+
+func TestClient_Snapshots(t *testing.T) {
+	client, err := NewClient(Params{Region: "us-east-1", API: api.NewMock(
+		mock.New200Response(mock.NewStringBody(getDeploymentResponse)),
+		newJSONResponse(200, `{"found-snapshots": {"type": "s3", "settings": {"bucket": "my-bucket"}}}`, &mock.RequestAssertion{
+			Header: proxyHeaders(false),
+			Method: "GET",
+			Host:   api.DefaultMockHost,
+			Path:   proxyPath + "/_snapshot",
+		}),
+		newJSONResponse(200, `{"snapshots": [
+  {"snapshot": "cloud-snapshot-2020.07.01", "state": "SUCCESS", "indices": ["logs"]}
+]}`, &mock.RequestAssertion{
+			Header: proxyHeaders(false),
+			Method: "GET",
+			Host:   api.DefaultMockHost,
+			Path:   proxyPath + "/_snapshot/found-snapshots/_all",
+		}),
+	)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repos, err := client.SnapshotRepositories(context.Background(), deploymentID)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]SnapshotRepository{"found-snapshots": {
+		Type: "s3", Settings: map[string]interface{}{"bucket": "my-bucket"},
+	}}, repos)
+
+	snapshots, err := client.Snapshots(context.Background(), deploymentID, "found-snapshots")
+	assert.NoError(t, err)
+	assert.Equal(t, []Snapshot{{
+		Snapshot: "cloud-snapshot-2020.07.01", State: "SUCCESS", Indices: []string{"logs"},
+	}}, snapshots)
+
+	_, err = client.Snapshots(context.Background(), deploymentID, "")
+	assert.EqualError(t, err, "elasticsearch proxy request: snapshot repository cannot be empty")
+}
+
+func TestClient_Snapshot(t *testing.T) {
+	client, err := NewClient(Params{Region: "us-east-1", API: api.NewMock(
+		mock.New200Response(mock.NewStringBody(getDeploymentResponse)),
+		newJSONResponse(200, `{"snapshots": [
+  {"snapshot": "my-snapshot", "state": "IN_PROGRESS", "indices": ["logs"]}
+]}`, &mock.RequestAssertion{
+			Header: proxyHeaders(false),
+			Method: "GET",
+			Host:   api.DefaultMockHost,
+			Path:   proxyPath + "/_snapshot/found-snapshots/my-snapshot",
+		}),
+		newJSONResponse(200, `{"snapshots": []}`, nil),
+	)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot, err := client.Snapshot(context.Background(), deploymentID, "found-snapshots", "my-snapshot")
+	assert.NoError(t, err)
+	assert.Equal(t, &Snapshot{
+		Snapshot: "my-snapshot", State: "IN_PROGRESS", Indices: []string{"logs"},
+	}, snapshot)
+
+	_, err = client.Snapshot(context.Background(), deploymentID, "found-snapshots", "other")
+	assert.EqualError(t, err, "elasticsearch proxy request: snapshot other not found")
+
+	_, err = client.Snapshot(context.Background(), deploymentID, "", "my-snapshot")
+	assert.EqualError(t, err, "elasticsearch proxy request: snapshot repository cannot be empty")
+
+	_, err = client.Snapshot(context.Background(), deploymentID, "found-snapshots", "")
+	assert.EqualError(t, err, "elasticsearch proxy request: snapshot name cannot be empty")
+}
@@ -0,0 +1,263 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package esproxyapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/api/deploymentapi"
+	"github.com/elastic/cloud-sdk-go/pkg/api/deploymentapi/deputil"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+const (
+	// managementHeader must be set to "true" on all proxied requests.
+	managementHeader = "X-Management-Request"
+
+	proxyPathPrefix = "/clusters/elasticsearch/{cluster_id}/proxy"
+)
+
+// Params is consumed by NewClient.
+type Params struct {
+	*api.API
+
+	Region string
+}
+
+// Validate ensures the parameters are usable by the consuming function.
+func (params Params) Validate() error {
+	var merr = multierror.NewPrefixed("elasticsearch proxy")
+	if params.API == nil {
+		merr = merr.Append(apierror.ErrMissingAPI)
+	}
+
+	if err := ec.RequireRegionSet(params.Region); err != nil {
+		merr = merr.Append(err)
+	}
+
+	return merr.ErrorOrNil()
+}
+
+// Client sends requests to the Elasticsearch resource of deployments through
+// the API proxy. The Elasticsearch resource IDs are resolved from the
+// deployment IDs and cached for the lifetime of the client. It is safe for
+// concurrent use.
+type Client struct {
+	params Params
+
+	mu  sync.RWMutex
+	ids map[string]string
+}
+
+// NewClient creates a new Client from the parameters.
+func NewClient(params Params) (*Client, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &Client{params: params, ids: make(map[string]string)}, nil
+}
+
+// Response is returned by Do. The caller is responsible for closing the Body.
+type Response struct {
+	StatusCode  int
+	ContentType string
+	Body        io.ReadCloser
+}
+
+// Do sends a request with the specified method and path (i.e. "_cat/indices?v")
+// to the Elasticsearch resource of a deployment through the API proxy. The
+// response body is streamed as it's read, and any Elasticsearch error
+// responses are returned as an *Error.
+func (c *Client) Do(ctx context.Context, deploymentID, method, path string, body io.Reader) (*Response, error) {
+	if err := validateRequest(deploymentID, method); err != nil {
+		return nil, err
+	}
+
+	clusterID, err := c.elasticsearchID(deploymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	esPath, query, err := splitPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var reader, writer = io.Pipe()
+	var responses = make(chan *Response, 1)
+	var errs = make(chan error, 1)
+
+	go func() {
+		_, err := c.params.V1API.Transport.Submit(&runtime.ClientOperation{
+			ID:                 "es-proxy-request",
+			Method:             strings.ToUpper(method),
+			PathPattern:        proxyPathPrefix + esPath,
+			ProducesMediaTypes: []string{"application/json"},
+			ConsumesMediaTypes: []string{"application/json"},
+			Schemes:            []string{"https"},
+			Params: proxyRequest{
+				clusterID: clusterID,
+				query:     query,
+				body:      body,
+			},
+			Reader: runtime.ClientResponseReaderFunc(func(res runtime.ClientResponse, _ runtime.Consumer) (interface{}, error) {
+				if res.Code() >= http.StatusBadRequest {
+					b, _ := ioutil.ReadAll(res.Body())
+					return nil, newError(res.Code(), b)
+				}
+
+				responses <- &Response{
+					StatusCode:  res.Code(),
+					ContentType: res.GetHeader("Content-Type"),
+					Body:        reader,
+				}
+
+				// Blocks until the body has been fully read or closed.
+				_, err := io.Copy(writer, res.Body())
+				writer.CloseWithError(err)
+				return nil, nil
+			}),
+			AuthInfo: c.params.AuthWriter,
+			Context:  api.WithRegion(ctx, c.params.Region),
+		})
+		if err != nil {
+			writer.CloseWithError(err)
+			errs <- apierror.Unwrap(err)
+		}
+	}()
+
+	select {
+	case res := <-responses:
+		return res, nil
+	case err := <-errs:
+		return nil, err
+	}
+}
+
+// elasticsearchID resolves the Elasticsearch resource ID of a deployment,
+// caching the result.
+func (c *Client) elasticsearchID(deploymentID string) (string, error) {
+	c.mu.RLock()
+	id, ok := c.ids[deploymentID]
+	c.mu.RUnlock()
+	if ok {
+		return id, nil
+	}
+
+	res, err := deploymentapi.Get(deploymentapi.GetParams{
+		API:          c.params.API,
+		DeploymentID: deploymentID,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if res.Resources == nil || len(res.Resources.Elasticsearch) == 0 {
+		return "", fmt.Errorf(
+			"deployment %s has no elasticsearch resources", deploymentID,
+		)
+	}
+
+	id = *res.Resources.Elasticsearch[0].ID
+	c.mu.Lock()
+	c.ids[deploymentID] = id
+	c.mu.Unlock()
+
+	return id, nil
+}
+
+func validateRequest(deploymentID, method string) error {
+	var merr = multierror.NewPrefixed("elasticsearch proxy request")
+	if len(deploymentID) != 32 {
+		merr = merr.Append(deputil.NewInvalidDeploymentIDError(deploymentID))
+	}
+
+	switch strings.ToUpper(method) {
+	case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete:
+	default:
+		merr = merr.Append(fmt.Errorf(`method "%s" is not supported`, method))
+	}
+
+	return merr.ErrorOrNil()
+}
+
+// splitPath splits the Elasticsearch path into its path and query parts. The
+// path is returned with a leading slash.
+func splitPath(p string) (string, url.Values, error) {
+	u, err := url.Parse(strings.TrimSpace(p))
+	if err != nil {
+		return "", nil, err
+	}
+
+	var esPath = path.Clean("/" + u.Path)
+	if esPath == "/" {
+		return "", nil, errors.New("elasticsearch proxy request: path cannot be empty")
+	}
+
+	return esPath, u.Query(), nil
+}
+
+// proxyRequest writes the proxied request parameters to the request.
+type proxyRequest struct {
+	clusterID string
+	query     url.Values
+	body      io.Reader
+}
+
+// WriteToRequest writes these params to a runtime.ClientRequest.
+func (r proxyRequest) WriteToRequest(req runtime.ClientRequest, _ strfmt.Registry) error {
+	if err := req.SetHeaderParam(managementHeader, "true"); err != nil {
+		return err
+	}
+
+	if err := req.SetPathParam("cluster_id", r.clusterID); err != nil {
+		return err
+	}
+
+	for k, v := range r.query {
+		if err := req.SetQueryParam(k, v...); err != nil {
+			return err
+		}
+	}
+
+	if r.body != nil {
+		return req.SetBodyParam(r.body)
+	}
+
+	return nil
+}
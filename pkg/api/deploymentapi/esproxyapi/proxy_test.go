@@ -0,0 +1,278 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package esproxyapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/api/mock"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+)
+
+const (
+	deploymentID = "f1d329b0fb34470ba8b18361cabdd2bc"
+
+	getDeploymentResponse = `{
+  "id": "f1d329b0fb34470ba8b18361cabdd2bc",
+  "resources": {
+    "elasticsearch": [{
+      "id": "cde7b6b605424a54ce9d56316eab13a1",
+      "ref_id": "main-elasticsearch",
+      "region": "us-east-1"
+    }]
+  }
+}`
+
+	proxyPath = "/api/v1/regions/us-east-1/clusters/elasticsearch/cde7b6b605424a54ce9d56316eab13a1/proxy"
+)
+
+func proxyHeaders(write bool) http.Header {
+	var headers = http.Header{"X-Management-Request": {"true"}}
+	var defaults = api.DefaultReadMockHeaders
+	if write {
+		defaults = api.DefaultWriteMockHeaders
+	}
+	for k, v := range defaults {
+		headers[k] = v
+	}
+	return headers
+}
+
+func newJSONResponse(code int, body string, assertion *mock.RequestAssertion) mock.Response {
+	return mock.Response{
+		Response: http.Response{
+			StatusCode: code,
+			Header:     http.Header{"Content-Type": {"application/json; charset=UTF-8"}},
+			Body:       mock.NewStringBody(body),
+		},
+		Assert: assertion,
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	tests := []struct {
+		name   string
+		params Params
+		err    error
+	}{
+		{
+			name: "fails due to parameter validation",
+			err: multierror.NewPrefixed("elasticsearch proxy",
+				apierror.ErrMissingAPI,
+				errors.New("region not specified and is required for this operation"),
+			),
+		},
+		{
+			name:   "succeeds",
+			params: Params{API: api.NewMock(), Region: "us-east-1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewClient(tt.params)
+			assert.Equal(t, tt.err, err)
+			if tt.err == nil {
+				assert.NotNil(t, got)
+			}
+		})
+	}
+}
+
+func TestClient_Do(t *testing.T) {
+	type args struct {
+		deploymentID string
+		method       string
+		path         string
+		body         string
+	}
+	tests := []struct {
+		name     string
+		mock     []mock.Response
+		args     args
+		wantCode int
+		wantBody string
+		err      error
+	}{
+		{
+			name: "fails due to invalid request",
+			args: args{deploymentID: "some", method: "PATCH", path: "_search"},
+			err: multierror.NewPrefixed("elasticsearch proxy request",
+				errors.New(`id "some" is invalid`),
+				errors.New(`method "PATCH" is not supported`),
+			),
+		},
+		{
+			name: "fails due to an empty path",
+			mock: []mock.Response{mock.New200Response(mock.NewStringBody(getDeploymentResponse))},
+			args: args{deploymentID: deploymentID, method: "GET", path: "/"},
+			err:  errors.New("elasticsearch proxy request: path cannot be empty"),
+		},
+		{
+			name: "fails when the deployment has no elasticsearch resources",
+			mock: []mock.Response{mock.New200Response(mock.NewStringBody(`{"resources": {}}`))},
+			args: args{deploymentID: deploymentID, method: "GET", path: "_cluster/health"},
+			err:  fmt.Errorf("deployment %s has no elasticsearch resources", deploymentID),
+		},
+		{
+			name: "returns an elasticsearch error",
+			mock: []mock.Response{
+				mock.New200Response(mock.NewStringBody(getDeploymentResponse)),
+				newJSONResponse(404, `{"error":{"root_cause":[],"type":"index_not_found_exception","reason":"no such index [foo]"},"status":404}`, nil),
+			},
+			args: args{deploymentID: deploymentID, method: "GET", path: "foo/_search"},
+			err: &Error{
+				StatusCode: 404,
+				Type:       "index_not_found_exception",
+				Reason:     "no such index [foo]",
+				Body:       []byte(`{"error":{"root_cause":[],"type":"index_not_found_exception","reason":"no such index [foo]"},"status":404}`),
+			},
+		},
+		{
+			name: "returns a legacy elasticsearch error",
+			mock: []mock.Response{
+				mock.New200Response(mock.NewStringBody(getDeploymentResponse)),
+				newJSONResponse(400, `{"error":"ElasticsearchParseException[failed to parse]","status":400}`, nil),
+			},
+			args: args{deploymentID: deploymentID, method: "GET", path: "_search"},
+			err: &Error{
+				StatusCode: 400,
+				Reason:     "ElasticsearchParseException[failed to parse]",
+				Body:       []byte(`{"error":"ElasticsearchParseException[failed to parse]","status":400}`),
+			},
+		},
+		{
+			name: "returns an API error",
+			mock: []mock.Response{
+				mock.New200Response(mock.NewStringBody(getDeploymentResponse)),
+				newJSONResponse(404, `{"errors":[{"code":"clusters.cluster_not_found","message":"cluster not found"}]}`, nil),
+			},
+			args: args{deploymentID: deploymentID, method: "GET", path: "_search"},
+			err: &Error{
+				StatusCode: 404,
+				Type:       "clusters.cluster_not_found",
+				Reason:     "cluster not found",
+				Body:       []byte(`{"errors":[{"code":"clusters.cluster_not_found","message":"cluster not found"}]}`),
+			},
+		},
+		{
+			name: "streams a GET response with a query",
+			mock: []mock.Response{
+				mock.New200Response(mock.NewStringBody(getDeploymentResponse)),
+				newJSONResponse(200, "green open my-index", &mock.RequestAssertion{
+					Header: proxyHeaders(false),
+					Method: "GET",
+					Host:   api.DefaultMockHost,
+					Path:   proxyPath + "/_cat/indices",
+					Query:  url.Values{"v": {""}, "h": {"health,status,index"}},
+				}),
+			},
+			args:     args{deploymentID: deploymentID, method: "get", path: "_cat/indices?v&h=health,status,index"},
+			wantCode: 200,
+			wantBody: "green open my-index",
+		},
+		{
+			name: "sends a POST request with a body",
+			mock: []mock.Response{
+				mock.New200Response(mock.NewStringBody(getDeploymentResponse)),
+				newJSONResponse(200, `{"hits":{"total":{"value":0}}}`, &mock.RequestAssertion{
+					Header: proxyHeaders(true),
+					Method: "POST",
+					Host:   api.DefaultMockHost,
+					Path:   proxyPath + "/my-index/_search",
+					Body:   mock.NewStringBody(`{"query":{"match_all":{}}}`),
+				}),
+			},
+			args: args{
+				deploymentID: deploymentID,
+				method:       "POST",
+				path:         "/my-index/_search",
+				body:         `{"query":{"match_all":{}}}`,
+			},
+			wantCode: 200,
+			wantBody: `{"hits":{"total":{"value":0}}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClient(Params{API: api.NewMock(tt.mock...), Region: "us-east-1"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var body io.Reader
+			if tt.args.body != "" {
+				body = strings.NewReader(tt.args.body)
+			}
+
+			got, err := client.Do(context.Background(), tt.args.deploymentID, tt.args.method, tt.args.path, body)
+			assert.Equal(t, tt.err, err)
+			if tt.err != nil {
+				assert.Nil(t, got)
+				return
+			}
+
+			defer got.Body.Close()
+			b, err := ioutil.ReadAll(got.Body)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantCode, got.StatusCode)
+			assert.Equal(t, tt.wantBody, string(b))
+		})
+	}
+}
+
+func TestClient_Do_CachesElasticsearchID(t *testing.T) {
+	client, err := NewClient(Params{Region: "us-east-1", API: api.NewMock(
+		mock.New200Response(mock.NewStringBody(getDeploymentResponse)),
+		newJSONResponse(200, `{}`, nil),
+		newJSONResponse(200, `{}`, nil),
+	)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		res, err := client.Do(context.Background(), deploymentID, "GET", "_cluster/health", nil)
+		if !assert.NoError(t, err) {
+			return
+		}
+		res.Body.Close()
+	}
+}
+
+func TestError_Error(t *testing.T) {
+	assert.EqualError(t,
+		&Error{StatusCode: 404, Type: "index_not_found_exception", Reason: "no such index [foo]"},
+		"elasticsearch proxy: 404: index_not_found_exception: no such index [foo]",
+	)
+	assert.EqualError(t,
+		&Error{StatusCode: 502, Reason: "Bad Gateway"},
+		"elasticsearch proxy: 502: Bad Gateway",
+	)
+}
@@ -127,6 +127,29 @@ func GetAppSearch(params GetParams) (*models.AppSearchResourceInfo, error) {
 	return res.Payload, nil
 }
 
+// GetEnterpriseSearch returns info about an enterprise search resource belonging to a given deployment.
+func GetEnterpriseSearch(params GetParams) (*models.EnterpriseSearchResourceInfo, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	res, err := params.API.V1API.Deployments.GetDeploymentEnterpriseSearchResourceInfo(
+		deployments.NewGetDeploymentEnterpriseSearchResourceInfoParams().
+			WithDeploymentID(params.DeploymentID).
+			WithRefID(params.RefID).
+			WithShowPlans(ec.Bool(params.ShowPlans)).
+			WithShowPlanDefaults(ec.Bool(params.ShowPlanDefaults)).
+			WithShowPlanLogs(ec.Bool(params.ShowPlanLogs)).
+			WithShowMetadata(ec.Bool(params.ShowMetadata)).
+			WithShowSettings(ec.Bool(params.ShowSettings)),
+		params.AuthWriter,
+	)
+	if err != nil {
+		return nil, apierror.Unwrap(err)
+	}
+	return res.Payload, nil
+}
+
 // GetElasticsearch returns info about an elasticsearch resource belonging to a given deployment.
 func GetElasticsearch(params GetParams) (*models.ElasticsearchResourceInfo, error) {
 	if err := params.Validate(); err != nil {
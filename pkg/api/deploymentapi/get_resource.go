@@ -20,7 +20,7 @@ package deploymentapi
 import (
 	"fmt"
 
-	"github.com/elastic/cloud-sdk-go/pkg/api/deploymentapi/deputil"
+	"github.com/elastic/cloud-sdk-go/pkg/resourcekind"
 )
 
 // GetResourceParams is consumed by GetResource.
@@ -33,39 +33,24 @@ type GetResourceParams struct {
 // GetResource is a high level function which either returns the top level
 // deployment information when no params.Kind is specified, or it returns a
 // specific deployment resource information by RefID. If no RefID is defined,
-// It will perform an additional API call to obtain the top level
+// the last resource of the kind is returned.
 func GetResource(params GetResourceParams) (interface{}, error) {
 	if err := params.Validate(); err != nil {
 		return nil, err
 	}
 
-	var noRefIDAndKind = params.GetParams.RefID == "" && params.Kind != ""
-	if noRefIDAndKind {
-		refID, err := GetKindRefID(params)
-		if err != nil {
-			return nil, err
-		}
-		params.GetParams.RefID = refID
+	if params.Kind == "" {
+		return Get(params.GetParams)
 	}
 
-	switch params.Kind {
-	case deputil.Apm:
-		return GetApm(params.GetParams)
-	case deputil.Kibana:
-		return GetKibana(params.GetParams)
-	case deputil.Elasticsearch:
-		return GetElasticsearch(params.GetParams)
-	case deputil.Appsearch:
-		return GetAppSearch(params.GetParams)
-	default:
-		// If the is specified but not supported, return an error.
-		if params.Kind != "" {
-			return nil, fmt.Errorf(
-				"deployment get: resource kind %s is not valid", params.Kind,
-			)
-		}
-		return Get(params.GetParams)
+	kind, ok := resourcekind.Get(params.Kind)
+	if !ok {
+		return nil, fmt.Errorf(
+			"deployment get: resource kind %s is not valid", params.Kind,
+		)
 	}
+
+	return getKindResource(params.GetParams, kind)
 }
 
 // GetKindRefID obtains a resource kind RefID. If the kind is not supported
@@ -77,22 +62,9 @@ func GetKindRefID(params GetResourceParams) (string, error) {
 	}
 
 	var refID string
-	switch params.Kind {
-	case deputil.Apm:
-		for _, resource := range res.Resources.Apm {
-			refID = *resource.RefID
-		}
-	case deputil.Kibana:
-		for _, resource := range res.Resources.Kibana {
-			refID = *resource.RefID
-		}
-	case deputil.Elasticsearch:
-		for _, resource := range res.Resources.Elasticsearch {
-			refID = *resource.RefID
-		}
-	case deputil.Appsearch:
-		for _, resource := range res.Resources.Appsearch {
-			refID = *resource.RefID
+	if kind, ok := resourcekind.Get(params.Kind); ok {
+		for _, id := range kind.RefIDs(res.Resources) {
+			refID = id
 		}
 	}
 
@@ -102,3 +74,30 @@ func GetKindRefID(params GetResourceParams) (string, error) {
 
 	return refID, nil
 }
+
+// getKindResource obtains a resource info from the deployment's resources.
+// When params.RefID is empty, the last resource of the kind is returned.
+func getKindResource(params GetParams, kind resourcekind.Kind) (interface{}, error) {
+	res, err := Get(params)
+	if err != nil {
+		return nil, err
+	}
+
+	var infos = kind.Infos(res.Resources)
+	if params.RefID == "" {
+		if len(infos) == 0 {
+			return nil, fmt.Errorf("deployment get: resource kind %s is not available", kind.Name)
+		}
+		return infos[len(infos)-1], nil
+	}
+
+	for _, info := range infos {
+		if kind.RefID(info) == params.RefID {
+			return info, nil
+		}
+	}
+
+	return nil, fmt.Errorf(
+		"deployment get: %s resource %s not found", kind.Name, params.RefID,
+	)
+}
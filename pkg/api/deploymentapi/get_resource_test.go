@@ -19,6 +19,7 @@ package deploymentapi
 
 import (
 	"errors"
+	"net/url"
 	"reflect"
 	"testing"
 
@@ -28,10 +29,65 @@ import (
 	"github.com/elastic/cloud-sdk-go/pkg/api/mock"
 	"github.com/elastic/cloud-sdk-go/pkg/models"
 	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/resourcekind"
 	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
 )
 
 func TestGetResource(t *testing.T) {
+	const id = "3531aaf988594efa87c1aabb7caed337"
+	var getResponse = func(resources *models.DeploymentResources) mock.Response {
+		return mock.New200ResponseAssertion(&mock.RequestAssertion{
+			Header: api.DefaultReadMockHeaders,
+			Method: "GET",
+			Host:   api.DefaultMockHost,
+			Path:   "/api/v1/deployments/" + id,
+			Query: url.Values{
+				"convert_legacy_plans": {"false"},
+				"enrich_with_template": {"true"},
+				"show_metadata":        {"false"},
+				"show_plan_defaults":   {"false"},
+				"show_plan_history":    {"false"},
+				"show_plan_logs":       {"false"},
+				"show_plans":           {"false"},
+				"show_security":        {"false"},
+				"show_settings":        {"false"},
+				"show_system_alerts":   {"5"},
+			},
+		}, mock.NewStructBody(models.DeploymentGetResponse{
+			Healthy: ec.Bool(true), ID: ec.String(id), Resources: resources,
+		}))
+	}
+	var apm = &models.ApmResourceInfo{
+		ElasticsearchClusterRefID: ec.String("elasticsearch"),
+		ID:                        ec.String(id),
+		RefID:                     ec.String(resourcekind.Apm),
+	}
+	var elasticsearch = &models.ElasticsearchResourceInfo{
+		ID: ec.String(id), RefID: ec.String("elasticsearch"),
+	}
+	var kibana = &models.KibanaResourceInfo{
+		ElasticsearchClusterRefID: ec.String("elasticsearch"),
+		ID:                        ec.String(id),
+		RefID:                     ec.String("kibana"),
+	}
+	var appsearch = &models.AppSearchResourceInfo{
+		ElasticsearchClusterRefID: ec.String("elasticsearch"),
+		ID:                        ec.String(id),
+		RefID:                     ec.String("appsearch"),
+	}
+	var enterpriseSearch = &models.EnterpriseSearchResourceInfo{
+		ElasticsearchClusterRefID: ec.String("elasticsearch"),
+		ID:                        ec.String(id),
+		RefID:                     ec.String("enterprise_search"),
+	}
+	var resources = &models.DeploymentResources{
+		Apm:              []*models.ApmResourceInfo{apm},
+		Appsearch:        []*models.AppSearchResourceInfo{appsearch},
+		Elasticsearch:    []*models.ElasticsearchResourceInfo{elasticsearch},
+		EnterpriseSearch: []*models.EnterpriseSearchResourceInfo{enterpriseSearch},
+		Kibana:           []*models.KibanaResourceInfo{kibana},
+	}
+
 	type args struct {
 		params GetResourceParams
 	}
@@ -53,241 +109,120 @@ func TestGetResource(t *testing.T) {
 			name: "obtains a apm resource with a set RefID",
 			args: args{params: GetResourceParams{
 				GetParams: GetParams{
-					API: api.NewMock(mock.New200Response(mock.NewStructBody(
-						models.ApmResourceInfo{
-							ElasticsearchClusterRefID: ec.String("elasticsearch"),
-							ID:                        ec.String("3531aaf988594efa87c1aabb7caed337"),
-							RefID:                     ec.String(deputil.Apm),
-						},
-					))),
-					DeploymentID: "3531aaf988594efa87c1aabb7caed337",
-					RefID:        deputil.Apm,
+					API:          api.NewMock(getResponse(resources)),
+					DeploymentID: id,
+					RefID:        resourcekind.Apm,
 				},
-				Kind: deputil.Apm,
+				Kind: resourcekind.Apm,
 			}},
-			want: &models.ApmResourceInfo{
-				ElasticsearchClusterRefID: ec.String("elasticsearch"),
-				ID:                        ec.String("3531aaf988594efa87c1aabb7caed337"),
-				RefID:                     ec.String(deputil.Apm),
-			},
+			want: apm,
 		},
 		{
 			name: "obtains a apm resource without a RefID",
 			args: args{params: GetResourceParams{
 				GetParams: GetParams{
-					API: api.NewMock(
-						mock.New200Response(mock.NewStructBody(models.DeploymentGetResponse{
-							Healthy: ec.Bool(true),
-							ID:      ec.String("3531aaf988594efa87c1aabb7caed337"),
-							Resources: &models.DeploymentResources{
-								Apm: []*models.ApmResourceInfo{{
-									ElasticsearchClusterRefID: ec.String("elasticsearch"),
-									ID:                        ec.String("3531aaf988594efa87c1aabb7caed337"),
-									RefID:                     ec.String(deputil.Apm),
-								}},
-							},
-						})),
-						mock.New200Response(mock.NewStructBody(models.ApmResourceInfo{
-							ElasticsearchClusterRefID: ec.String("elasticsearch"),
-							ID:                        ec.String("3531aaf988594efa87c1aabb7caed337"),
-							RefID:                     ec.String(deputil.Apm),
-						})),
-					),
-					DeploymentID: "3531aaf988594efa87c1aabb7caed337",
+					API:          api.NewMock(getResponse(resources)),
+					DeploymentID: id,
 				},
-				Kind: deputil.Apm,
+				Kind: resourcekind.Apm,
 			}},
-			want: &models.ApmResourceInfo{
-				ElasticsearchClusterRefID: ec.String("elasticsearch"),
-				ID:                        ec.String("3531aaf988594efa87c1aabb7caed337"),
-				RefID:                     ec.String(deputil.Apm),
-			},
+			want: apm,
 		},
 		{
 			name: "obtains an elasticsearch resource with a set RefID",
 			args: args{params: GetResourceParams{
 				GetParams: GetParams{
-					API: api.NewMock(mock.New200Response(mock.NewStructBody(
-						models.ElasticsearchResourceInfo{
-							ID:    ec.String("3531aaf988594efa87c1aabb7caed337"),
-							RefID: ec.String("elasticsearch"),
-						},
-					))),
-					DeploymentID: "3531aaf988594efa87c1aabb7caed337",
+					API:          api.NewMock(getResponse(resources)),
+					DeploymentID: id,
 					RefID:        "elasticsearch",
 				},
 				Kind: "elasticsearch",
 			}},
-			want: &models.ElasticsearchResourceInfo{
-				ID:    ec.String("3531aaf988594efa87c1aabb7caed337"),
-				RefID: ec.String("elasticsearch"),
-			},
+			want: elasticsearch,
 		},
 		{
 			name: "obtains an elasticsearch resource without a RefID",
 			args: args{params: GetResourceParams{
-				Kind: "elasticsearch",
 				GetParams: GetParams{
-					API: api.NewMock(
-						mock.New200Response(mock.NewStructBody(models.DeploymentGetResponse{
-							Healthy: ec.Bool(true),
-							ID:      ec.String("3531aaf988594efa87c1aabb7caed337"),
-							Resources: &models.DeploymentResources{
-								Elasticsearch: []*models.ElasticsearchResourceInfo{{
-									ID:    ec.String("3531aaf988594efa87c1aabb7caed337"),
-									RefID: ec.String("elasticsearch"),
-								}},
-							},
-						})),
-						mock.New200Response(mock.NewStructBody(
-							models.ElasticsearchResourceInfo{
-								RefID: ec.String("elasticsearch"),
-								ID:    ec.String("3531aaf988594efa87c1aabb7caed337"),
-							},
-						)),
-					),
-					DeploymentID: "3531aaf988594efa87c1aabb7caed337",
+					API:          api.NewMock(getResponse(resources)),
+					DeploymentID: id,
 				},
+				Kind: "elasticsearch",
 			}},
-			want: &models.ElasticsearchResourceInfo{
-				ID:    ec.String("3531aaf988594efa87c1aabb7caed337"),
-				RefID: ec.String("elasticsearch"),
-			},
+			want: elasticsearch,
 		},
 		{
 			name: "obtains a kibana resource with a set RefID",
 			args: args{params: GetResourceParams{
 				GetParams: GetParams{
-					API: api.NewMock(mock.New200Response(mock.NewStructBody(
-						models.KibanaResourceInfo{
-							ElasticsearchClusterRefID: ec.String("elasticsearch"),
-							ID:                        ec.String("3531aaf988594efa87c1aabb7caed337"),
-							RefID:                     ec.String("kibana"),
-						},
-					))),
-					DeploymentID: "3531aaf988594efa87c1aabb7caed337",
+					API:          api.NewMock(getResponse(resources)),
+					DeploymentID: id,
 					RefID:        "kibana",
 				},
 				Kind: "kibana",
 			}},
-			want: &models.KibanaResourceInfo{
-				ElasticsearchClusterRefID: ec.String("elasticsearch"),
-				ID:                        ec.String("3531aaf988594efa87c1aabb7caed337"),
-				RefID:                     ec.String("kibana"),
-			},
+			want: kibana,
 		},
 		{
 			name: "obtains a kibana resource without a RefID",
 			args: args{params: GetResourceParams{
 				GetParams: GetParams{
-					API: api.NewMock(
-						mock.New200Response(mock.NewStructBody(models.DeploymentGetResponse{
-							Healthy: ec.Bool(true),
-							ID:      ec.String("3531aaf988594efa87c1aabb7caed337"),
-							Resources: &models.DeploymentResources{
-								Kibana: []*models.KibanaResourceInfo{{
-									ID:    ec.String("3531aaf988594efa87c1aabb7caed337"),
-									RefID: ec.String("elasticsearch"),
-								}},
-							},
-						})),
-						mock.New200Response(mock.NewStructBody(
-							models.KibanaResourceInfo{
-								ElasticsearchClusterRefID: ec.String("elasticsearch"),
-								ID:                        ec.String("3531aaf988594efa87c1aabb7caed337"),
-								RefID:                     ec.String("kibana"),
-							},
-						)),
-					),
-					DeploymentID: "3531aaf988594efa87c1aabb7caed337",
+					API:          api.NewMock(getResponse(resources)),
+					DeploymentID: id,
 				},
 				Kind: "kibana",
 			}},
-			want: &models.KibanaResourceInfo{
-				ElasticsearchClusterRefID: ec.String("elasticsearch"),
-				ID:                        ec.String("3531aaf988594efa87c1aabb7caed337"),
-				RefID:                     ec.String("kibana"),
-			},
+			want: kibana,
 		},
 		{
 			name: "obtains a appsearch resource with a set RefID",
 			args: args{params: GetResourceParams{
 				GetParams: GetParams{
-					API: api.NewMock(mock.New200Response(mock.NewStructBody(
-						models.AppSearchResourceInfo{
-							ElasticsearchClusterRefID: ec.String("elasticsearch"),
-							ID:                        ec.String("3531aaf988594efa87c1aabb7caed337"),
-							RefID:                     ec.String("appsearch"),
-						},
-					))),
-					DeploymentID: "3531aaf988594efa87c1aabb7caed337",
+					API:          api.NewMock(getResponse(resources)),
+					DeploymentID: id,
 					RefID:        "appsearch",
 				},
 				Kind: "appsearch",
 			}},
-			want: &models.AppSearchResourceInfo{
-				ElasticsearchClusterRefID: ec.String("elasticsearch"),
-				ID:                        ec.String("3531aaf988594efa87c1aabb7caed337"),
-				RefID:                     ec.String("appsearch"),
-			},
+			want: appsearch,
 		},
 		{
 			name: "obtains a appsearch resource without a RefID",
 			args: args{params: GetResourceParams{
 				GetParams: GetParams{
-					API: api.NewMock(
-						mock.New200Response(mock.NewStructBody(models.DeploymentGetResponse{
-							Healthy: ec.Bool(true),
-							ID:      ec.String("3531aaf988594efa87c1aabb7caed337"),
-							Resources: &models.DeploymentResources{
-								Appsearch: []*models.AppSearchResourceInfo{{
-									ID:    ec.String("3531aaf988594efa87c1aabb7caed337"),
-									RefID: ec.String("appsearch"),
-								}},
-							},
-						})),
-						mock.New200Response(mock.NewStructBody(
-							models.AppSearchResourceInfo{
-								ElasticsearchClusterRefID: ec.String("elasticsearch"),
-								ID:                        ec.String("3531aaf988594efa87c1aabb7caed337"),
-								RefID:                     ec.String("appsearch"),
-							},
-						)),
-					),
-					DeploymentID: "3531aaf988594efa87c1aabb7caed337",
+					API:          api.NewMock(getResponse(resources)),
+					DeploymentID: id,
 				},
 				Kind: "appsearch",
 			}},
-			want: &models.AppSearchResourceInfo{
-				ElasticsearchClusterRefID: ec.String("elasticsearch"),
-				ID:                        ec.String("3531aaf988594efa87c1aabb7caed337"),
-				RefID:                     ec.String("appsearch"),
-			},
+			want: appsearch,
 		},
 		{
-			name: "obtains an invalid resource kind INVALID without a RefID",
+			name: "obtains an enterprise search resource without a RefID",
 			args: args{params: GetResourceParams{
 				GetParams: GetParams{
-					API: api.NewMock(
-						mock.New200Response(mock.NewStructBody(models.DeploymentGetResponse{
-							Healthy: ec.Bool(true),
-							ID:      ec.String("3531aaf988594efa87c1aabb7caed337"),
-							Resources: &models.DeploymentResources{
-								Appsearch: []*models.AppSearchResourceInfo{{
-									ID:    ec.String("3531aaf988594efa87c1aabb7caed337"),
-									RefID: ec.String("appsearch"),
-								}},
-							},
-						})),
-					),
-					DeploymentID: "3531aaf988594efa87c1aabb7caed337",
+					API:          api.NewMock(getResponse(resources)),
+					DeploymentID: id,
 				},
-				Kind: "INVALID",
+				Kind: "enterprise_search",
 			}},
-			err: errors.New("deployment get: resource kind INVALID is not available"),
+			want: enterpriseSearch,
 		},
 		{
-			name: "returns an error when the RefID discovery fails",
+			name: "returns an error when the deployment has no resources of the kind",
+			args: args{params: GetResourceParams{
+				GetParams: GetParams{
+					API: api.NewMock(getResponse(&models.DeploymentResources{
+						Elasticsearch: []*models.ElasticsearchResourceInfo{elasticsearch},
+					})),
+					DeploymentID: id,
+				},
+				Kind: "enterprise_search",
+			}},
+			err: errors.New("deployment get: resource kind enterprise_search is not available"),
+		},
+		{
+			name: "returns an error when the deployment can't be obtained",
 			args: args{params: GetResourceParams{
 				GetParams: GetParams{
 					API: api.NewMock(mock.New500Response(mock.NewStructBody(&models.BasicFailedReply{
@@ -295,18 +230,29 @@ func TestGetResource(t *testing.T) {
 							{Code: ec.String("deployment.missing")},
 						},
 					}))),
-					DeploymentID: "3531aaf988594efa87c1aabb7caed337",
+					DeploymentID: id,
 				},
-				Kind: "INVALID",
+				Kind: "apm",
 			}},
 			err: multierror.NewPrefixed("api error", errors.New("deployment.missing: unknown")),
 		},
+		{
+			name: "tries to obtain an INVALID resource without a RefID",
+			args: args{params: GetResourceParams{
+				GetParams: GetParams{
+					API:          api.NewMock(),
+					DeploymentID: id,
+				},
+				Kind: "INVALID",
+			}},
+			err: errors.New("deployment get: resource kind INVALID is not valid"),
+		},
 		{
 			name: "tries to obtain an INVALID resource with a set RefID",
 			args: args{params: GetResourceParams{
 				GetParams: GetParams{
 					API:          api.NewMock(),
-					DeploymentID: "3531aaf988594efa87c1aabb7caed337",
+					DeploymentID: id,
 					RefID:        "appsearch",
 				},
 				Kind: "INVALID",
@@ -345,3 +291,67 @@ func TestGetResource(t *testing.T) {
 		})
 	}
 }
+
+func Test_getKindResource(t *testing.T) {
+	var kind, _ = resourcekind.Get(resourcekind.Kibana)
+	var getResponse = func() mock.Response {
+		return mock.New200Response(mock.NewStructBody(models.DeploymentGetResponse{
+			ID: ec.String("f1d329b0fb34470ba8b18361cabdd2bc"),
+			Resources: &models.DeploymentResources{
+				Kibana: []*models.KibanaResourceInfo{
+					{ID: ec.String("3531aaf988594efa87c1aabb7caed337"), RefID: ec.String("main-kibana")},
+					{ID: ec.String("4531aaf988594efa87c1aabb7caed337"), RefID: ec.String("other-kibana")},
+				},
+			},
+		}))
+	}
+	tests := []struct {
+		name   string
+		params GetParams
+		want   interface{}
+		err    error
+	}{
+		{
+			name: "obtains the resource by RefID",
+			params: GetParams{
+				API:          api.NewMock(getResponse()),
+				DeploymentID: "f1d329b0fb34470ba8b18361cabdd2bc",
+				RefID:        "other-kibana",
+			},
+			want: &models.KibanaResourceInfo{
+				ID: ec.String("4531aaf988594efa87c1aabb7caed337"), RefID: ec.String("other-kibana"),
+			},
+		},
+		{
+			name: "obtains the last resource when no RefID is set",
+			params: GetParams{
+				API:          api.NewMock(getResponse()),
+				DeploymentID: "f1d329b0fb34470ba8b18361cabdd2bc",
+			},
+			want: &models.KibanaResourceInfo{
+				ID: ec.String("4531aaf988594efa87c1aabb7caed337"), RefID: ec.String("other-kibana"),
+			},
+		},
+		{
+			name: "returns an error when the RefID is not found",
+			params: GetParams{
+				API:          api.NewMock(getResponse()),
+				DeploymentID: "f1d329b0fb34470ba8b18361cabdd2bc",
+				RefID:        "some",
+			},
+			err: errors.New("deployment get: kibana resource some not found"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getKindResource(tt.params, kind)
+			if !reflect.DeepEqual(err, tt.err) {
+				t.Errorf("getKindResource() error = %v, wantErr %v", err, tt.err)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getKindResource() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package monitoringapi
+
+import (
+	"context"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/deploymentapi/deputil"
+	"github.com/elastic/cloud-sdk-go/pkg/client/clusters_elasticsearch"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+)
+
+// EnableParams is consumed by Enable.
+type EnableParams struct {
+	*api.API
+
+	// SourceDeploymentID is the deployment which ships its monitoring data.
+	SourceDeploymentID string
+
+	// DestinationDeploymentID is the deployment which receives the data.
+	DestinationDeploymentID string
+
+	Region string
+}
+
+// Validate ensures the parameters are usable.
+func (params EnableParams) Validate() error {
+	var merr = multierror.NewPrefixed("deployment monitoring enable",
+		commonErrors(params.API, params.Region)...,
+	)
+
+	if len(params.SourceDeploymentID) != 32 {
+		merr = merr.Append(deputil.NewInvalidDeploymentIDError(params.SourceDeploymentID))
+	}
+
+	if len(params.DestinationDeploymentID) != 32 {
+		merr = merr.Append(deputil.NewInvalidDeploymentIDError(params.DestinationDeploymentID))
+	}
+
+	return merr.ErrorOrNil()
+}
+
+// Enable configures the source deployment to ship its monitoring data to the
+// destination deployment.
+func Enable(params EnableParams) error {
+	if err := params.Validate(); err != nil {
+		return err
+	}
+
+	source, err := getElasticsearchID(params.API, params.SourceDeploymentID)
+	if err != nil {
+		return err
+	}
+
+	destination, err := getElasticsearchID(params.API, params.DestinationDeploymentID)
+	if err != nil {
+		return err
+	}
+
+	return api.ReturnErrOnly(
+		params.V1API.ClustersElasticsearch.SetEsClusterMonitoring(
+			clusters_elasticsearch.NewSetEsClusterMonitoringParams().
+				WithContext(api.WithRegion(context.Background(), params.Region)).
+				WithClusterID(source).
+				WithDestClusterID(destination),
+			params.AuthWriter,
+		),
+	)
+}
+
+// DisableParams is consumed by Disable.
+type DisableParams struct {
+	*api.API
+
+	DeploymentID string
+	Region       string
+}
+
+// Validate ensures the parameters are usable.
+func (params DisableParams) Validate() error {
+	var merr = multierror.NewPrefixed("deployment monitoring disable",
+		commonErrors(params.API, params.Region)...,
+	)
+
+	if len(params.DeploymentID) != 32 {
+		merr = merr.Append(deputil.NewInvalidDeploymentIDError(params.DeploymentID))
+	}
+
+	return merr.ErrorOrNil()
+}
+
+// Disable stops the deployment from shipping its monitoring data.
+func Disable(params DisableParams) error {
+	if err := params.Validate(); err != nil {
+		return err
+	}
+
+	id, err := getElasticsearchID(params.API, params.DeploymentID)
+	if err != nil {
+		return err
+	}
+
+	return api.ReturnErrOnly(
+		params.V1API.ClustersElasticsearch.CancelEsClusterMonitoring(
+			clusters_elasticsearch.NewCancelEsClusterMonitoringParams().
+				WithContext(api.WithRegion(context.Background(), params.Region)).
+				WithClusterID(id),
+			params.AuthWriter,
+		),
+	)
+}
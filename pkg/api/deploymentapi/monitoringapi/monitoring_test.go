@@ -0,0 +1,176 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package monitoringapi
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/api/mock"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+)
+
+const (
+	sourceDeploymentID      = "f1d329b0fb34470ba8b18361cabdd2bc"
+	sourceClusterID         = "cde7b6b605424a54ce9d56316eab13a1"
+	destinationDeploymentID = "a4b3e2c1d0f94e8b8a7c6d5e4f3a2b1c"
+	destinationClusterID    = "9a8b7c6d5e4f4a3b2c1d0e9f8a7b6c5d"
+)
+
+func newDeploymentResponse(deploymentID, clusterID string) string {
+	return fmt.Sprintf(`{
+  "id": "%s",
+  "resources": {
+    "elasticsearch": [{
+      "id": "%s",
+      "ref_id": "main-elasticsearch",
+      "region": "us-east-1"
+    }]
+  }
+}`, deploymentID, clusterID)
+}
+
+func TestEnable(t *testing.T) {
+	type args struct {
+		params EnableParams
+	}
+	tests := []struct {
+		name string
+		args args
+		err  error
+	}{
+		{
+			name: "fails due to parameter validation",
+			err: multierror.NewPrefixed("deployment monitoring enable",
+				apierror.ErrMissingAPI,
+				errors.New("region not specified and is required for this operation"),
+				errors.New(`id "" is invalid`),
+				errors.New(`id "" is invalid`),
+			),
+		},
+		{
+			name: "fails when the source deployment has no elasticsearch resources",
+			args: args{params: EnableParams{
+				API: api.NewMock(
+					mock.New200Response(mock.NewStringBody(`{"id": "f1d329b0fb34470ba8b18361cabdd2bc", "resources": {}}`)),
+				),
+				SourceDeploymentID:      sourceDeploymentID,
+				DestinationDeploymentID: destinationDeploymentID,
+				Region:                  "us-east-1",
+			}},
+			err: errors.New("deployment f1d329b0fb34470ba8b18361cabdd2bc has no elasticsearch resources"),
+		},
+		{
+			name: "fails when the API returns an error",
+			args: args{params: EnableParams{
+				API: api.NewMock(
+					mock.New200Response(mock.NewStringBody(newDeploymentResponse(sourceDeploymentID, sourceClusterID))),
+					mock.New200Response(mock.NewStringBody(newDeploymentResponse(destinationDeploymentID, destinationClusterID))),
+					mock.New500Response(mock.NewStringBody(`{"error": "some error"}`)),
+				),
+				SourceDeploymentID:      sourceDeploymentID,
+				DestinationDeploymentID: destinationDeploymentID,
+				Region:                  "us-east-1",
+			}},
+			err: errors.New(`{"error": "some error"}`),
+		},
+		{
+			name: "enables monitoring from the source to the destination",
+			args: args{params: EnableParams{
+				API: api.NewMock(
+					mock.New200Response(mock.NewStringBody(newDeploymentResponse(sourceDeploymentID, sourceClusterID))),
+					mock.New200Response(mock.NewStringBody(newDeploymentResponse(destinationDeploymentID, destinationClusterID))),
+					mock.New202ResponseAssertion(&mock.RequestAssertion{
+						Header: api.DefaultWriteMockHeaders,
+						Method: "POST",
+						Host:   api.DefaultMockHost,
+						Path:   "/api/v1/regions/us-east-1/clusters/elasticsearch/cde7b6b605424a54ce9d56316eab13a1/monitoring/9a8b7c6d5e4f4a3b2c1d0e9f8a7b6c5d",
+					}, mock.NewStringBody(`{}`)),
+				),
+				SourceDeploymentID:      sourceDeploymentID,
+				DestinationDeploymentID: destinationDeploymentID,
+				Region:                  "us-east-1",
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Enable(tt.args.params)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestDisable(t *testing.T) {
+	type args struct {
+		params DisableParams
+	}
+	tests := []struct {
+		name string
+		args args
+		err  error
+	}{
+		{
+			name: "fails due to parameter validation",
+			err: multierror.NewPrefixed("deployment monitoring disable",
+				apierror.ErrMissingAPI,
+				errors.New("region not specified and is required for this operation"),
+				errors.New(`id "" is invalid`),
+			),
+		},
+		{
+			name: "fails when the API returns an error",
+			args: args{params: DisableParams{
+				API: api.NewMock(
+					mock.New200Response(mock.NewStringBody(newDeploymentResponse(sourceDeploymentID, sourceClusterID))),
+					mock.New500Response(mock.NewStringBody(`{"error": "some error"}`)),
+				),
+				DeploymentID: sourceDeploymentID,
+				Region:       "us-east-1",
+			}},
+			err: errors.New(`{"error": "some error"}`),
+		},
+		{
+			name: "disables monitoring",
+			args: args{params: DisableParams{
+				API: api.NewMock(
+					mock.New200Response(mock.NewStringBody(newDeploymentResponse(sourceDeploymentID, sourceClusterID))),
+					mock.New202ResponseAssertion(&mock.RequestAssertion{
+						Header: api.DefaultWriteMockHeaders,
+						Method: "DELETE",
+						Host:   api.DefaultMockHost,
+						Path:   "/api/v1/regions/us-east-1/clusters/elasticsearch/cde7b6b605424a54ce9d56316eab13a1/monitoring",
+					}, mock.NewStringBody(`{}`)),
+				),
+				DeploymentID: sourceDeploymentID,
+				Region:       "us-east-1",
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Disable(tt.args.params)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
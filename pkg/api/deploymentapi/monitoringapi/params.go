@@ -0,0 +1,61 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package monitoringapi
+
+import (
+	"fmt"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/api/deploymentapi"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+// commonErrors returns a list of errors for the parameters which are common to all
+// the monitoring operations.
+func commonErrors(a *api.API, region string) []error {
+	var errs []error
+	if a == nil {
+		errs = append(errs, apierror.ErrMissingAPI)
+	}
+
+	if err := ec.RequireRegionSet(region); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errs
+}
+
+// getElasticsearchID resolves the Elasticsearch resource ID of a deployment.
+func getElasticsearchID(a *api.API, deploymentID string) (string, error) {
+	res, err := deploymentapi.Get(deploymentapi.GetParams{
+		API:          a,
+		DeploymentID: deploymentID,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if res.Resources == nil || len(res.Resources.Elasticsearch) == 0 {
+		return "", fmt.Errorf(
+			"deployment %s has no elasticsearch resources", deploymentID,
+		)
+	}
+
+	return *res.Resources.Elasticsearch[0].ID, nil
+}
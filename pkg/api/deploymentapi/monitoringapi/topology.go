@@ -0,0 +1,187 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package monitoringapi
+
+import (
+	"context"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/api/deploymentapi"
+	"github.com/elastic/cloud-sdk-go/pkg/client/clusters_elasticsearch"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+// clustersPageSize is the number of Elasticsearch clusters obtained per
+// request when listing the clusters of a region.
+const clustersPageSize = 100
+
+// TopologyParams is consumed by GetTopology.
+type TopologyParams struct {
+	*api.API
+
+	Region string
+}
+
+// Validate ensures the parameters are usable.
+func (params TopologyParams) Validate() error {
+	return multierror.NewPrefixed("deployment monitoring topology",
+		commonErrors(params.API, params.Region)...,
+	).ErrorOrNil()
+}
+
+// Topology is the graph of monitoring relationships between the deployments
+// of a region.
+type Topology struct {
+	// Deployments contains every deployment which has an Elasticsearch
+	// resource in the region.
+	Deployments []DeploymentMonitoring `json:"deployments"`
+
+	// Unmonitored contains the IDs of the deployments which don't ship their
+	// monitoring data anywhere.
+	Unmonitored []string `json:"unmonitored"`
+}
+
+// DeploymentMonitoring is a node of the monitoring topology.
+type DeploymentMonitoring struct {
+	DeploymentID string `json:"deployment_id"`
+	Name         string `json:"name"`
+
+	// ShipsTo contains the IDs of the deployments which receive this
+	// deployment's monitoring data. When a destination cannot be matched to
+	// a deployment, its Elasticsearch cluster ID is used instead.
+	ShipsTo []string `json:"ships_to,omitempty"`
+
+	// ReceivesFrom contains the IDs of the deployments which ship their
+	// monitoring data to this deployment.
+	ReceivesFrom []string `json:"receives_from,omitempty"`
+
+	// Healthy reports the health of the monitoring configuration, it is nil
+	// when the deployment doesn't ship its monitoring data.
+	Healthy *bool `json:"healthy,omitempty"`
+}
+
+// GetTopology lists all the deployments and returns which deployments ship
+// their monitoring data to which, and which ones ship it nowhere.
+func GetTopology(params TopologyParams) (*Topology, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	list, err := deploymentapi.List(deploymentapi.ListParams{API: params.API})
+	if err != nil {
+		return nil, err
+	}
+
+	var nodes = make([]DeploymentMonitoring, 0, len(list.Deployments))
+	var deployments = make(map[string]string)
+	for _, d := range list.Deployments {
+		for _, r := range d.Resources {
+			if r.Kind == nil || *r.Kind != "elasticsearch" {
+				continue
+			}
+			if r.Region != nil && *r.Region != params.Region {
+				continue
+			}
+
+			deployments[*r.ID] = *d.ID
+			nodes = append(nodes, DeploymentMonitoring{
+				DeploymentID: *d.ID, Name: *d.Name,
+			})
+			break
+		}
+	}
+
+	var topology = Topology{
+		Deployments: nodes,
+		Unmonitored: make([]string, 0),
+	}
+	if len(nodes) == 0 {
+		return &topology, nil
+	}
+
+	clusters, err := listClusters(params)
+	if err != nil {
+		return nil, err
+	}
+
+	var index = make(map[string]int, len(nodes))
+	for i, n := range nodes {
+		index[n.DeploymentID] = i
+	}
+
+	for _, c := range clusters {
+		source, ok := deployments[*c.ClusterID]
+		if !ok || c.ElasticsearchMonitoringInfo == nil {
+			continue
+		}
+
+		var node = &topology.Deployments[index[source]]
+		node.Healthy = c.ElasticsearchMonitoringInfo.Healthy
+		for _, id := range c.ElasticsearchMonitoringInfo.DestinationClusterIds {
+			destination, ok := deployments[id]
+			if !ok {
+				node.ShipsTo = append(node.ShipsTo, id)
+				continue
+			}
+
+			node.ShipsTo = append(node.ShipsTo, destination)
+			var dest = &topology.Deployments[index[destination]]
+			dest.ReceivesFrom = append(dest.ReceivesFrom, source)
+		}
+	}
+
+	for i := range topology.Deployments {
+		var node = &topology.Deployments[i]
+		if len(node.ShipsTo) == 0 {
+			node.Healthy = nil
+			topology.Unmonitored = append(topology.Unmonitored, node.DeploymentID)
+		}
+	}
+
+	return &topology, nil
+}
+
+// listClusters pages through all the Elasticsearch clusters of the region,
+// since their monitoring information can't be filtered by cluster ID.
+func listClusters(params TopologyParams) ([]*models.ElasticsearchClusterInfo, error) {
+	var clusters []*models.ElasticsearchClusterInfo
+	for from := int64(0); ; {
+		res, err := params.V1API.ClustersElasticsearch.GetEsClusters(
+			clusters_elasticsearch.NewGetEsClustersParams().
+				WithContext(api.WithRegion(context.Background(), params.Region)).
+				WithFrom(ec.Int64(from)).
+				WithSize(ec.Int64(clustersPageSize)).
+				WithShowMetadata(ec.Bool(false)).
+				WithShowPlans(ec.Bool(false)),
+			params.AuthWriter,
+		)
+		if err != nil {
+			return nil, apierror.Unwrap(err)
+		}
+
+		clusters = append(clusters, res.Payload.ElasticsearchClusters...)
+		var returned = int64(len(res.Payload.ElasticsearchClusters))
+		from += returned
+		if returned == 0 || from >= int64(res.Payload.MatchCount) {
+			return clusters, nil
+		}
+	}
+}
@@ -0,0 +1,229 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package monitoringapi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/api/mock"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+const deploymentList = `{"deployments": [
+  {
+    "id": "f1d329b0fb34470ba8b18361cabdd2bc",
+    "name": "source",
+    "resources": [
+      {"id": "cde7b6b605424a54ce9d56316eab13a1", "kind": "elasticsearch", "ref_id": "main-elasticsearch", "region": "us-east-1"},
+      {"id": "0e1d2c3b4a594f8e8d7c6b5a4f3e2d1c", "kind": "kibana", "ref_id": "main-kibana", "region": "us-east-1"}
+    ]
+  },
+  {
+    "id": "a4b3e2c1d0f94e8b8a7c6d5e4f3a2b1c",
+    "name": "monitoring",
+    "resources": [
+      {"id": "9a8b7c6d5e4f4a3b2c1d0e9f8a7b6c5d", "kind": "elasticsearch", "ref_id": "main-elasticsearch", "region": "us-east-1"}
+    ]
+  },
+  {
+    "id": "5c4b3a2f1e0d4c9b8a7f6e5d4c3b2a1f",
+    "name": "other region",
+    "resources": [
+      {"id": "7f6e5d4c3b2a4f1e0d9c8b7a6f5e4d3c", "kind": "elasticsearch", "ref_id": "main-elasticsearch", "region": "us-west-1"}
+    ]
+  }
+]}`
+
+const clusterList = `{"return_count": 2, "elasticsearch_clusters": [
+  {
+    "cluster_id": "cde7b6b605424a54ce9d56316eab13a1",
+    "elasticsearch_monitoring_info": {
+      "destination_cluster_ids": ["9a8b7c6d5e4f4a3b2c1d0e9f8a7b6c5d", "1a2b3c4d5e6f4a7b8c9d0e1f2a3b4c5d"],
+      "source_cluster_ids": [],
+      "healthy": true
+    }
+  },
+  {
+    "cluster_id": "9a8b7c6d5e4f4a3b2c1d0e9f8a7b6c5d",
+    "elasticsearch_monitoring_info": {
+      "destination_cluster_ids": [],
+      "source_cluster_ids": ["cde7b6b605424a54ce9d56316eab13a1"],
+      "healthy": true
+    }
+  }
+]}`
+
+const clusterListFirstPage = `{"return_count": 2, "match_count": 3, "elasticsearch_clusters": [
+  {
+    "cluster_id": "2b3c4d5e6f7a4b8c9d0e1f2a3b4c5d6e",
+    "elasticsearch_monitoring_info": {"destination_cluster_ids": [], "source_cluster_ids": [], "healthy": true}
+  },
+  {
+    "cluster_id": "9a8b7c6d5e4f4a3b2c1d0e9f8a7b6c5d",
+    "elasticsearch_monitoring_info": {
+      "destination_cluster_ids": [],
+      "source_cluster_ids": ["cde7b6b605424a54ce9d56316eab13a1"],
+      "healthy": true
+    }
+  }
+]}`
+
+const clusterListSecondPage = `{"return_count": 1, "match_count": 3, "elasticsearch_clusters": [
+  {
+    "cluster_id": "cde7b6b605424a54ce9d56316eab13a1",
+    "elasticsearch_monitoring_info": {
+      "destination_cluster_ids": ["9a8b7c6d5e4f4a3b2c1d0e9f8a7b6c5d"],
+      "source_cluster_ids": [],
+      "healthy": false
+    }
+  }
+]}`
+
+func clusterListResponse(from, body string) mock.Response {
+	return mock.New200ResponseAssertion(&mock.RequestAssertion{
+		Header: api.DefaultReadMockHeaders,
+		Method: "GET",
+		Host:   api.DefaultMockHost,
+		Path:   "/api/v1/regions/us-east-1/clusters/elasticsearch",
+		Query: map[string][]string{
+			"convert_legacy_plans": {"false"},
+			"enrich_with_template": {"false"},
+			"from":                 {from},
+			"show_metadata":        {"false"},
+			"show_plan_defaults":   {"false"},
+			"show_plans":           {"false"},
+			"show_security":        {"false"},
+			"show_settings":        {"false"},
+			"show_system_alerts":   {"0"},
+			"size":                 {"100"},
+		},
+	}, mock.NewStringBody(body))
+}
+
+func TestGetTopology(t *testing.T) {
+	type args struct {
+		params TopologyParams
+	}
+	tests := []struct {
+		name string
+		args args
+		want *Topology
+		err  error
+	}{
+		{
+			name: "fails due to parameter validation",
+			err: multierror.NewPrefixed("deployment monitoring topology",
+				apierror.ErrMissingAPI,
+				errors.New("region not specified and is required for this operation"),
+			),
+		},
+		{
+			name: "fails when the cluster list returns an error",
+			args: args{params: TopologyParams{
+				API: api.NewMock(
+					mock.New200Response(mock.NewStringBody(deploymentList)),
+					mock.New500Response(mock.NewStringBody(`{"error": "some error"}`)),
+				),
+				Region: "us-east-1",
+			}},
+			err: errors.New(`{"error": "some error"}`),
+		},
+		{
+			name: "returns an empty topology when there are no deployments",
+			args: args{params: TopologyParams{
+				API: api.NewMock(
+					mock.New200Response(mock.NewStringBody(`{"deployments": []}`)),
+				),
+				Region: "us-east-1",
+			}},
+			want: &Topology{
+				Deployments: []DeploymentMonitoring{},
+				Unmonitored: []string{},
+			},
+		},
+		{
+			name: "returns the monitoring topology",
+			args: args{params: TopologyParams{
+				API: api.NewMock(
+					mock.New200Response(mock.NewStringBody(deploymentList)),
+					clusterListResponse("0", clusterList),
+				),
+				Region: "us-east-1",
+			}},
+			want: &Topology{
+				Deployments: []DeploymentMonitoring{
+					{
+						DeploymentID: "f1d329b0fb34470ba8b18361cabdd2bc",
+						Name:         "source",
+						ShipsTo: []string{
+							"a4b3e2c1d0f94e8b8a7c6d5e4f3a2b1c",
+							"1a2b3c4d5e6f4a7b8c9d0e1f2a3b4c5d",
+						},
+						Healthy: ec.Bool(true),
+					},
+					{
+						DeploymentID: "a4b3e2c1d0f94e8b8a7c6d5e4f3a2b1c",
+						Name:         "monitoring",
+						ReceivesFrom: []string{"f1d329b0fb34470ba8b18361cabdd2bc"},
+					},
+				},
+				Unmonitored: []string{"a4b3e2c1d0f94e8b8a7c6d5e4f3a2b1c"},
+			},
+		},
+		{
+			name: "pages through the region clusters when there are more than the deployments",
+			args: args{params: TopologyParams{
+				API: api.NewMock(
+					mock.New200Response(mock.NewStringBody(deploymentList)),
+					clusterListResponse("0", clusterListFirstPage),
+					clusterListResponse("2", clusterListSecondPage),
+				),
+				Region: "us-east-1",
+			}},
+			want: &Topology{
+				Deployments: []DeploymentMonitoring{
+					{
+						DeploymentID: "f1d329b0fb34470ba8b18361cabdd2bc",
+						Name:         "source",
+						ShipsTo:      []string{"a4b3e2c1d0f94e8b8a7c6d5e4f3a2b1c"},
+						Healthy:      ec.Bool(false),
+					},
+					{
+						DeploymentID: "a4b3e2c1d0f94e8b8a7c6d5e4f3a2b1c",
+						Name:         "monitoring",
+						ReceivesFrom: []string{"f1d329b0fb34470ba8b18361cabdd2bc"},
+					},
+				},
+				Unmonitored: []string{"a4b3e2c1d0f94e8b8a7c6d5e4f3a2b1c"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetTopology(tt.args.params)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
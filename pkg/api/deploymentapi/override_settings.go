@@ -17,7 +17,10 @@
 
 package deploymentapi
 
-import "github.com/elastic/cloud-sdk-go/pkg/models"
+import (
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/resourcekind"
+)
 
 // PayloadOverrides represent the override settings to
 type PayloadOverrides struct {
@@ -43,17 +46,13 @@ func setOverrides(req interface{}, overrides *PayloadOverrides) {
 		return
 	}
 
-	var apm []*models.ApmPayload
-	var appsearch []*models.AppSearchPayload
-	var elasticsearch []*models.ElasticsearchPayload
-	var kibana []*models.KibanaPayload
+	var resources interface{}
 	switch t := req.(type) {
 	case *models.DeploymentUpdateRequest:
 		if t.Resources == nil {
 			return
 		}
-		apm, appsearch = t.Resources.Apm, t.Resources.Appsearch
-		elasticsearch, kibana = t.Resources.Elasticsearch, t.Resources.Kibana
+		resources = t.Resources
 	case *models.DeploymentCreateRequest:
 		if overrides.Name != "" {
 			t.Name = overrides.Name
@@ -61,63 +60,19 @@ func setOverrides(req interface{}, overrides *PayloadOverrides) {
 		if t.Resources == nil {
 			return
 		}
-		apm, appsearch = t.Resources.Apm, t.Resources.Appsearch
-		elasticsearch, kibana = t.Resources.Elasticsearch, t.Resources.Kibana
-	}
-
-	overrideByPayload(
-		apm, appsearch, elasticsearch, kibana,
-		overrides.Region, overrides.Version,
-	)
-}
-
-// nolint
-func overrideByPayload(apm []*models.ApmPayload, appsearch []*models.AppSearchPayload,
-	elasticsearch []*models.ElasticsearchPayload, kibana []*models.KibanaPayload, region, version string) {
-	for _, resource := range apm {
-		if resource.Region == nil && region != "" {
-			resource.Region = &region
-		}
-
-		if version != "" {
-			if resource.Plan != nil && resource.Plan.Apm != nil {
-				resource.Plan.Apm.Version = version
-			}
-		}
-	}
-
-	for _, resource := range appsearch {
-		if resource.Region == nil && region != "" {
-			resource.Region = &region
-		}
-
-		if version != "" {
-			if resource.Plan != nil && resource.Plan.Appsearch != nil {
-				resource.Plan.Appsearch.Version = version
-			}
-		}
+		resources = t.Resources
+	default:
+		return
 	}
 
-	for _, resource := range elasticsearch {
-		if resource.Region == nil && region != "" {
-			resource.Region = &region
-		}
-
-		if version != "" {
-			if resource.Plan != nil && resource.Plan.Elasticsearch != nil {
-				resource.Plan.Elasticsearch.Version = version
+	for _, kind := range resourcekind.All() {
+		for _, payload := range kind.Payloads(resources) {
+			if overrides.Region != "" {
+				kind.SetRegion(payload, overrides.Region)
 			}
-		}
-	}
-
-	for _, resource := range kibana {
-		if resource.Region == nil && region != "" {
-			resource.Region = &region
-		}
 
-		if version != "" {
-			if resource.Plan != nil && resource.Plan.Kibana != nil {
-				resource.Plan.Kibana.Version = version
+			if overrides.Version != "" {
+				kind.SetVersion(payload, overrides.Version)
 			}
 		}
 	}
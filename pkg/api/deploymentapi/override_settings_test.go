@@ -206,6 +206,40 @@ func Test_setOverrides(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "set version and region override on enterprise search",
+			args: args{
+				overrides: &PayloadOverrides{
+					Version: "7.10.0",
+					Region:  eceRegion,
+				},
+				req: &models.DeploymentUpdateRequest{
+					Resources: &models.DeploymentUpdateResources{
+						EnterpriseSearch: []*models.EnterpriseSearchPayload{
+							{
+								Plan: &models.EnterpriseSearchPlan{
+									EnterpriseSearch: &models.EnterpriseSearchConfiguration{Version: "7.9.0"},
+								},
+							},
+							{Region: &overriddenRegion},
+						},
+					},
+				},
+			},
+			want: &models.DeploymentUpdateRequest{
+				Resources: &models.DeploymentUpdateResources{
+					EnterpriseSearch: []*models.EnterpriseSearchPayload{
+						{
+							Region: &eceRegion,
+							Plan: &models.EnterpriseSearchPlan{
+								EnterpriseSearch: &models.EnterpriseSearchConfiguration{Version: "7.10.0"},
+							},
+						},
+						{Region: &overriddenRegion},
+					},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
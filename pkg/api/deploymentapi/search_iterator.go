@@ -0,0 +1,110 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package deploymentapi
+
+import (
+	"context"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/client/deployments"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/util/pagination"
+)
+
+// SearchIteratorParams is consumed by NewSearchIterator.
+type SearchIteratorParams struct {
+	*api.API
+
+	// Request used to search the deployments, its From and Size are set by
+	// the iterator. When nil, all the deployments are matched. The request
+	// should be sorted for the pages to be consistent.
+	Request *models.SearchRequest
+
+	// PageSize is the number of deployments fetched on each request,
+	// defaults to pagination.DefaultSize.
+	PageSize int32
+}
+
+// Validate ensures the parameters are usable.
+func (params SearchIteratorParams) Validate() error {
+	if params.API == nil {
+		return apierror.ErrMissingAPI
+	}
+	return nil
+}
+
+// SearchIterator iterates over the deployments matching a search request,
+// lazily fetching a page of deployments when the previous one has been
+// consumed.
+//
+//	it, err := deploymentapi.NewSearchIterator(ctx, params)
+//	for it.Next() {
+//		fmt.Println(*it.Deployment().ID)
+//	}
+//	if err := it.Err(); err != nil {
+//		return err
+//	}
+type SearchIterator struct {
+	pager *pagination.Pager
+	page  []*models.DeploymentSearchResponse
+}
+
+// NewSearchIterator returns an iterator over the deployments matching the
+// search request. The iteration stops when the context is cancelled.
+func NewSearchIterator(ctx context.Context, params SearchIteratorParams) (*SearchIterator, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	var it SearchIterator
+	it.pager = pagination.New(ctx, params.PageSize, func(ctx context.Context, from, size int32) (int, error) {
+		var req models.SearchRequest
+		if params.Request != nil {
+			req = *params.Request
+		}
+		req.From, req.Size = from, size
+
+		res, err := params.V1API.Deployments.SearchDeployments(
+			deployments.NewSearchDeploymentsParams().
+				WithContext(ctx).
+				WithBody(&req),
+			params.AuthWriter,
+		)
+		if err != nil {
+			return 0, apierror.Unwrap(err)
+		}
+
+		it.page = res.Payload.Deployments
+		return len(it.page), nil
+	})
+
+	return &it, nil
+}
+
+// Next advances the iterator to the next deployment, returning false when
+// there are no more deployments or the iteration has failed.
+func (it *SearchIterator) Next() bool { return it.pager.Next() }
+
+// Deployment returns the current deployment.
+func (it *SearchIterator) Deployment() *models.DeploymentSearchResponse {
+	return it.page[it.pager.Index()]
+}
+
+// Err returns the error which stopped the iteration, if any.
+func (it *SearchIterator) Err() error { return it.pager.Err() }
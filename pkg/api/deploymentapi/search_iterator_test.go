@@ -0,0 +1,105 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package deploymentapi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/api/mock"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+func TestSearchIterator(t *testing.T) {
+	var pageResponse = func(from, size int32, ids ...string) mock.Response {
+		var res = models.DeploymentsSearchResponse{ReturnCount: ec.Int32(int32(len(ids)))}
+		for _, id := range ids {
+			res.Deployments = append(res.Deployments, &models.DeploymentSearchResponse{ID: ec.String(id)})
+		}
+		return mock.New200ResponseAssertion(&mock.RequestAssertion{
+			Header: api.DefaultWriteMockHeaders,
+			Method: "POST",
+			Host:   api.DefaultMockHost,
+			Path:   "/api/v1/deployments/_search",
+			Body: mock.NewStructBody(models.SearchRequest{
+				From: from, Size: size, Sort: []interface{}{"id"},
+			}),
+		}, mock.NewStructBody(res))
+	}
+	var request = &models.SearchRequest{Sort: []interface{}{"id"}}
+
+	t.Run("fails due to parameter validation", func(t *testing.T) {
+		got, err := NewSearchIterator(context.Background(), SearchIteratorParams{})
+		assert.Nil(t, got)
+		assert.Equal(t, apierror.ErrMissingAPI, err)
+	})
+
+	t.Run("iterates over all the pages", func(t *testing.T) {
+		it, err := NewSearchIterator(context.Background(), SearchIteratorParams{
+			API: api.NewMock(
+				pageResponse(0, 2, "a", "b"),
+				pageResponse(2, 2, "c"),
+			),
+			Request:  request,
+			PageSize: 2,
+		})
+		assert.NoError(t, err)
+
+		var got []string
+		for it.Next() {
+			got = append(got, *it.Deployment().ID)
+		}
+		assert.NoError(t, it.Err())
+		assert.Equal(t, []string{"a", "b", "c"}, got)
+		assert.Equal(t, int32(0), request.From)
+	})
+
+	t.Run("stops when a page fails to be fetched", func(t *testing.T) {
+		it, err := NewSearchIterator(context.Background(), SearchIteratorParams{
+			API: api.NewMock(
+				pageResponse(0, 2, "a", "b"),
+				mock.New500Response(mock.NewStringBody(`{"error": "some error"}`)),
+			),
+			Request:  request,
+			PageSize: 2,
+		})
+		assert.NoError(t, err)
+
+		var got []string
+		for it.Next() {
+			got = append(got, *it.Deployment().ID)
+		}
+		assert.EqualError(t, it.Err(), `{"error": "some error"}`)
+		assert.Equal(t, []string{"a", "b"}, got)
+	})
+
+	t.Run("stops when the context is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		it, err := NewSearchIterator(ctx, SearchIteratorParams{API: api.NewMock()})
+		assert.NoError(t, err)
+		assert.False(t, it.Next())
+		assert.Equal(t, context.Canceled, it.Err())
+	})
+}
@@ -0,0 +1,247 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package snapshotapi
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/api/deploymentapi"
+	"github.com/elastic/cloud-sdk-go/pkg/api/deploymentapi/deputil"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/util/slice"
+)
+
+var restoreStrategies = []string{"partial", "full", "recovery"}
+
+// RestoreParams is consumed by Restore. Either a DeploymentID to restore into
+// an existing deployment, or a Request to create a new deployment from, must
+// be set.
+type RestoreParams struct {
+	*api.API
+
+	// Deployment whose snapshot is restored. Defaults to DeploymentID when
+	// restoring into an existing deployment.
+	SourceDeploymentID string
+
+	// Optional snapshot to restore, defaults to the latest successful one.
+	SnapshotName string
+
+	// Optional indices to restore, all of the snapshot's indices are restored
+	// when empty.
+	Indices []string
+
+	// Optional restore strategy, one of "partial", "full" or "recovery".
+	Strategy string
+
+	// Existing deployment to restore the snapshot into.
+	DeploymentID string
+
+	// Request used to create a new deployment to restore the snapshot into.
+	// The snapshot restore is set in the plan of its first Elasticsearch
+	// resource.
+	Request *models.DeploymentCreateRequest
+
+	// Optional request ID used when creating a new deployment.
+	RequestID string
+}
+
+// Validate ensures the parameters are usable by Restore.
+func (params RestoreParams) Validate() error {
+	var merr = multierror.NewPrefixed("deployment snapshot restore")
+	if params.API == nil {
+		merr = merr.Append(apierror.ErrMissingAPI)
+	}
+
+	var existing, create = params.DeploymentID != "", params.Request != nil
+	if existing == create {
+		merr = merr.Append(errors.New("one of deployment id or request must be specified"))
+	}
+
+	if existing && len(params.DeploymentID) != 32 {
+		merr = merr.Append(deputil.NewInvalidDeploymentIDError(params.DeploymentID))
+	}
+
+	if create {
+		if params.Request.Resources == nil || len(params.Request.Resources.Elasticsearch) == 0 ||
+			params.Request.Resources.Elasticsearch[0].Plan == nil {
+			merr = merr.Append(errors.New("request must contain an elasticsearch resource plan"))
+		}
+
+		if params.SourceDeploymentID == "" {
+			merr = merr.Append(errors.New("source deployment id must be specified"))
+		}
+	}
+
+	if params.SourceDeploymentID != "" && len(params.SourceDeploymentID) != 32 {
+		merr = merr.Append(deputil.NewInvalidDeploymentIDError(params.SourceDeploymentID))
+	}
+
+	for _, index := range params.Indices {
+		if index == "" {
+			merr = merr.Append(errors.New("indices cannot contain empty names"))
+			break
+		}
+	}
+
+	if params.Strategy != "" && !slice.HasString(restoreStrategies, params.Strategy) {
+		merr = merr.Append(fmt.Errorf(`invalid restore strategy "%s"`, params.Strategy))
+	}
+
+	return merr.ErrorOrNil()
+}
+
+// RestoreResponse is returned by Restore. Only one of Created or Updated is
+// set, depending on whether a new deployment was created.
+type RestoreResponse struct {
+	DeploymentID string
+
+	Created *models.DeploymentCreateResponse
+	Updated *models.DeploymentUpdateResponse
+}
+
+// Restore restores a snapshot of a deployment's Elasticsearch resource into a
+// new deployment or into an existing one, optionally restoring only a subset
+// of the snapshot's indices. When restoring into an existing deployment, any
+// of the indices which are being restored must not be open in it.
+func Restore(params RestoreParams) (*RestoreResponse, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	if params.Request != nil {
+		return restoreNew(params)
+	}
+
+	return restoreExisting(params)
+}
+
+func restoreNew(params RestoreParams) (*RestoreResponse, error) {
+	sourceID, err := elasticsearchID(params.API, params.SourceDeploymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	setRestoreSnapshot(
+		params.Request.Resources.Elasticsearch[0].Plan,
+		newRestoreConfiguration(params, sourceID),
+	)
+
+	res, err := deploymentapi.Create(deploymentapi.CreateParams{
+		API:       params.API,
+		Request:   params.Request,
+		RequestID: params.RequestID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var response = RestoreResponse{Created: res}
+	if res.ID != nil {
+		response.DeploymentID = *res.ID
+	}
+
+	return &response, nil
+}
+
+func restoreExisting(params RestoreParams) (*RestoreResponse, error) {
+	res, err := deploymentapi.Get(deploymentapi.GetParams{
+		API:          params.API,
+		DeploymentID: params.DeploymentID,
+		QueryParams: deputil.QueryParams{
+			ShowPlans:    true,
+			ShowSettings: true,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sourceID, err := elasticsearchResourceID(res, params.DeploymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if params.SourceDeploymentID != "" && params.SourceDeploymentID != params.DeploymentID {
+		if sourceID, err = elasticsearchID(params.API, params.SourceDeploymentID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Only the Elasticsearch resources are sent, leaving the rest untouched.
+	var esOnly = *res
+	esOnly.Resources = &models.DeploymentResources{
+		Elasticsearch: res.Resources.Elasticsearch,
+	}
+	req, err := deploymentapi.NewUpdateRequest(&esOnly, deploymentapi.ConvertOptions{
+		DropTransient: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	setRestoreSnapshot(
+		req.Resources.Elasticsearch[0].Plan,
+		newRestoreConfiguration(params, sourceID),
+	)
+
+	updated, err := deploymentapi.Update(deploymentapi.UpdateParams{
+		API:          params.API,
+		DeploymentID: params.DeploymentID,
+		Request:      req,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &RestoreResponse{
+		DeploymentID: params.DeploymentID,
+		Updated:      updated,
+	}, nil
+}
+
+func newRestoreConfiguration(params RestoreParams, sourceID string) *models.RestoreSnapshotConfiguration {
+	var name = params.SnapshotName
+	if name == "" {
+		name = deploymentapi.LatestSuccessfulSnapshot
+	}
+
+	var config = models.RestoreSnapshotConfiguration{
+		SnapshotName:    &name,
+		SourceClusterID: sourceID,
+		Strategy:        params.Strategy,
+	}
+
+	if len(params.Indices) > 0 {
+		config.RestorePayload = &models.RestoreSnapshotAPIConfiguration{
+			Indices: params.Indices,
+		}
+	}
+
+	return &config
+}
+
+func setRestoreSnapshot(plan *models.ElasticsearchClusterPlan, config *models.RestoreSnapshotConfiguration) {
+	if plan.Transient == nil {
+		plan.Transient = new(models.TransientElasticsearchPlanConfiguration)
+	}
+	plan.Transient.RestoreSnapshot = config
+}
@@ -0,0 +1,306 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package snapshotapi
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/api/deploymentapi/deputil"
+	"github.com/elastic/cloud-sdk-go/pkg/api/mock"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+func TestRestore(t *testing.T) {
+	const targetID = "e3dac8bf3dc64c528c295a94d0f19a77"
+	const targetClusterID = "3ee11eb40eda22cac0cce259625c6734"
+	var newPlan = func() *models.ElasticsearchClusterPlan {
+		return &models.ElasticsearchClusterPlan{
+			Elasticsearch: &models.ElasticsearchConfiguration{Version: "7.10.0"},
+			ClusterTopology: []*models.ElasticsearchClusterTopologyElement{{
+				InstanceConfigurationID: "data.default",
+				ZoneCount:               1,
+				Size: &models.TopologySize{
+					Resource: ec.String("memory"), Value: ec.Int32(1024),
+				},
+			}},
+		}
+	}
+	var targetResponse = func() mock.Response {
+		var plan = newPlan()
+		plan.Transient = &models.TransientElasticsearchPlanConfiguration{
+			Strategy: &models.PlanStrategy{Rolling: &models.RollingStrategyConfig{}},
+		}
+		return mock.New200ResponseAssertion(&mock.RequestAssertion{
+			Header: api.DefaultReadMockHeaders,
+			Method: "GET",
+			Host:   api.DefaultMockHost,
+			Path:   "/api/v1/deployments/" + targetID,
+			Query: url.Values{
+				"convert_legacy_plans": {"false"},
+				"enrich_with_template": {"true"},
+				"show_metadata":        {"false"},
+				"show_plan_defaults":   {"false"},
+				"show_plan_history":    {"false"},
+				"show_plan_logs":       {"false"},
+				"show_plans":           {"true"},
+				"show_security":        {"false"},
+				"show_settings":        {"true"},
+				"show_system_alerts":   {"5"},
+			},
+		}, mock.NewStructBody(models.DeploymentGetResponse{
+			ID:   ec.String(targetID),
+			Name: ec.String("target"),
+			Resources: &models.DeploymentResources{
+				Elasticsearch: []*models.ElasticsearchResourceInfo{{
+					ID:     ec.String(targetClusterID),
+					RefID:  ec.String("main-elasticsearch"),
+					Region: ec.String("us-east-1"),
+					Info: &models.ElasticsearchClusterInfo{
+						ClusterName: ec.String("target"),
+						PlanInfo: &models.ElasticsearchClusterPlansInfo{
+							Current: &models.ElasticsearchClusterPlanInfo{Plan: plan},
+						},
+					},
+				}},
+				Kibana: []*models.KibanaResourceInfo{{
+					ID:    ec.String("2c2d4bfbbd1e4f2b90fe7b00cb5c2dd7"),
+					RefID: ec.String("main-kibana"),
+				}},
+			},
+		}))
+	}
+	var updateResponse = func(restore *models.RestoreSnapshotConfiguration) mock.Response {
+		var plan = newPlan()
+		plan.Transient = &models.TransientElasticsearchPlanConfiguration{
+			RestoreSnapshot: restore,
+		}
+		return mock.New200ResponseAssertion(&mock.RequestAssertion{
+			Header: api.DefaultWriteMockHeaders,
+			Method: "PUT",
+			Host:   api.DefaultMockHost,
+			Path:   "/api/v1/deployments/" + targetID,
+			Query: url.Values{
+				"hide_pruned_orphans": {"false"},
+				"skip_snapshot":       {"false"},
+				"validate_only":       {"false"},
+			},
+			Body: mock.NewStructBody(models.DeploymentUpdateRequest{
+				Name:         "target",
+				PruneOrphans: ec.Bool(false),
+				Resources: &models.DeploymentUpdateResources{
+					Elasticsearch: []*models.ElasticsearchPayload{{
+						DisplayName: "target",
+						RefID:       ec.String("main-elasticsearch"),
+						Region:      ec.String("us-east-1"),
+						Plan:        plan,
+					}},
+				},
+			}),
+		}, mock.NewStructBody(models.DeploymentUpdateResponse{ID: ec.String(targetID)}))
+	}
+	var createRequest = func() *models.DeploymentCreateRequest {
+		return &models.DeploymentCreateRequest{
+			Name: "restored",
+			Resources: &models.DeploymentCreateResources{
+				Elasticsearch: []*models.ElasticsearchPayload{{
+					RefID:  ec.String("main-elasticsearch"),
+					Region: ec.String("us-east-1"),
+					Plan:   newPlan(),
+				}},
+			},
+		}
+	}
+	var createResponse = func(restore *models.RestoreSnapshotConfiguration) mock.Response {
+		var req = createRequest()
+		req.Resources.Elasticsearch[0].Plan.Transient = &models.TransientElasticsearchPlanConfiguration{
+			RestoreSnapshot: restore,
+		}
+		return mock.New201ResponseAssertion(&mock.RequestAssertion{
+			Header: api.DefaultWriteMockHeaders,
+			Method: "POST",
+			Host:   api.DefaultMockHost,
+			Path:   "/api/v1/deployments",
+			Query:  url.Values{"validate_only": {"false"}},
+			Body:   mock.NewStructBody(req),
+		}, mock.NewStructBody(models.DeploymentCreateResponse{
+			ID: ec.String(targetID), Name: ec.String("restored"),
+		}))
+	}
+
+	tests := []struct {
+		name   string
+		params RestoreParams
+		want   *RestoreResponse
+		err    string
+	}{
+		{
+			name:   "fails on parameter validation",
+			params: RestoreParams{Indices: []string{""}, Strategy: "some"},
+			err: multierror.NewPrefixed("deployment snapshot restore",
+				apierror.ErrMissingAPI,
+				errors.New("one of deployment id or request must be specified"),
+				errors.New("indices cannot contain empty names"),
+				errors.New(`invalid restore strategy "some"`),
+			).Error(),
+		},
+		{
+			name: "fails on new deployment parameter validation",
+			params: RestoreParams{
+				API:                api.NewMock(),
+				Request:            &models.DeploymentCreateRequest{},
+				SourceDeploymentID: "invalid",
+			},
+			err: multierror.NewPrefixed("deployment snapshot restore",
+				errors.New("request must contain an elasticsearch resource plan"),
+				deputil.NewInvalidDeploymentIDError("invalid"),
+			).Error(),
+		},
+		{
+			name: "fails when both the deployment id and request are set",
+			params: RestoreParams{
+				API:                api.NewMock(),
+				DeploymentID:       targetID,
+				Request:            createRequest(),
+				SourceDeploymentID: deploymentID,
+			},
+			err: multierror.NewPrefixed("deployment snapshot restore",
+				errors.New("one of deployment id or request must be specified"),
+			).Error(),
+		},
+		{
+			name: "fails on a new deployment without a source",
+			params: RestoreParams{
+				API:     api.NewMock(),
+				Request: createRequest(),
+			},
+			err: multierror.NewPrefixed("deployment snapshot restore",
+				errors.New("source deployment id must be specified"),
+			).Error(),
+		},
+		{
+			name: "restores the latest snapshot into the same deployment",
+			params: RestoreParams{
+				API: api.NewMock(
+					targetResponse(),
+					updateResponse(&models.RestoreSnapshotConfiguration{
+						SnapshotName:    ec.String("__latest_success__"),
+						SourceClusterID: targetClusterID,
+					}),
+				),
+				DeploymentID: targetID,
+			},
+			want: &RestoreResponse{
+				DeploymentID: targetID,
+				Updated:      &models.DeploymentUpdateResponse{ID: ec.String(targetID)},
+			},
+		},
+		{
+			name: "restores filtered indices from another deployment into an existing one",
+			params: RestoreParams{
+				API: api.NewMock(
+					targetResponse(),
+					newGetResponse(deploymentID, clusterID),
+					updateResponse(&models.RestoreSnapshotConfiguration{
+						SnapshotName:    ec.String("my-snapshot"),
+						SourceClusterID: clusterID,
+						Strategy:        "partial",
+						RestorePayload: &models.RestoreSnapshotAPIConfiguration{
+							Indices: []string{"logs-*", "metrics"},
+						},
+					}),
+				),
+				DeploymentID:       targetID,
+				SourceDeploymentID: deploymentID,
+				SnapshotName:       "my-snapshot",
+				Indices:            []string{"logs-*", "metrics"},
+				Strategy:           "partial",
+			},
+			want: &RestoreResponse{
+				DeploymentID: targetID,
+				Updated:      &models.DeploymentUpdateResponse{ID: ec.String(targetID)},
+			},
+		},
+		{
+			name: "restores filtered indices into a new deployment",
+			params: RestoreParams{
+				API: api.NewMock(
+					newGetResponse(deploymentID, clusterID),
+					createResponse(&models.RestoreSnapshotConfiguration{
+						SnapshotName:    ec.String("__latest_success__"),
+						SourceClusterID: clusterID,
+						RestorePayload: &models.RestoreSnapshotAPIConfiguration{
+							Indices: []string{"logs-*"},
+						},
+					}),
+				),
+				Request:            createRequest(),
+				SourceDeploymentID: deploymentID,
+				Indices:            []string{"logs-*"},
+			},
+			want: &RestoreResponse{
+				DeploymentID: targetID,
+				Created: &models.DeploymentCreateResponse{
+					ID: ec.String(targetID), Name: ec.String("restored"),
+				},
+			},
+		},
+		{
+			name: "fails when the source deployment has no elasticsearch resources",
+			params: RestoreParams{
+				API:                api.NewMock(newGetResponse(deploymentID, "")),
+				Request:            createRequest(),
+				SourceDeploymentID: deploymentID,
+			},
+			err: "deployment snapshot: deployment f1d329b0fb34470ba8b18361cabdd2bc has no elasticsearch resources",
+		},
+		{
+			name: "fails when the target elasticsearch resource has no id",
+			params: RestoreParams{
+				API: api.NewMock(mock.New200Response(mock.NewStructBody(models.DeploymentGetResponse{
+					ID: ec.String(targetID),
+					Resources: &models.DeploymentResources{
+						Elasticsearch: []*models.ElasticsearchResourceInfo{{
+							RefID: ec.String("main-elasticsearch"),
+						}},
+					},
+				}))),
+				DeploymentID: targetID,
+			},
+			err: "deployment snapshot: deployment e3dac8bf3dc64c528c295a94d0f19a77 elasticsearch resource has no id",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Restore(tt.params)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
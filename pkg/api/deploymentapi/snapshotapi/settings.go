@@ -0,0 +1,151 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package snapshotapi
+
+import (
+	"context"
+	"errors"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/api/deploymentapi/deputil"
+	"github.com/elastic/cloud-sdk-go/pkg/client/clusters_elasticsearch"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+// GetSettingsParams is consumed by GetSettings.
+type GetSettingsParams struct {
+	*api.API
+
+	DeploymentID string
+	Region       string
+}
+
+// Validate ensures the parameters are usable by GetSettings.
+func (params GetSettingsParams) Validate() error {
+	var merr = multierror.NewPrefixed("deployment snapshot settings")
+	if params.API == nil {
+		merr = merr.Append(apierror.ErrMissingAPI)
+	}
+
+	if len(params.DeploymentID) != 32 {
+		merr = merr.Append(deputil.NewInvalidDeploymentIDError(params.DeploymentID))
+	}
+
+	if err := ec.RequireRegionSet(params.Region); err != nil {
+		merr = merr.Append(err)
+	}
+
+	return merr.ErrorOrNil()
+}
+
+// GetSettings returns the snapshot schedule and retention settings of a
+// deployment's Elasticsearch resource.
+func GetSettings(params GetSettingsParams) (*models.ClusterSnapshotSettings, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	clusterID, err := elasticsearchID(params.API, params.DeploymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := params.V1API.ClustersElasticsearch.GetEsClusterSnapshotSettings(
+		clusters_elasticsearch.NewGetEsClusterSnapshotSettingsParams().
+			WithContext(api.WithRegion(context.Background(), params.Region)).
+			WithClusterID(clusterID),
+		params.AuthWriter,
+	)
+	if err != nil {
+		return nil, apierror.Unwrap(err)
+	}
+
+	return res.Payload, nil
+}
+
+// UpdateSettingsParams is consumed by UpdateSettings.
+type UpdateSettingsParams struct {
+	*api.API
+
+	DeploymentID string
+	Region       string
+
+	// Settings to change, any unset fields are left as they are.
+	Settings *models.ClusterSnapshotSettings
+
+	// Optional settings version, used to avoid overwriting concurrent
+	// changes.
+	Version *int64
+}
+
+// Validate ensures the parameters are usable by UpdateSettings.
+func (params UpdateSettingsParams) Validate() error {
+	var merr = multierror.NewPrefixed("deployment snapshot settings")
+	if params.API == nil {
+		merr = merr.Append(apierror.ErrMissingAPI)
+	}
+
+	if len(params.DeploymentID) != 32 {
+		merr = merr.Append(deputil.NewInvalidDeploymentIDError(params.DeploymentID))
+	}
+
+	if err := ec.RequireRegionSet(params.Region); err != nil {
+		merr = merr.Append(err)
+	}
+
+	if params.Settings == nil {
+		merr = merr.Append(errors.New("settings cannot be empty"))
+	}
+
+	if params.Settings != nil && params.Settings.Retention != nil &&
+		params.Settings.Retention.Snapshots < 0 {
+		merr = merr.Append(errors.New("retention snapshots cannot be negative"))
+	}
+
+	return merr.ErrorOrNil()
+}
+
+// UpdateSettings changes the snapshot schedule and retention settings of a
+// deployment's Elasticsearch resource, returning the resulting settings.
+func UpdateSettings(params UpdateSettingsParams) (*models.ClusterSnapshotSettings, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	clusterID, err := elasticsearchID(params.API, params.DeploymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := params.V1API.ClustersElasticsearch.UpdateEsClusterSnapshotSettings(
+		clusters_elasticsearch.NewUpdateEsClusterSnapshotSettingsParams().
+			WithContext(api.WithRegion(context.Background(), params.Region)).
+			WithClusterID(clusterID).
+			WithVersion(params.Version).
+			WithBody(params.Settings),
+		params.AuthWriter,
+	)
+	if err != nil {
+		return nil, apierror.Unwrap(err)
+	}
+
+	return res.Payload, nil
+}
@@ -0,0 +1,159 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package snapshotapi
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/api/deploymentapi/deputil"
+	"github.com/elastic/cloud-sdk-go/pkg/api/mock"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+var snapshotSettings = models.ClusterSnapshotSettings{
+	Enabled:  ec.Bool(true),
+	Interval: "30m",
+	Retention: &models.ClusterSnapshotRetention{
+		MaxAge:    "7d",
+		Snapshots: 100,
+	},
+}
+
+func TestGetSettings(t *testing.T) {
+	tests := []struct {
+		name   string
+		params GetSettingsParams
+		want   *models.ClusterSnapshotSettings
+		err    string
+	}{
+		{
+			name:   "fails on parameter validation",
+			params: GetSettingsParams{},
+			err: multierror.NewPrefixed("deployment snapshot settings",
+				apierror.ErrMissingAPI,
+				deputil.NewInvalidDeploymentIDError(""),
+				errors.New("region not specified and is required for this operation"),
+			).Error(),
+		},
+		{
+			name: "returns the snapshot settings",
+			params: GetSettingsParams{
+				API: api.NewMock(
+					newGetResponse(deploymentID, clusterID),
+					mock.New200ResponseAssertion(&mock.RequestAssertion{
+						Header: api.DefaultReadMockHeaders,
+						Method: "GET",
+						Host:   api.DefaultMockHost,
+						Path:   clusterPath + "/snapshot/settings",
+					}, mock.NewStructBody(snapshotSettings)),
+				),
+				DeploymentID: deploymentID,
+				Region:       "us-east-1",
+			},
+			want: &snapshotSettings,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetSettings(tt.params)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestUpdateSettings(t *testing.T) {
+	var retention = models.ClusterSnapshotSettings{
+		Retention: &models.ClusterSnapshotRetention{MaxAge: "7d", Snapshots: 100},
+	}
+	tests := []struct {
+		name   string
+		params UpdateSettingsParams
+		want   *models.ClusterSnapshotSettings
+		err    string
+	}{
+		{
+			name:   "fails on parameter validation",
+			params: UpdateSettingsParams{},
+			err: multierror.NewPrefixed("deployment snapshot settings",
+				apierror.ErrMissingAPI,
+				deputil.NewInvalidDeploymentIDError(""),
+				errors.New("region not specified and is required for this operation"),
+				errors.New("settings cannot be empty"),
+			).Error(),
+		},
+		{
+			name: "fails on negative retention",
+			params: UpdateSettingsParams{
+				API:          api.NewMock(),
+				DeploymentID: deploymentID,
+				Region:       "us-east-1",
+				Settings: &models.ClusterSnapshotSettings{
+					Retention: &models.ClusterSnapshotRetention{Snapshots: -1},
+				},
+			},
+			err: multierror.NewPrefixed("deployment snapshot settings",
+				errors.New("retention snapshots cannot be negative"),
+			).Error(),
+		},
+		{
+			name: "updates the snapshot retention",
+			params: UpdateSettingsParams{
+				API: api.NewMock(
+					newGetResponse(deploymentID, clusterID),
+					mock.New200ResponseAssertion(&mock.RequestAssertion{
+						Header: api.DefaultWriteMockHeaders,
+						Method: "PATCH",
+						Host:   api.DefaultMockHost,
+						Path:   clusterPath + "/snapshot/settings",
+						Query:  url.Values{"version": {"3"}},
+						Body:   mock.NewStructBody(retention),
+					}, mock.NewStructBody(snapshotSettings)),
+				),
+				DeploymentID: deploymentID,
+				Region:       "us-east-1",
+				Settings:     &retention,
+				Version:      ec.Int64(3),
+			},
+			want: &snapshotSettings,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UpdateSettings(tt.params)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
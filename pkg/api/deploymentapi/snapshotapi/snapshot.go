@@ -0,0 +1,293 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package snapshotapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/api/deploymentapi"
+	"github.com/elastic/cloud-sdk-go/pkg/api/deploymentapi/deputil"
+	"github.com/elastic/cloud-sdk-go/pkg/api/deploymentapi/esproxyapi"
+	"github.com/elastic/cloud-sdk-go/pkg/client/clusters_elasticsearch"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+const (
+	// DefaultRepository is the snapshot repository which is registered in
+	// the Elasticsearch resource of every deployment.
+	DefaultRepository = "found-snapshots"
+
+	// DefaultPollFrequency is used by Wait when no PollFrequency is set.
+	DefaultPollFrequency = 10 * time.Second
+
+	// StateInProgress is the state of a snapshot which hasn't finished yet.
+	StateInProgress = "IN_PROGRESS"
+
+	// StateSuccess is the state of a snapshot which has finished successfully.
+	StateSuccess = "SUCCESS"
+)
+
+// TakeParams is consumed by Take.
+type TakeParams struct {
+	*api.API
+
+	DeploymentID string
+	Region       string
+
+	// Optional snapshot name, generated by the API when empty.
+	Name string
+
+	// Optional snapshot repository, defaults to DefaultRepository.
+	RepositoryName string
+
+	// When set, waits until the snapshot has finished.
+	Wait          bool
+	PollFrequency time.Duration
+	Timeout       time.Duration
+}
+
+// Validate ensures the parameters are usable by Take.
+func (params TakeParams) Validate() error {
+	var merr = multierror.NewPrefixed("deployment snapshot")
+	if params.API == nil {
+		merr = merr.Append(apierror.ErrMissingAPI)
+	}
+
+	if len(params.DeploymentID) != 32 {
+		merr = merr.Append(deputil.NewInvalidDeploymentIDError(params.DeploymentID))
+	}
+
+	if err := ec.RequireRegionSet(params.Region); err != nil {
+		merr = merr.Append(err)
+	}
+
+	merr = merr.Append(validateWaitDurations(params.PollFrequency, params.Timeout)...)
+
+	return merr.ErrorOrNil()
+}
+
+// TakeResponse is returned by Take.
+type TakeResponse struct {
+	// Name of the snapshot which has been taken.
+	Name string
+
+	// Snapshot is the finished snapshot, only set when waiting for it.
+	Snapshot *esproxyapi.Snapshot
+}
+
+// Take takes an on-demand snapshot of a deployment's Elasticsearch resource,
+// optionally waiting until the snapshot has finished successfully.
+func Take(params TakeParams) (*TakeResponse, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	clusterID, err := elasticsearchID(params.API, params.DeploymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := params.V1API.ClustersElasticsearch.SnapshotEsCluster(
+		clusters_elasticsearch.NewSnapshotEsClusterParams().
+			WithContext(api.WithRegion(context.Background(), params.Region)).
+			WithClusterID(clusterID).
+			WithBody(&models.ClusterSnapshotRequest{
+				Name:           params.Name,
+				RepositoryName: params.RepositoryName,
+			}),
+		params.AuthWriter,
+	)
+	if err != nil {
+		return nil, apierror.Unwrap(err)
+	}
+
+	var response = TakeResponse{Name: params.Name}
+	if res.Payload != nil && res.Payload.Name != nil {
+		response.Name = *res.Payload.Name
+	}
+
+	if !params.Wait {
+		return &response, nil
+	}
+
+	snapshot, err := Wait(WaitParams{
+		API:            params.API,
+		DeploymentID:   params.DeploymentID,
+		Region:         params.Region,
+		Name:           response.Name,
+		RepositoryName: params.RepositoryName,
+		PollFrequency:  params.PollFrequency,
+		Timeout:        params.Timeout,
+	})
+	response.Snapshot = snapshot
+
+	return &response, err
+}
+
+// WaitParams is consumed by Wait.
+type WaitParams struct {
+	*api.API
+
+	DeploymentID string
+	Region       string
+	Name         string
+
+	// Optional snapshot repository, defaults to DefaultRepository.
+	RepositoryName string
+
+	// Optional frequency at which the snapshot state is polled, defaults to
+	// DefaultPollFrequency.
+	PollFrequency time.Duration
+
+	// Optional time after which to stop waiting, no limit when unset.
+	Timeout time.Duration
+}
+
+// Validate ensures the parameters are usable by Wait.
+func (params WaitParams) Validate() error {
+	var merr = multierror.NewPrefixed("deployment snapshot wait")
+	if params.API == nil {
+		merr = merr.Append(apierror.ErrMissingAPI)
+	}
+
+	if len(params.DeploymentID) != 32 {
+		merr = merr.Append(deputil.NewInvalidDeploymentIDError(params.DeploymentID))
+	}
+
+	if err := ec.RequireRegionSet(params.Region); err != nil {
+		merr = merr.Append(err)
+	}
+
+	if params.Name == "" {
+		merr = merr.Append(errors.New("snapshot name cannot be empty"))
+	}
+
+	merr = merr.Append(validateWaitDurations(params.PollFrequency, params.Timeout)...)
+
+	return merr.ErrorOrNil()
+}
+
+// Wait polls the state of a snapshot through the Elasticsearch proxy until it
+// has finished, returning an error when it didn't finish successfully or the
+// timeout has been reached.
+func Wait(params WaitParams) (*esproxyapi.Snapshot, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	client, err := esproxyapi.NewClient(esproxyapi.Params{
+		API:    params.API,
+		Region: params.Region,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var repository = params.RepositoryName
+	if repository == "" {
+		repository = DefaultRepository
+	}
+
+	var frequency = params.PollFrequency
+	if frequency == 0 {
+		frequency = DefaultPollFrequency
+	}
+
+	var ctx, cancel = context.WithCancel(context.Background())
+	if params.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), params.Timeout)
+	}
+	defer cancel()
+
+	for {
+		snapshot, err := client.Snapshot(ctx, params.DeploymentID, repository, params.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		switch snapshot.State {
+		case StateSuccess:
+			return snapshot, nil
+		case StateInProgress:
+		default:
+			return snapshot, fmt.Errorf(
+				"deployment snapshot: snapshot %s finished with state %s",
+				params.Name, snapshot.State,
+			)
+		}
+
+		select {
+		case <-ctx.Done():
+			return snapshot, fmt.Errorf(
+				"deployment snapshot: timed out waiting for snapshot %s to finish", params.Name,
+			)
+		case <-time.After(frequency):
+		}
+	}
+}
+
+func validateWaitDurations(frequency, timeout time.Duration) []error {
+	var errs []error
+	if frequency < 0 {
+		errs = append(errs, errors.New("poll frequency cannot be negative"))
+	}
+
+	if timeout < 0 {
+		errs = append(errs, errors.New("timeout cannot be negative"))
+	}
+
+	return errs
+}
+
+// elasticsearchID resolves the ID of a deployment's Elasticsearch resource.
+func elasticsearchID(instance *api.API, deploymentID string) (string, error) {
+	res, err := deploymentapi.Get(deploymentapi.GetParams{
+		API:          instance,
+		DeploymentID: deploymentID,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return elasticsearchResourceID(res, deploymentID)
+}
+
+// elasticsearchResourceID returns the ID of the first Elasticsearch resource
+// found in the deployment, erroring when there's none or it has no ID.
+func elasticsearchResourceID(res *models.DeploymentGetResponse, deploymentID string) (string, error) {
+	if res.Resources == nil || len(res.Resources.Elasticsearch) == 0 {
+		return "", fmt.Errorf(
+			"deployment snapshot: deployment %s has no elasticsearch resources", deploymentID,
+		)
+	}
+
+	if id := res.Resources.Elasticsearch[0].ID; id != nil && *id != "" {
+		return *id, nil
+	}
+
+	return "", fmt.Errorf(
+		"deployment snapshot: deployment %s elasticsearch resource has no id", deploymentID,
+	)
+}
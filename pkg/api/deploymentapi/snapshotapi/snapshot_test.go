@@ -0,0 +1,284 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package snapshotapi
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/api/deploymentapi/deputil"
+	"github.com/elastic/cloud-sdk-go/pkg/api/deploymentapi/esproxyapi"
+	"github.com/elastic/cloud-sdk-go/pkg/api/mock"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+const (
+	deploymentID = "f1d329b0fb34470ba8b18361cabdd2bc"
+	clusterID    = "cde7b6b605424a54ce9d56316eab13a1"
+	clusterPath  = "/api/v1/regions/us-east-1/clusters/elasticsearch/" + clusterID
+)
+
+var getQuery = url.Values{
+	"convert_legacy_plans": {"false"},
+	"enrich_with_template": {"true"},
+	"show_metadata":        {"false"},
+	"show_plan_defaults":   {"false"},
+	"show_plan_history":    {"false"},
+	"show_plan_logs":       {"false"},
+	"show_plans":           {"false"},
+	"show_security":        {"false"},
+	"show_settings":        {"false"},
+	"show_system_alerts":   {"5"},
+}
+
+func newGetResponse(id, esID string) mock.Response {
+	var resources = new(models.DeploymentResources)
+	if esID != "" {
+		resources.Elasticsearch = []*models.ElasticsearchResourceInfo{{
+			ID:     ec.String(esID),
+			RefID:  ec.String("main-elasticsearch"),
+			Region: ec.String("us-east-1"),
+		}}
+	}
+
+	return mock.New200ResponseAssertion(&mock.RequestAssertion{
+		Header: api.DefaultReadMockHeaders,
+		Method: "GET",
+		Host:   api.DefaultMockHost,
+		Path:   "/api/v1/deployments/" + id,
+		Query:  getQuery,
+	}, mock.NewStructBody(models.DeploymentGetResponse{
+		ID:        ec.String(id),
+		Resources: resources,
+	}))
+}
+
+func newSnapshotStateResponse(name, state string) mock.Response {
+	return mock.New200ResponseAssertion(&mock.RequestAssertion{
+		Header: proxyHeaders(),
+		Method: "GET",
+		Host:   api.DefaultMockHost,
+		Path:   clusterPath + "/proxy/_snapshot/found-snapshots/" + name,
+	}, mock.NewStructBody(map[string][]esproxyapi.Snapshot{
+		"snapshots": {{Snapshot: name, State: state}},
+	}))
+}
+
+func proxyHeaders() map[string][]string {
+	var headers = map[string][]string{"X-Management-Request": {"true"}}
+	for k, v := range api.DefaultReadMockHeaders {
+		headers[k] = v
+	}
+	return headers
+}
+
+func TestTake(t *testing.T) {
+	var snapshotResponse = func(body models.ClusterSnapshotRequest) mock.Response {
+		return mock.New202ResponseAssertion(&mock.RequestAssertion{
+			Header: api.DefaultWriteMockHeaders,
+			Method: "POST",
+			Host:   api.DefaultMockHost,
+			Path:   clusterPath + "/_snapshot",
+			Body:   mock.NewStructBody(body),
+		}, mock.NewStructBody(models.ClusterSnapshotResponse{
+			Name: ec.String("my-snapshot"),
+		}))
+	}
+	type args struct {
+		params TakeParams
+	}
+	tests := []struct {
+		name string
+		args args
+		want *TakeResponse
+		err  string
+	}{
+		{
+			name: "fails on parameter validation",
+			args: args{params: TakeParams{PollFrequency: -1, Timeout: -1}},
+			err: multierror.NewPrefixed("deployment snapshot",
+				apierror.ErrMissingAPI,
+				deputil.NewInvalidDeploymentIDError(""),
+				errors.New("region not specified and is required for this operation"),
+				errors.New("poll frequency cannot be negative"),
+				errors.New("timeout cannot be negative"),
+			).Error(),
+		},
+		{
+			name: "fails when the deployment has no elasticsearch resources",
+			args: args{params: TakeParams{
+				API:          api.NewMock(newGetResponse(deploymentID, "")),
+				DeploymentID: deploymentID,
+				Region:       "us-east-1",
+			}},
+			err: "deployment snapshot: deployment f1d329b0fb34470ba8b18361cabdd2bc has no elasticsearch resources",
+		},
+		{
+			name: "fails when the elasticsearch resource has no id",
+			args: args{params: TakeParams{
+				API: api.NewMock(mock.New200Response(mock.NewStructBody(models.DeploymentGetResponse{
+					ID: ec.String(deploymentID),
+					Resources: &models.DeploymentResources{
+						Elasticsearch: []*models.ElasticsearchResourceInfo{{
+							RefID: ec.String("main-elasticsearch"),
+						}},
+					},
+				}))),
+				DeploymentID: deploymentID,
+				Region:       "us-east-1",
+			}},
+			err: "deployment snapshot: deployment f1d329b0fb34470ba8b18361cabdd2bc elasticsearch resource has no id",
+		},
+		{
+			name: "fails when the snapshot can't be taken",
+			args: args{params: TakeParams{
+				API: api.NewMock(
+					newGetResponse(deploymentID, clusterID),
+					mock.NewErrorResponse(500, mock.APIError{
+						Code: "clusters.snapshot_failed", Message: "snapshot failed",
+					}),
+				),
+				DeploymentID: deploymentID,
+				Region:       "us-east-1",
+			}},
+			err: multierror.NewPrefixed("api error",
+				errors.New("clusters.snapshot_failed: snapshot failed"),
+			).Error(),
+		},
+		{
+			name: "takes a snapshot without waiting",
+			args: args{params: TakeParams{
+				API: api.NewMock(
+					newGetResponse(deploymentID, clusterID),
+					snapshotResponse(models.ClusterSnapshotRequest{
+						Name: "my-snapshot", RepositoryName: "found-snapshots",
+					}),
+				),
+				DeploymentID:   deploymentID,
+				Region:         "us-east-1",
+				Name:           "my-snapshot",
+				RepositoryName: "found-snapshots",
+			}},
+			want: &TakeResponse{Name: "my-snapshot"},
+		},
+		{
+			name: "takes a snapshot and waits for it",
+			args: args{params: TakeParams{
+				API: api.NewMock(
+					newGetResponse(deploymentID, clusterID),
+					snapshotResponse(models.ClusterSnapshotRequest{}),
+					newGetResponse(deploymentID, clusterID),
+					newSnapshotStateResponse("my-snapshot", StateInProgress),
+					newSnapshotStateResponse("my-snapshot", StateSuccess),
+				),
+				DeploymentID:  deploymentID,
+				Region:        "us-east-1",
+				Wait:          true,
+				PollFrequency: 1,
+			}},
+			want: &TakeResponse{Name: "my-snapshot", Snapshot: &esproxyapi.Snapshot{
+				Snapshot: "my-snapshot", State: StateSuccess,
+			}},
+		},
+		{
+			name: "returns an error when the snapshot fails",
+			args: args{params: TakeParams{
+				API: api.NewMock(
+					newGetResponse(deploymentID, clusterID),
+					snapshotResponse(models.ClusterSnapshotRequest{}),
+					newGetResponse(deploymentID, clusterID),
+					newSnapshotStateResponse("my-snapshot", "PARTIAL"),
+				),
+				DeploymentID:  deploymentID,
+				Region:        "us-east-1",
+				Wait:          true,
+				PollFrequency: 1,
+			}},
+			want: &TakeResponse{Name: "my-snapshot", Snapshot: &esproxyapi.Snapshot{
+				Snapshot: "my-snapshot", State: "PARTIAL",
+			}},
+			err: "deployment snapshot: snapshot my-snapshot finished with state PARTIAL",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Take(tt.args.params)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestWait(t *testing.T) {
+	tests := []struct {
+		name   string
+		params WaitParams
+		want   *esproxyapi.Snapshot
+		err    string
+	}{
+		{
+			name:   "fails on parameter validation",
+			params: WaitParams{},
+			err: multierror.NewPrefixed("deployment snapshot wait",
+				apierror.ErrMissingAPI,
+				deputil.NewInvalidDeploymentIDError(""),
+				errors.New("region not specified and is required for this operation"),
+				errors.New("snapshot name cannot be empty"),
+			).Error(),
+		},
+		{
+			name: "times out while the snapshot is in progress",
+			params: WaitParams{
+				API: api.NewMock(
+					newGetResponse(deploymentID, clusterID),
+					newSnapshotStateResponse("my-snapshot", StateInProgress),
+				),
+				DeploymentID:  deploymentID,
+				Region:        "us-east-1",
+				Name:          "my-snapshot",
+				PollFrequency: time.Minute,
+				Timeout:       time.Millisecond,
+			},
+			want: &esproxyapi.Snapshot{Snapshot: "my-snapshot", State: StateInProgress},
+			err:  "deployment snapshot: timed out waiting for snapshot my-snapshot to finish",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Wait(tt.params)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
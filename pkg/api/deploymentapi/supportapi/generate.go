@@ -0,0 +1,298 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package supportapi
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/api/deploymentapi"
+	"github.com/elastic/cloud-sdk-go/pkg/api/deploymentapi/deputil"
+	"github.com/elastic/cloud-sdk-go/pkg/client/clusters_elasticsearch"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/resourcekind"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+// ManifestFile is the name of the file which contains the bundle manifest.
+const ManifestFile = "manifest.json"
+
+// GenerateParams is consumed by the Generate function.
+type GenerateParams struct {
+	*api.API
+
+	DeploymentID string
+	Region       string
+
+	// Directory where the bundle files are written. Either Directory or
+	// Writer must be specified.
+	Directory string
+
+	// Writer where the bundle is written as a zip archive. Either Directory or
+	// Writer must be specified.
+	Writer io.Writer
+
+	// Optional logs retrieval start date in the YYYY-MM-DD[THH[:mm]] format.
+	LogsDate string
+}
+
+// Validate ensures the parameters are usable by the consuming function.
+func (params GenerateParams) Validate() error {
+	var merr = multierror.NewPrefixed("deployment support bundle")
+	if params.API == nil {
+		merr = merr.Append(apierror.ErrMissingAPI)
+	}
+
+	if len(params.DeploymentID) != 32 {
+		merr = merr.Append(deputil.NewInvalidDeploymentIDError(params.DeploymentID))
+	}
+
+	if err := ec.RequireRegionSet(params.Region); err != nil {
+		merr = merr.Append(err)
+	}
+
+	if params.Directory == "" && params.Writer == nil {
+		merr = merr.Append(errors.New("one of directory or writer must be specified"))
+	}
+
+	if params.Directory != "" && params.Writer != nil {
+		merr = merr.Append(errors.New("only one of directory or writer can be specified"))
+	}
+
+	return merr.ErrorOrNil()
+}
+
+// Manifest is written as part of the support bundle, containing the deployment
+// information with its plan history and the outcome for each of the resources.
+type Manifest struct {
+	Deployment *models.DeploymentGetResponse `json:"deployment"`
+	Resources  []ResourceReport              `json:"resources"`
+}
+
+// ResourceReport contains the files which were generated for a deployment
+// resource and any errors which occurred while generating them.
+type ResourceReport struct {
+	Kind   string   `json:"kind"`
+	RefID  string   `json:"ref_id"`
+	ID     string   `json:"id"`
+	Files  []string `json:"files,omitempty"`
+	Errors []string `json:"errors,omitempty"`
+}
+
+// generator obtains a single support artifact for a resource.
+type generator struct {
+	name     string
+	generate func(params GenerateParams, id string) ([]byte, error)
+}
+
+// generators contains the support artifacts which can be obtained for each of
+// the resource kinds. Kinds which aren't present don't support any.
+var generators = map[string][]generator{
+	resourcekind.Elasticsearch: {
+		{name: "diagnostics", generate: elasticsearchDiagnostics},
+		{name: "logs", generate: elasticsearchLogs},
+	},
+}
+
+type resource struct {
+	kind, refID, id string
+}
+
+type artifact struct {
+	name string
+	data []byte
+}
+
+// Generate obtains the diagnostics and logs for all of the deployment
+// resources which support them, writing them to either the directory or the
+// writer along with a manifest. Resources are processed concurrently and
+// failures are reported per resource, not stopping the rest of resources from
+// being processed. The returned error contains all of the resource failures.
+func Generate(params GenerateParams) (*Manifest, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	res, err := deploymentapi.Get(deploymentapi.GetParams{
+		API:          params.API,
+		DeploymentID: params.DeploymentID,
+		QueryParams: deputil.QueryParams{
+			ShowPlans:       true,
+			ShowPlanHistory: true,
+			ShowMetadata:    true,
+			ShowSettings:    true,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var resources = supportedResources(res)
+	var reports = make([]ResourceReport, len(resources))
+	var artifacts = make([][]artifact, len(resources))
+
+	var wg sync.WaitGroup
+	for i, r := range resources {
+		wg.Add(1)
+		go func(i int, r resource) {
+			defer wg.Done()
+			reports[i], artifacts[i] = generate(params, r)
+		}(i, r)
+	}
+	wg.Wait()
+
+	var manifest = Manifest{Deployment: res, Resources: reports}
+	var merr = multierror.NewPrefixed("deployment support bundle")
+	for _, report := range reports {
+		for _, e := range report.Errors {
+			merr = merr.Append(fmt.Errorf("%s %s: %s", report.Kind, report.RefID, e))
+		}
+	}
+
+	if err := write(params, manifest, artifacts); err != nil {
+		return &manifest, merr.Append(err)
+	}
+
+	return &manifest, merr.ErrorOrNil()
+}
+
+func supportedResources(res *models.DeploymentGetResponse) []resource {
+	var resources []resource
+	if res.Resources == nil {
+		return resources
+	}
+
+	for _, es := range res.Resources.Elasticsearch {
+		resources = append(resources, resource{
+			kind: resourcekind.Elasticsearch, refID: *es.RefID, id: *es.ID,
+		})
+	}
+
+	return resources
+}
+
+func generate(params GenerateParams, r resource) (ResourceReport, []artifact) {
+	var report = ResourceReport{Kind: r.kind, RefID: r.refID, ID: r.id}
+	var artifacts []artifact
+	for _, g := range generators[r.kind] {
+		data, err := g.generate(params, r.id)
+		if err != nil {
+			report.Errors = append(report.Errors,
+				fmt.Sprintf("failed generating %s: %s", g.name, err),
+			)
+			continue
+		}
+
+		var name = fmt.Sprintf("%s-%s-%s.zip", r.kind, r.refID, g.name)
+		report.Files = append(report.Files, name)
+		artifacts = append(artifacts, artifact{name: name, data: data})
+	}
+
+	return report, artifacts
+}
+
+func elasticsearchDiagnostics(params GenerateParams, id string) ([]byte, error) {
+	res, err := params.V1API.ClustersElasticsearch.GenerateEsClusterDiagnostics(
+		clusters_elasticsearch.NewGenerateEsClusterDiagnosticsParams().
+			WithContext(api.WithRegion(context.Background(), params.Region)).
+			WithClusterID(id),
+		params.AuthWriter,
+	)
+	if err != nil {
+		return nil, apierror.Unwrap(err)
+	}
+
+	return res.Payload, nil
+}
+
+func elasticsearchLogs(params GenerateParams, id string) ([]byte, error) {
+	res, err := params.V1API.ClustersElasticsearch.GenerateEsClusterLogs(
+		clusters_elasticsearch.NewGenerateEsClusterLogsParams().
+			WithContext(api.WithRegion(context.Background(), params.Region)).
+			WithClusterID(id).
+			WithDate(params.LogsDate),
+		params.AuthWriter,
+	)
+	if err != nil {
+		return nil, apierror.Unwrap(err)
+	}
+
+	return res.Payload, nil
+}
+
+// write persists the manifest and all of the artifacts to either a directory
+// or a zip archive written to the writer.
+func write(params GenerateParams, manifest Manifest, artifacts [][]artifact) error {
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	var files = []artifact{{name: ManifestFile, data: manifestData}}
+	for _, a := range artifacts {
+		files = append(files, a...)
+	}
+
+	if params.Writer != nil {
+		return writeArchive(params.Writer, files)
+	}
+
+	return writeDirectory(params.Directory, files)
+}
+
+func writeArchive(w io.Writer, files []artifact) error {
+	var archive = zip.NewWriter(w)
+	for _, f := range files {
+		fw, err := archive.Create(f.name)
+		if err != nil {
+			return err
+		}
+
+		if _, err := fw.Write(f.data); err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
+
+func writeDirectory(dir string, files []artifact) error {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+
+	var merr = multierror.NewPrefixed("failed persisting support bundle")
+	for _, f := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, f.name), f.data, 0600); err != nil {
+			merr = merr.Append(err)
+		}
+	}
+
+	return merr.ErrorOrNil()
+}
@@ -0,0 +1,253 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package supportapi
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/api/mock"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+const getDeploymentResponse = `{
+  "id": "f1d329b0fb34470ba8b18361cabdd2bc",
+  "resources": {
+    "elasticsearch": [{
+      "id": "cde7b6b605424a54ce9d56316eab13a1",
+      "ref_id": "main-elasticsearch",
+      "region": "us-east-1"
+    }],
+    "kibana": [{
+      "id": "9b2c9e1a3d4f4e5a8b7c6d5e4f3a2b1c",
+      "ref_id": "main-kibana",
+      "elasticsearch_cluster_ref_id": "main-elasticsearch",
+      "region": "us-east-1"
+    }]
+  }
+}`
+
+func newZipResponse(body string) mock.Response {
+	return mock.Response{Response: http.Response{
+		StatusCode: 200,
+		Header:     http.Header{"Content-Type": {"application/zip"}},
+		Body:       mock.NewStringBody(body),
+	}}
+}
+
+func wantDeployment() *models.DeploymentGetResponse {
+	return &models.DeploymentGetResponse{
+		ID: ec.String("f1d329b0fb34470ba8b18361cabdd2bc"),
+		Resources: &models.DeploymentResources{
+			Elasticsearch: []*models.ElasticsearchResourceInfo{{
+				ID:     ec.String("cde7b6b605424a54ce9d56316eab13a1"),
+				RefID:  ec.String("main-elasticsearch"),
+				Region: ec.String("us-east-1"),
+			}},
+			Kibana: []*models.KibanaResourceInfo{{
+				ID:                        ec.String("9b2c9e1a3d4f4e5a8b7c6d5e4f3a2b1c"),
+				RefID:                     ec.String("main-kibana"),
+				ElasticsearchClusterRefID: ec.String("main-elasticsearch"),
+				Region:                    ec.String("us-east-1"),
+			}},
+		},
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "supportapi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var buf = new(bytes.Buffer)
+	type args struct {
+		params GenerateParams
+	}
+	tests := []struct {
+		name      string
+		args      args
+		want      *Manifest
+		wantFiles map[string]string
+		err       error
+	}{
+		{
+			name: "fails due to parameter validation",
+			err: multierror.NewPrefixed("deployment support bundle",
+				apierror.ErrMissingAPI,
+				errors.New(`id "" is invalid`),
+				errors.New("region not specified and is required for this operation"),
+				errors.New("one of directory or writer must be specified"),
+			),
+		},
+		{
+			name: "fails when both directory and writer are specified",
+			args: args{params: GenerateParams{
+				API:          api.NewMock(),
+				DeploymentID: "f1d329b0fb34470ba8b18361cabdd2bc",
+				Region:       "us-east-1",
+				Directory:    dir,
+				Writer:       new(bytes.Buffer),
+			}},
+			err: multierror.NewPrefixed("deployment support bundle",
+				errors.New("only one of directory or writer can be specified"),
+			),
+		},
+		{
+			name: "fails obtaining the deployment",
+			args: args{params: GenerateParams{
+				API: api.NewMock(
+					mock.New500Response(mock.NewStringBody(`{"error": "some error"}`)),
+				),
+				DeploymentID: "f1d329b0fb34470ba8b18361cabdd2bc",
+				Region:       "us-east-1",
+				Directory:    dir,
+			}},
+			err: errors.New(`{"error": "some error"}`),
+		},
+		{
+			name: "writes the bundle to a directory, reporting partial failures",
+			args: args{params: GenerateParams{
+				API: api.NewMock(
+					mock.New200Response(mock.NewStringBody(getDeploymentResponse)),
+					newZipResponse("diagnostics-contents"),
+					mock.New500Response(mock.NewStringBody(`{"error": "logs unavailable"}`)),
+				),
+				DeploymentID: "f1d329b0fb34470ba8b18361cabdd2bc",
+				Region:       "us-east-1",
+				Directory:    filepath.Join(dir, "bundle"),
+			}},
+			want: &Manifest{
+				Deployment: wantDeployment(),
+				Resources: []ResourceReport{{
+					Kind:   "elasticsearch",
+					RefID:  "main-elasticsearch",
+					ID:     "cde7b6b605424a54ce9d56316eab13a1",
+					Files:  []string{"elasticsearch-main-elasticsearch-diagnostics.zip"},
+					Errors: []string{`failed generating logs: {"error": "logs unavailable"}`},
+				}},
+			},
+			wantFiles: map[string]string{
+				filepath.Join(dir, "bundle", "elasticsearch-main-elasticsearch-diagnostics.zip"): "diagnostics-contents",
+			},
+			err: multierror.NewPrefixed("deployment support bundle",
+				errors.New(`elasticsearch main-elasticsearch: failed generating logs: {"error": "logs unavailable"}`),
+			),
+		},
+		{
+			name: "writes the bundle to a writer",
+			args: args{params: GenerateParams{
+				API: api.NewMock(
+					mock.New200Response(mock.NewStringBody(getDeploymentResponse)),
+					newZipResponse("diagnostics-contents"),
+					mock.Response{
+						Response: http.Response{
+							StatusCode: 200,
+							Header:     http.Header{"Content-Type": {"application/zip"}},
+							Body:       mock.NewStringBody("logs-contents"),
+						},
+						Assert: &mock.RequestAssertion{
+							Header: api.DefaultReadMockHeaders,
+							Method: "GET",
+							Host:   api.DefaultMockHost,
+							Path:   "/api/v1/regions/us-east-1/clusters/elasticsearch/cde7b6b605424a54ce9d56316eab13a1/support/_generate-logs",
+							Query:  map[string][]string{"date": {"2020-07-01"}},
+						},
+					},
+				),
+				DeploymentID: "f1d329b0fb34470ba8b18361cabdd2bc",
+				Region:       "us-east-1",
+				Writer:       buf,
+				LogsDate:     "2020-07-01",
+			}},
+			want: &Manifest{
+				Deployment: wantDeployment(),
+				Resources: []ResourceReport{{
+					Kind:  "elasticsearch",
+					RefID: "main-elasticsearch",
+					ID:    "cde7b6b605424a54ce9d56316eab13a1",
+					Files: []string{
+						"elasticsearch-main-elasticsearch-diagnostics.zip",
+						"elasticsearch-main-elasticsearch-logs.zip",
+					},
+				}},
+			},
+			wantFiles: map[string]string{
+				"elasticsearch-main-elasticsearch-diagnostics.zip": "diagnostics-contents",
+				"elasticsearch-main-elasticsearch-logs.zip":        "logs-contents",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Generate(tt.args.params)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.want, got)
+			if tt.want == nil {
+				return
+			}
+
+			var files = make(map[string][]byte)
+			if tt.args.params.Writer != nil {
+				r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, f := range r.File {
+					rc, err := f.Open()
+					if err != nil {
+						t.Fatal(err)
+					}
+					files[f.Name], _ = ioutil.ReadAll(rc)
+					rc.Close()
+				}
+			} else {
+				for name := range tt.wantFiles {
+					files[name], _ = ioutil.ReadFile(name)
+				}
+				files[ManifestFile], _ = ioutil.ReadFile(
+					filepath.Join(tt.args.params.Directory, ManifestFile),
+				)
+			}
+
+			for name, contents := range tt.wantFiles {
+				assert.Equal(t, contents, string(files[name]), name)
+			}
+
+			var manifest Manifest
+			if err := json.Unmarshal(files[ManifestFile], &manifest); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.want, &manifest)
+		})
+	}
+}
@@ -0,0 +1,321 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package upgradeapi
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/blang/semver"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/api/deploymentapi"
+	"github.com/elastic/cloud-sdk-go/pkg/api/deploymentapi/depresourceapi"
+	"github.com/elastic/cloud-sdk-go/pkg/api/deploymentapi/deputil"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/plan"
+	"github.com/elastic/cloud-sdk-go/pkg/plan/planutil"
+	"github.com/elastic/cloud-sdk-go/pkg/resourcekind"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+// Status of an upgrade phase.
+type Status string
+
+const (
+	// StatusUpgraded is set when the resource has been upgraded.
+	StatusUpgraded Status = "upgraded"
+
+	// StatusSkipped is set when the resource already runs the target version.
+	StatusSkipped Status = "skipped"
+
+	// StatusFailed is set when the resource upgrade or any of its hooks failed.
+	StatusFailed Status = "failed"
+
+	// StatusNotStarted is set on the phases which follow a failed one.
+	StatusNotStarted Status = "not started"
+)
+
+// Phase is the upgrade of a single deployment resource.
+type Phase struct {
+	Kind  string
+	RefID string
+
+	// Version of the resource before the upgrade.
+	Version string
+}
+
+// Hook is called before or after each upgrade phase, when it returns an error
+// the upgrade is stopped. Hooks can be used to take a snapshot or to check
+// the health of the deployment.
+type Hook func(Phase) error
+
+// PhaseResult is the outcome of an upgrade phase.
+type PhaseResult struct {
+	Phase
+	Status Status
+	Err    error
+}
+
+// Report contains the outcome of each of the upgrade phases, in the order
+// they're run.
+type Report struct {
+	DeploymentID string
+	FromVersion  string
+	ToVersion    string
+	Phases       []PhaseResult
+}
+
+// Upgraded returns the phases which have upgraded their resource.
+func (r Report) Upgraded() []PhaseResult {
+	var res []PhaseResult
+	for _, p := range r.Phases {
+		if p.Status == StatusUpgraded {
+			res = append(res, p)
+		}
+	}
+	return res
+}
+
+// Params is consumed by Upgrade.
+type Params struct {
+	*api.API
+
+	DeploymentID string
+
+	// Region is used to obtain the available stack versions.
+	Region string
+
+	// Version to upgrade the deployment to.
+	Version string
+
+	// PreHooks are called before each phase upgrade.
+	PreHooks []Hook
+
+	// PostHooks are called after each phase has been upgraded.
+	PostHooks []Hook
+
+	// TrackFrequency controls how often the plan changes are polled.
+	TrackFrequency plan.TrackFrequencyConfig
+}
+
+// Validate ensures the parameters are usable.
+func (params Params) Validate() error {
+	var merr = multierror.NewPrefixed("deployment upgrade")
+	if params.API == nil {
+		merr = merr.Append(apierror.ErrMissingAPI)
+	}
+
+	if len(params.DeploymentID) != 32 {
+		merr = merr.Append(deputil.NewInvalidDeploymentIDError(params.DeploymentID))
+	}
+
+	if _, err := semver.Parse(params.Version); err != nil {
+		merr = merr.Append(errors.New(strings.ToLower(err.Error())))
+	}
+
+	if err := ec.RequireRegionSet(params.Region); err != nil {
+		merr = merr.Append(err)
+	}
+
+	return merr.ErrorOrNil()
+}
+
+// Upgrade upgrades a deployment to the target stack version. The version must
+// be available in the region and must be upgradable from the current version
+// of each of the deployment's Elasticsearch resources. Elasticsearch is
+// upgraded first, followed by the stateless resources in the resourcekind
+// registry order (Kibana, APM, App Search and Enterprise Search), waiting for
+// each of the plan changes to finish. Resources which already run the target
+// version are skipped. When a phase or its hooks fail, the remaining phases
+// aren't run and the returned report contains what was upgraded along with
+// the error.
+func Upgrade(params Params) (*Report, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	res, err := deploymentapi.Get(deploymentapi.GetParams{
+		API: params.API, DeploymentID: params.DeploymentID,
+		QueryParams: deputil.QueryParams{ShowPlans: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	phases, err := newPhases(res)
+	if err != nil {
+		return nil, err
+	}
+
+	var report = Report{
+		DeploymentID: params.DeploymentID,
+		FromVersion:  phases[0].Version,
+		ToVersion:    params.Version,
+	}
+	var validated = make(map[string]bool)
+	for _, phase := range phases {
+		if phase.Kind != resourcekind.Elasticsearch || validated[phase.Version] {
+			continue
+		}
+		validated[phase.Version] = true
+
+		if err := ValidateVersion(ValidateVersionParams{
+			API: params.API, Region: params.Region,
+			From: phase.Version, To: params.Version,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	var failed error
+	for _, phase := range phases {
+		var result = PhaseResult{Phase: phase, Status: StatusUpgraded}
+		switch {
+		case failed != nil:
+			result.Status = StatusNotStarted
+		case phase.Version == params.Version:
+			result.Status = StatusSkipped
+		default:
+			if result.Err = runPhase(params, phase, res); result.Err != nil {
+				result.Status, failed = StatusFailed, result.Err
+			}
+		}
+		report.Phases = append(report.Phases, result)
+	}
+
+	if failed != nil {
+		return &report, multierror.NewPrefixed("deployment upgrade", failed)
+	}
+
+	return &report, nil
+}
+
+func runPhase(params Params, phase Phase, res *models.DeploymentGetResponse) error {
+	for _, hook := range params.PreHooks {
+		if err := hook(phase); err != nil {
+			return fmt.Errorf("%s %s: pre-upgrade hook: %w", phase.Kind, phase.RefID, err)
+		}
+	}
+
+	if err := upgradeResource(params, phase, res); err != nil {
+		return fmt.Errorf("%s %s: %w", phase.Kind, phase.RefID, err)
+	}
+
+	for _, hook := range params.PostHooks {
+		if err := hook(phase); err != nil {
+			return fmt.Errorf("%s %s: post-upgrade hook: %w", phase.Kind, phase.RefID, err)
+		}
+	}
+
+	return nil
+}
+
+// upgradeResource upgrades the Elasticsearch resources by updating the
+// version of their current plan, while the stateless resources are upgraded
+// to the Elasticsearch version.
+func upgradeResource(params Params, phase Phase, res *models.DeploymentGetResponse) error {
+	if phase.Kind != resourcekind.Elasticsearch {
+		if _, err := depresourceapi.UpgradeStateless(depresourceapi.Params{
+			API: params.API, DeploymentID: params.DeploymentID,
+			Kind: phase.Kind, RefID: phase.RefID,
+		}); err != nil {
+			return err
+		}
+		return wait(params)
+	}
+
+	req, err := deploymentapi.NewUpdateRequest(res, deploymentapi.ConvertOptions{
+		DropTransient: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	var payload *models.ElasticsearchPayload
+	for _, p := range req.Resources.Elasticsearch {
+		if p.RefID != nil && *p.RefID == phase.RefID {
+			payload = p
+		}
+	}
+	if payload == nil || payload.Plan == nil || payload.Plan.Elasticsearch == nil {
+		return errors.New("elasticsearch resource plan not found")
+	}
+
+	payload.Plan.Elasticsearch.Version = params.Version
+	for _, t := range payload.Plan.ClusterTopology {
+		if t.Elasticsearch != nil && t.Elasticsearch.Version != "" {
+			t.Elasticsearch.Version = params.Version
+		}
+	}
+	req.Resources = &models.DeploymentUpdateResources{
+		Elasticsearch: []*models.ElasticsearchPayload{payload},
+	}
+
+	if _, err := deploymentapi.Update(deploymentapi.UpdateParams{
+		API: params.API, DeploymentID: params.DeploymentID, Request: req,
+	}); err != nil {
+		return err
+	}
+
+	return wait(params)
+}
+
+func wait(params Params) error {
+	return planutil.Wait(plan.TrackChangeParams{
+		API: params.API, DeploymentID: params.DeploymentID,
+		Config: params.TrackFrequency,
+	})
+}
+
+// newPhases returns the upgrade phases of the deployment resources, starting
+// with the Elasticsearch resources.
+func newPhases(res *models.DeploymentGetResponse) ([]Phase, error) {
+	if res.Resources == nil || len(res.Resources.Elasticsearch) == 0 {
+		return nil, errors.New("deployment upgrade: deployment has no elasticsearch resources")
+	}
+
+	var elasticsearch, _ = resourcekind.Get(resourcekind.Elasticsearch)
+	var phases = kindPhases(res.Resources, elasticsearch)
+	for _, phase := range phases {
+		if phase.Version == "" {
+			return nil, errors.New("deployment upgrade: unable to obtain the current elasticsearch version")
+		}
+	}
+
+	// The stateless resources are upgraded in the resourcekind registry order.
+	for _, kind := range resourcekind.Supporting(resourcekind.Upgrade) {
+		phases = append(phases, kindPhases(res.Resources, kind)...)
+	}
+
+	return phases, nil
+}
+
+// kindPhases returns the upgrade phases of all the resources of a kind.
+func kindPhases(resources *models.DeploymentResources, kind resourcekind.Kind) []Phase {
+	var phases []Phase
+	for _, info := range kind.Infos(resources) {
+		phases = append(phases, Phase{
+			Kind: kind.Name, RefID: kind.RefID(info), Version: kind.CurrentVersion(info),
+		})
+	}
+	return phases
+}
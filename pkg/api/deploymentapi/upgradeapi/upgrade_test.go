@@ -0,0 +1,341 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package upgradeapi
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/api/deploymentapi/deputil"
+	"github.com/elastic/cloud-sdk-go/pkg/api/mock"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/plan"
+	"github.com/elastic/cloud-sdk-go/pkg/resourcekind"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+const deploymentID = "f1d329b0fb34470ba8b18361cabdd2bc"
+
+func newDeployment() *models.DeploymentGetResponse {
+	return &models.DeploymentGetResponse{
+		ID:   ec.String(deploymentID),
+		Name: ec.String("my-deployment"),
+		Resources: &models.DeploymentResources{
+			Elasticsearch: []*models.ElasticsearchResourceInfo{{
+				ID:    ec.String("3ee11eb40eda22cac0cce259625c6734"),
+				RefID: ec.String("main-elasticsearch"),
+				Info: &models.ElasticsearchClusterInfo{PlanInfo: &models.ElasticsearchClusterPlansInfo{
+					Current: &models.ElasticsearchClusterPlanInfo{Plan: &models.ElasticsearchClusterPlan{
+						Elasticsearch: &models.ElasticsearchConfiguration{Version: "7.10.0"},
+						ClusterTopology: []*models.ElasticsearchClusterTopologyElement{{
+							ZoneCount: 1,
+							Size:      &models.TopologySize{Resource: ec.String("memory"), Value: ec.Int32(1024)},
+						}},
+					}},
+				}},
+			}},
+			Kibana: []*models.KibanaResourceInfo{{
+				ID:    ec.String("4ee11eb40eda22cac0cce259625c6734"),
+				RefID: ec.String("main-kibana"),
+				Info: &models.KibanaClusterInfo{PlanInfo: &models.KibanaClusterPlansInfo{
+					Current: &models.KibanaClusterPlanInfo{Plan: &models.KibanaClusterPlan{
+						Kibana: &models.KibanaConfiguration{Version: "7.10.0"},
+					}},
+				}},
+			}},
+			Apm: []*models.ApmResourceInfo{{
+				ID:    ec.String("5ee11eb40eda22cac0cce259625c6734"),
+				RefID: ec.String("main-apm"),
+				Info: &models.ApmInfo{PlanInfo: &models.ApmPlansInfo{
+					Current: &models.ApmPlanInfo{Plan: &models.ApmPlan{
+						Apm: &models.ApmConfiguration{Version: "7.11.0"},
+					}},
+				}},
+			}},
+		},
+	}
+}
+
+func trackResponses() []mock.Response {
+	var res = func() mock.Response {
+		return mock.New200Response(mock.NewStructBody(models.DeploymentGetResponse{
+			ID: ec.String(deploymentID), Resources: &models.DeploymentResources{},
+		}))
+	}
+	return []mock.Response{res(), res()}
+}
+
+func TestUpgrade(t *testing.T) {
+	var deploymentResponse = func() mock.Response {
+		return mock.New200Response(mock.NewStructBody(newDeployment()))
+	}
+	var updateResponse = mock.New200ResponseAssertion(&mock.RequestAssertion{
+		Header: api.DefaultWriteMockHeaders,
+		Method: "PUT",
+		Host:   api.DefaultMockHost,
+		Path:   "/api/v1/deployments/" + deploymentID,
+		Query: url.Values{
+			"hide_pruned_orphans": {"false"},
+			"skip_snapshot":       {"false"},
+			"validate_only":       {"false"},
+		},
+		Body: mock.NewStructBody(models.DeploymentUpdateRequest{
+			Name:         "my-deployment",
+			PruneOrphans: ec.Bool(false),
+			Resources: &models.DeploymentUpdateResources{
+				Elasticsearch: []*models.ElasticsearchPayload{{
+					RefID: ec.String("main-elasticsearch"),
+					Plan: &models.ElasticsearchClusterPlan{
+						Elasticsearch: &models.ElasticsearchConfiguration{Version: "7.11.0"},
+						ClusterTopology: []*models.ElasticsearchClusterTopologyElement{{
+							ZoneCount: 1,
+							Size:      &models.TopologySize{Resource: ec.String("memory"), Value: ec.Int32(1024)},
+						}},
+					},
+				}},
+			},
+		}),
+	}, mock.NewStructBody(models.DeploymentUpdateResponse{ID: ec.String(deploymentID)}))
+	var kibanaUpgradeResponse = mock.New202ResponseAssertion(&mock.RequestAssertion{
+		Header: api.DefaultWriteMockHeaders,
+		Method: "POST",
+		Host:   api.DefaultMockHost,
+		Path:   "/api/v1/deployments/" + deploymentID + "/kibana/main-kibana/_upgrade",
+		Query:  url.Values{"validate_only": {"false"}},
+	}, mock.NewStructBody(models.DeploymentResourceUpgradeResponse{}))
+	var trackFrequency = plan.TrackFrequencyConfig{PollFrequency: time.Millisecond, MaxRetries: 1}
+
+	var phases []string
+	var hook = func(name string, err error) Hook {
+		return func(p Phase) error {
+			phases = append(phases, fmt.Sprintf("%s %s %s", name, p.Kind, p.RefID))
+			return err
+		}
+	}
+
+	var esPhase = Phase{Kind: resourcekind.Elasticsearch, RefID: "main-elasticsearch", Version: "7.10.0"}
+	var kibanaPhase = Phase{Kind: resourcekind.Kibana, RefID: "main-kibana", Version: "7.10.0"}
+	var apmPhase = Phase{Kind: resourcekind.Apm, RefID: "main-apm", Version: "7.11.0"}
+
+	tests := []struct {
+		name       string
+		params     Params
+		want       *Report
+		wantPhases []string
+		err        string
+	}{
+		{
+			name:   "fails due to parameter validation",
+			params: Params{Version: "7.11"},
+			err: multierror.NewPrefixed("deployment upgrade",
+				apierror.ErrMissingAPI,
+				deputil.NewInvalidDeploymentIDError(""),
+				errors.New("no major.minor.patch elements found"),
+				errors.New("region not specified and is required for this operation"),
+			).Error(),
+		},
+		{
+			name: "fails when the version is invalid",
+			params: Params{
+				API:          api.NewMock(deploymentResponse(), stacksResponse()),
+				DeploymentID: deploymentID,
+				Region:       "us-east-1",
+				Version:      "7.12.0",
+			},
+			err: "deployment upgrade: version 7.10.0 can't be upgraded to 7.12.0, valid versions are 7.11.0",
+		},
+		{
+			name: "fails when the version is invalid for any of the elasticsearch resources",
+			params: Params{
+				API: api.NewMock(mock.New200Response(mock.NewStructBody(func() *models.DeploymentGetResponse {
+					var res = newDeployment()
+					res.Resources.Elasticsearch = append(res.Resources.Elasticsearch, &models.ElasticsearchResourceInfo{
+						ID:    ec.String("6ee11eb40eda22cac0cce259625c6734"),
+						RefID: ec.String("other-elasticsearch"),
+						Info: &models.ElasticsearchClusterInfo{PlanInfo: &models.ElasticsearchClusterPlansInfo{
+							Current: &models.ElasticsearchClusterPlanInfo{Plan: &models.ElasticsearchClusterPlan{
+								Elasticsearch: &models.ElasticsearchConfiguration{Version: "6.5.0"},
+							}},
+						}},
+					})
+					return res
+				}())), stacksResponse(), stacksResponse()),
+				DeploymentID: deploymentID,
+				Region:       "us-east-1",
+				Version:      "7.11.0",
+			},
+			err: "deployment upgrade: version 7.11.0 can only be upgraded to from 6.8.0 or higher",
+		},
+		{
+			name: "upgrades elasticsearch and then the stateless resources",
+			params: Params{
+				API: api.NewMock(append(append(append(
+					[]mock.Response{deploymentResponse(), stacksResponse(), updateResponse},
+					trackResponses()...), kibanaUpgradeResponse), trackResponses()...,
+				)...),
+				DeploymentID:   deploymentID,
+				Region:         "us-east-1",
+				Version:        "7.11.0",
+				PreHooks:       []Hook{hook("pre", nil)},
+				PostHooks:      []Hook{hook("post", nil)},
+				TrackFrequency: trackFrequency,
+			},
+			want: &Report{
+				DeploymentID: deploymentID, FromVersion: "7.10.0", ToVersion: "7.11.0",
+				Phases: []PhaseResult{
+					{Phase: esPhase, Status: StatusUpgraded},
+					{Phase: kibanaPhase, Status: StatusUpgraded},
+					{Phase: apmPhase, Status: StatusSkipped},
+				},
+			},
+			wantPhases: []string{
+				"pre elasticsearch main-elasticsearch",
+				"post elasticsearch main-elasticsearch",
+				"pre kibana main-kibana",
+				"post kibana main-kibana",
+			},
+		},
+		{
+			name: "stops when a stateless resource upgrade fails",
+			params: Params{
+				API: api.NewMock(append(append(
+					[]mock.Response{deploymentResponse(), stacksResponse(), mock.New200Response(
+						mock.NewStructBody(models.DeploymentUpdateResponse{ID: ec.String(deploymentID)}),
+					)}, trackResponses()...),
+					mock.New500Response(mock.NewStringBody(`{"error": "some error"}`)),
+				)...),
+				DeploymentID:   deploymentID,
+				Region:         "us-east-1",
+				Version:        "7.11.0",
+				TrackFrequency: trackFrequency,
+			},
+			want: &Report{
+				DeploymentID: deploymentID, FromVersion: "7.10.0", ToVersion: "7.11.0",
+				Phases: []PhaseResult{
+					{Phase: esPhase, Status: StatusUpgraded},
+					{Phase: kibanaPhase, Status: StatusFailed, Err: fmt.Errorf(
+						"kibana main-kibana: %w", errors.New(`{"error": "some error"}`),
+					)},
+					{Phase: apmPhase, Status: StatusNotStarted},
+				},
+			},
+			err: `deployment upgrade: 1 error occurred:
+	* kibana main-kibana: {"error": "some error"}
+
+`,
+		},
+		{
+			name: "stops when a pre-upgrade hook fails",
+			params: Params{
+				API:          api.NewMock(deploymentResponse(), stacksResponse()),
+				DeploymentID: deploymentID,
+				Region:       "us-east-1",
+				Version:      "7.11.0",
+				PreHooks:     []Hook{hook("pre", errors.New("unhealthy"))},
+			},
+			want: &Report{
+				DeploymentID: deploymentID, FromVersion: "7.10.0", ToVersion: "7.11.0",
+				Phases: []PhaseResult{
+					{Phase: esPhase, Status: StatusFailed, Err: fmt.Errorf(
+						"elasticsearch main-elasticsearch: pre-upgrade hook: %w", errors.New("unhealthy"),
+					)},
+					{Phase: kibanaPhase, Status: StatusNotStarted},
+					{Phase: apmPhase, Status: StatusNotStarted},
+				},
+			},
+			wantPhases: []string{"pre elasticsearch main-elasticsearch"},
+			err: `deployment upgrade: 1 error occurred:
+	* elasticsearch main-elasticsearch: pre-upgrade hook: unhealthy
+
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			phases = nil
+			got, err := Upgrade(tt.params)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantPhases, phases)
+			if got != nil {
+				var upgraded []PhaseResult
+				for _, p := range tt.want.Phases {
+					if p.Status == StatusUpgraded {
+						upgraded = append(upgraded, p)
+					}
+				}
+				assert.Equal(t, upgraded, got.Upgraded())
+			}
+		})
+	}
+}
+
+func Test_newPhases(t *testing.T) {
+	var res = &models.DeploymentGetResponse{
+		Resources: &models.DeploymentResources{
+			Elasticsearch: []*models.ElasticsearchResourceInfo{{
+				RefID: ec.String("main-elasticsearch"),
+				Info: &models.ElasticsearchClusterInfo{PlanInfo: &models.ElasticsearchClusterPlansInfo{
+					Current: &models.ElasticsearchClusterPlanInfo{Plan: &models.ElasticsearchClusterPlan{
+						Elasticsearch: &models.ElasticsearchConfiguration{Version: "7.9.0"},
+					}},
+				}},
+			}},
+			EnterpriseSearch: []*models.EnterpriseSearchResourceInfo{{
+				RefID: ec.String("main-enterprise_search"),
+				Info: &models.EnterpriseSearchInfo{PlanInfo: &models.EnterpriseSearchPlansInfo{
+					Current: &models.EnterpriseSearchPlanInfo{Plan: &models.EnterpriseSearchPlan{
+						EnterpriseSearch: &models.EnterpriseSearchConfiguration{Version: "7.9.0"},
+					}},
+				}},
+			}},
+			Appsearch: []*models.AppSearchResourceInfo{{
+				RefID: ec.String("main-appsearch"),
+			}},
+			Kibana: []*models.KibanaResourceInfo{{
+				RefID: ec.String("main-kibana"),
+				Info: &models.KibanaClusterInfo{PlanInfo: &models.KibanaClusterPlansInfo{
+					Current: &models.KibanaClusterPlanInfo{Plan: &models.KibanaClusterPlan{
+						Kibana: &models.KibanaConfiguration{Version: "7.9.0"},
+					}},
+				}},
+			}},
+		},
+	}
+
+	got, err := newPhases(res)
+	assert.NoError(t, err)
+	assert.Equal(t, []Phase{
+		{Kind: "elasticsearch", RefID: "main-elasticsearch", Version: "7.9.0"},
+		{Kind: "kibana", RefID: "main-kibana", Version: "7.9.0"},
+		{Kind: "appsearch", RefID: "main-appsearch"},
+		{Kind: "enterprise_search", RefID: "main-enterprise_search", Version: "7.9.0"},
+	}, got)
+}
@@ -0,0 +1,121 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package upgradeapi
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/blang/semver"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/api/stackapi"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+	"github.com/elastic/cloud-sdk-go/pkg/util/slice"
+)
+
+// ValidateVersionParams is consumed by ValidateVersion.
+type ValidateVersionParams struct {
+	*api.API
+	Region string
+
+	// From is the current version.
+	From string
+
+	// To is the target version.
+	To string
+}
+
+// Validate ensures the parameters are usable.
+func (params ValidateVersionParams) Validate() error {
+	var merr = multierror.NewPrefixed("invalid upgrade version params")
+	if params.API == nil {
+		merr = merr.Append(apierror.ErrMissingAPI)
+	}
+
+	for _, v := range []string{params.From, params.To} {
+		if _, err := semver.Parse(v); err != nil {
+			merr = merr.Append(errors.New(strings.ToLower(err.Error())))
+		}
+	}
+
+	if err := ec.RequireRegionSet(params.Region); err != nil {
+		merr = merr.Append(err)
+	}
+
+	return merr.ErrorOrNil()
+}
+
+// ValidateVersion ensures that the target version is an available stack
+// version in the region and that it can be upgraded to from the current
+// version, following the upgrade paths of both stack versions.
+func ValidateVersion(params ValidateVersionParams) error {
+	if err := params.Validate(); err != nil {
+		return err
+	}
+
+	res, err := stackapi.List(stackapi.ListParams{API: params.API, Region: params.Region})
+	if err != nil {
+		return err
+	}
+
+	from, to := semver.MustParse(params.From), semver.MustParse(params.To)
+	if to.LT(from) {
+		return fmt.Errorf("deployment upgrade: cannot downgrade from %s to %s", params.From, params.To)
+	}
+
+	var current, target *models.StackVersionConfig
+	for _, s := range res.Stacks {
+		if s.Version == params.From {
+			current = s
+		}
+		if s.Version == params.To {
+			target = s
+		}
+	}
+
+	if target == nil || (target.Deleted != nil && *target.Deleted) {
+		return fmt.Errorf("deployment upgrade: version %s is not available", params.To)
+	}
+
+	if to.EQ(from) {
+		return nil
+	}
+
+	if target.MinUpgradableFrom != "" {
+		if min, err := semver.Parse(target.MinUpgradableFrom); err == nil && from.LT(min) {
+			return fmt.Errorf(
+				"deployment upgrade: version %s can only be upgraded to from %s or higher",
+				params.To, target.MinUpgradableFrom,
+			)
+		}
+	}
+
+	if current != nil && len(current.UpgradableTo) > 0 && !slice.HasString(current.UpgradableTo, params.To) {
+		return fmt.Errorf(
+			"deployment upgrade: version %s can't be upgraded to %s, valid versions are %s",
+			params.From, params.To, strings.Join(current.UpgradableTo, ", "),
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,119 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package upgradeapi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/api/mock"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+)
+
+func stacksResponse() mock.Response {
+	return mock.New200Response(mock.NewStructBody(models.StackVersionConfigs{
+		Stacks: []*models.StackVersionConfig{
+			{Version: "7.10.0", UpgradableTo: []string{"7.11.0"}},
+			{Version: "7.11.0", MinUpgradableFrom: "6.8.0"},
+			{Version: "7.12.0", MinUpgradableFrom: "6.8.0"},
+			{Version: "8.0.0", MinUpgradableFrom: "7.17.0"},
+		},
+	}))
+}
+
+func TestValidateVersion(t *testing.T) {
+	tests := []struct {
+		name   string
+		params ValidateVersionParams
+		err    error
+	}{
+		{
+			name:   "fails due to parameter validation",
+			params: ValidateVersionParams{From: "7.10.0", To: "invalid"},
+			err: multierror.NewPrefixed("invalid upgrade version params",
+				apierror.ErrMissingAPI,
+				errors.New("no major.minor.patch elements found"),
+				errors.New("region not specified and is required for this operation"),
+			),
+		},
+		{
+			name: "fails listing the stack versions",
+			params: ValidateVersionParams{
+				API:    api.NewMock(mock.New500Response(mock.NewStringBody(`{"error": "some error"}`))),
+				Region: "us-east-1", From: "7.10.0", To: "7.11.0",
+			},
+			err: errors.New(`{"error": "some error"}`),
+		},
+		{
+			name: "fails on downgrades",
+			params: ValidateVersionParams{
+				API:    api.NewMock(stacksResponse()),
+				Region: "us-east-1", From: "7.11.0", To: "7.10.0",
+			},
+			err: errors.New("deployment upgrade: cannot downgrade from 7.11.0 to 7.10.0"),
+		},
+		{
+			name: "fails when the target version isn't available",
+			params: ValidateVersionParams{
+				API:    api.NewMock(stacksResponse()),
+				Region: "us-east-1", From: "7.10.0", To: "7.13.0",
+			},
+			err: errors.New("deployment upgrade: version 7.13.0 is not available"),
+		},
+		{
+			name: "fails when the current version is lower than the minimum upgradable from",
+			params: ValidateVersionParams{
+				API:    api.NewMock(stacksResponse()),
+				Region: "us-east-1", From: "7.10.0", To: "8.0.0",
+			},
+			err: errors.New("deployment upgrade: version 8.0.0 can only be upgraded to from 7.17.0 or higher"),
+		},
+		{
+			name: "fails when the target version isn't an upgrade path of the current one",
+			params: ValidateVersionParams{
+				API:    api.NewMock(stacksResponse()),
+				Region: "us-east-1", From: "7.10.0", To: "7.12.0",
+			},
+			err: errors.New("deployment upgrade: version 7.10.0 can't be upgraded to 7.12.0, valid versions are 7.11.0"),
+		},
+		{
+			name: "succeeds on a valid upgrade",
+			params: ValidateVersionParams{
+				API:    api.NewMock(stacksResponse()),
+				Region: "us-east-1", From: "7.10.0", To: "7.11.0",
+			},
+		},
+		{
+			name: "succeeds when the version doesn't change",
+			params: ValidateVersionParams{
+				API:    api.NewMock(stacksResponse()),
+				Region: "us-east-1", From: "7.11.0", To: "7.11.0",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.err, ValidateVersion(tt.params))
+		})
+	}
+}
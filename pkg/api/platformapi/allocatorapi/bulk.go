@@ -0,0 +1,281 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package allocatorapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/api/platformapi/loggingapi"
+	"github.com/elastic/cloud-sdk-go/pkg/client/platform_infrastructure"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+// BulkParams selects the allocators which a bulk operation is applied to. It
+// is embedded in all of the bulk parameter structures.
+type BulkParams struct {
+	*api.API
+	Region string
+
+	// Optional allocator search query, i.e. UnhealthyQuery.
+	Query string
+
+	// Optional tags which the allocators must have. Expected format is a
+	// key:value slice. i.e. [key:val, key:value]
+	FilterTags string
+}
+
+func (params BulkParams) errors() []error {
+	var errs []error
+	if params.API == nil {
+		errs = append(errs, apierror.ErrMissingAPI)
+	}
+	if err := ec.RequireRegionSet(params.Region); err != nil {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// BulkReport contains the outcome of a bulk operation for each of the matched
+// allocators, sorted by allocator ID. It is returned alongside the error when
+// the operation fails on any of the allocators.
+type BulkReport struct {
+	Results []BulkResult `json:"results"`
+}
+
+// BulkResult is the outcome of a bulk operation on a single allocator.
+type BulkResult struct {
+	AllocatorID string `json:"allocator_id"`
+	Error       error  `json:"-"`
+}
+
+// Succeeded returns the IDs of the allocators where the operation succeeded.
+func (r *BulkReport) Succeeded() []string {
+	var ids = make([]string, 0, len(r.Results))
+	for _, res := range r.Results {
+		if res.Error == nil {
+			ids = append(ids, res.AllocatorID)
+		}
+	}
+	return ids
+}
+
+// Failed returns the IDs of the allocators where the operation failed.
+func (r *BulkReport) Failed() []string {
+	var ids = make([]string, 0, len(r.Results))
+	for _, res := range r.Results {
+		if res.Error != nil {
+			ids = append(ids, res.AllocatorID)
+		}
+	}
+	return ids
+}
+
+// bulkApply runs the function on each of the allocators which match the bulk
+// params, returning the report and a multierror containing the failures.
+func bulkApply(params BulkParams, prefix string, fn func(*models.AllocatorInfo) error) (*BulkReport, error) {
+	res, err := List(ListParams{
+		API:        params.API,
+		Region:     params.Region,
+		Query:      params.Query,
+		FilterTags: params.FilterTags,
+		ShowAll:    true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var allocators []*models.AllocatorInfo
+	for _, z := range res.Zones {
+		for _, a := range z.Allocators {
+			if a != nil && a.AllocatorID != nil {
+				allocators = append(allocators, a)
+			}
+		}
+	}
+	sort.Slice(allocators, func(i, j int) bool {
+		return *allocators[i].AllocatorID < *allocators[j].AllocatorID
+	})
+
+	var report = BulkReport{Results: make([]BulkResult, 0, len(allocators))}
+	var merr = multierror.NewPrefixed(prefix)
+	for _, a := range allocators {
+		var result = BulkResult{AllocatorID: *a.AllocatorID, Error: fn(a)}
+		if result.Error != nil {
+			merr = merr.Append(fmt.Errorf("allocator %s: %w", result.AllocatorID, result.Error))
+		}
+		report.Results = append(report.Results, result)
+	}
+
+	return &report, merr.ErrorOrNil()
+}
+
+// BulkSetMetadataParams is consumed by BulkSetMetadata.
+type BulkSetMetadataParams struct {
+	BulkParams
+
+	// Metadata to set on the allocators.
+	Metadata map[string]string
+
+	// When true, the allocators metadata is replaced by Metadata, otherwise
+	// Metadata is merged into the existing allocator metadata.
+	Replace bool
+}
+
+// Validate ensures that the parameters are correct
+func (params BulkSetMetadataParams) Validate() error {
+	var merr = multierror.NewPrefixed("invalid allocator bulk metadata params",
+		params.errors()...,
+	)
+	if len(params.Metadata) == 0 && !params.Replace {
+		merr = merr.Append(errors.New("metadata cannot be empty"))
+	}
+	if _, ok := params.Metadata[""]; ok {
+		merr = merr.Append(errors.New("metadata keys cannot be empty"))
+	}
+	return merr.ErrorOrNil()
+}
+
+// BulkSetMetadata sets the metadata of all the allocators which match the
+// query and tag filters.
+func BulkSetMetadata(params BulkSetMetadataParams) (*BulkReport, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	return bulkApply(params.BulkParams, "allocator bulk metadata", func(a *models.AllocatorInfo) error {
+		var metadata = make(map[string]string)
+		if !params.Replace {
+			for _, m := range a.Metadata {
+				if m == nil || m.Key == nil || m.Value == nil {
+					continue
+				}
+				metadata[*m.Key] = *m.Value
+			}
+		}
+		for k, v := range params.Metadata {
+			metadata[k] = v
+		}
+
+		var keys = make([]string, 0, len(metadata))
+		for k := range metadata {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		var items = make([]*models.MetadataItem, 0, len(keys))
+		for _, k := range keys {
+			items = append(items, &models.MetadataItem{
+				Key: ec.String(k), Value: ec.String(metadata[k]),
+			})
+		}
+
+		return api.ReturnErrOnly(
+			params.V1API.PlatformInfrastructure.SetAllocatorMetadata(
+				platform_infrastructure.NewSetAllocatorMetadataParams().
+					WithContext(api.WithRegion(context.Background(), params.Region)).
+					WithAllocatorID(*a.AllocatorID).
+					WithBody(&models.MetadataItems{Items: items}),
+				params.AuthWriter,
+			),
+		)
+	})
+}
+
+// BulkUpdateSettingsParams is consumed by BulkUpdateSettings.
+type BulkUpdateSettingsParams struct {
+	BulkParams
+
+	// Settings to update, only the non empty fields are updated.
+	Settings *models.AllocatorSettings
+}
+
+// Validate ensures that the parameters are correct
+func (params BulkUpdateSettingsParams) Validate() error {
+	var merr = multierror.NewPrefixed("invalid allocator bulk settings params",
+		params.errors()...,
+	)
+	if params.Settings == nil {
+		merr = merr.Append(errSettingsCannotBeNil)
+	}
+	return merr.ErrorOrNil()
+}
+
+// BulkUpdateSettings updates the settings of all the allocators which match
+// the query and tag filters, i.e. to override their capacity.
+func BulkUpdateSettings(params BulkUpdateSettingsParams) (*BulkReport, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	return bulkApply(params.BulkParams, "allocator bulk settings", func(a *models.AllocatorInfo) error {
+		_, err := UpdateSettings(SettingsSetParams{
+			API:      params.API,
+			ID:       *a.AllocatorID,
+			Region:   params.Region,
+			Settings: params.Settings,
+		})
+		return err
+	})
+}
+
+// BulkUpdateLoggingSettingsParams is consumed by BulkUpdateLoggingSettings.
+type BulkUpdateLoggingSettingsParams struct {
+	BulkParams
+
+	// Logging levels by logger name, i.e. {"root": "DEBUG"}.
+	Levels map[string]string
+}
+
+// Validate ensures that the parameters are correct
+func (params BulkUpdateLoggingSettingsParams) Validate() error {
+	var merr = multierror.NewPrefixed("invalid allocator bulk logging settings params",
+		params.errors()...,
+	)
+	if len(params.Levels) == 0 {
+		merr = merr.Append(errors.New("at least one logging level must be specified"))
+	}
+	return merr.ErrorOrNil()
+}
+
+// BulkUpdateLoggingSettings updates the logging levels of all the allocators
+// which match the query and tag filters.
+func BulkUpdateLoggingSettings(params BulkUpdateLoggingSettingsParams) (*BulkReport, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	return bulkApply(params.BulkParams, "allocator bulk logging settings", func(a *models.AllocatorInfo) error {
+		_, err := loggingapi.Update(loggingapi.UpdateParams{
+			Params: loggingapi.Params{
+				API:       params.API,
+				Region:    params.Region,
+				Component: loggingapi.Allocator,
+				ID:        *a.AllocatorID,
+			},
+			Levels: params.Levels,
+		})
+		return err
+	})
+}
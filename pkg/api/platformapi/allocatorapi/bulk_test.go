@@ -0,0 +1,199 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package allocatorapi
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/api/mock"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+func newBulkAllocators() mock.Response {
+	var newAllocator = func(id string, metadata ...*models.MetadataItem) *models.AllocatorInfo {
+		return &models.AllocatorInfo{
+			AllocatorID: ec.String(id),
+			Status:      &models.AllocatorHealthStatus{Connected: ec.Bool(false)},
+			Metadata:    metadata,
+		}
+	}
+	return mock.New200ResponseAssertion(&mock.RequestAssertion{
+		Header: api.DefaultReadMockHeaders,
+		Method: "GET",
+		Host:   api.DefaultMockHost,
+		Path:   "/api/v1/regions/us-east-1/platform/infrastructure/allocators",
+		Query:  url.Values{"q": {UnhealthyQuery}},
+	}, mock.NewStructBody(models.AllocatorOverview{Zones: []*models.AllocatorZoneInfo{
+		{ZoneID: ec.String("zone-1"), Allocators: []*models.AllocatorInfo{
+			newAllocator("allocator-2",
+				&models.MetadataItem{Key: ec.String("hardware"), Value: ec.String("io")},
+			),
+		}},
+		{ZoneID: ec.String("zone-2"), Allocators: []*models.AllocatorInfo{
+			newAllocator("allocator-1",
+				&models.MetadataItem{Key: ec.String("hardware"), Value: ec.String("io")},
+				&models.MetadataItem{Key: ec.String("team"), Value: ec.String("a")},
+				&models.MetadataItem{Key: ec.String("broken")},
+				&models.MetadataItem{Value: ec.String("broken")},
+			),
+			newAllocator("allocator-3"),
+		}},
+	}}))
+}
+
+func newBulkParams(responses ...mock.Response) BulkParams {
+	return BulkParams{
+		API:        api.NewMock(append([]mock.Response{newBulkAllocators()}, responses...)...),
+		Region:     "us-east-1",
+		Query:      UnhealthyQuery,
+		FilterTags: "hardware:io",
+	}
+}
+
+func TestBulkSetMetadata(t *testing.T) {
+	var newMetadataAssertion = func(id, body string) mock.Response {
+		return mock.New200ResponseAssertion(&mock.RequestAssertion{
+			Header: api.DefaultWriteMockHeaders,
+			Method: "PUT",
+			Host:   api.DefaultMockHost,
+			Path:   "/api/v1/regions/us-east-1/platform/infrastructure/allocators/" + id + "/metadata",
+			Body:   mock.NewStringBody(body + "\n"),
+		}, mock.NewStringBody(`[]`))
+	}
+	type args struct {
+		params BulkSetMetadataParams
+	}
+	tests := []struct {
+		name string
+		args args
+		want *BulkReport
+		err  string
+	}{
+		{
+			name: "fails due to parameter validation",
+			err: multierror.NewPrefixed("invalid allocator bulk metadata params",
+				apierror.ErrMissingAPI,
+				errors.New("region not specified and is required for this operation"),
+				errors.New("metadata cannot be empty"),
+			).Error(),
+		},
+		{
+			name: "fails due to an empty metadata key",
+			args: args{params: BulkSetMetadataParams{
+				BulkParams: newBulkParams(),
+				Metadata:   map[string]string{"": "r1"},
+			}},
+			err: multierror.NewPrefixed("invalid allocator bulk metadata params",
+				errors.New("metadata keys cannot be empty"),
+			).Error(),
+		},
+		{
+			name: "merges the metadata into the matched allocators",
+			args: args{params: BulkSetMetadataParams{
+				BulkParams: newBulkParams(
+					newMetadataAssertion("allocator-1", `{"items":[{"key":"hardware","value":"io"},{"key":"rack","value":"r1"},{"key":"team","value":"a"}]}`),
+					newMetadataAssertion("allocator-2", `{"items":[{"key":"hardware","value":"io"},{"key":"rack","value":"r1"}]}`),
+				),
+				Metadata: map[string]string{"rack": "r1"},
+			}},
+			want: &BulkReport{Results: []BulkResult{
+				{AllocatorID: "allocator-1"},
+				{AllocatorID: "allocator-2"},
+			}},
+		},
+		{
+			name: "replaces the metadata and reports the failures",
+			args: args{params: BulkSetMetadataParams{
+				BulkParams: newBulkParams(
+					newMetadataAssertion("allocator-1", `{"items":[{"key":"rack","value":"r1"}]}`),
+					mock.New500Response(mock.NewStringBody(`{"error": "some error"}`)),
+				),
+				Metadata: map[string]string{"rack": "r1"},
+				Replace:  true,
+			}},
+			want: &BulkReport{Results: []BulkResult{
+				{AllocatorID: "allocator-1"},
+				{AllocatorID: "allocator-2", Error: errors.New(`{"error": "some error"}`)},
+			}},
+			err: multierror.NewPrefixed("allocator bulk metadata",
+				errors.New(`allocator allocator-2: {"error": "some error"}`),
+			).Error(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BulkSetMetadata(tt.args.params)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBulkUpdateSettings(t *testing.T) {
+	got, err := BulkUpdateSettings(BulkUpdateSettingsParams{})
+	assert.EqualError(t, err, multierror.NewPrefixed("invalid allocator bulk settings params",
+		apierror.ErrMissingAPI,
+		errors.New("region not specified and is required for this operation"),
+		errSettingsCannotBeNil,
+	).Error())
+	assert.Nil(t, got)
+
+	got, err = BulkUpdateSettings(BulkUpdateSettingsParams{
+		BulkParams: newBulkParams(
+			mock.New200Response(mock.NewStringBody(`{"capacity": 16384}`)),
+			mock.New200Response(mock.NewStringBody(`{"capacity": 16384}`)),
+		),
+		Settings: &models.AllocatorSettings{Capacity: 16384},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"allocator-1", "allocator-2"}, got.Succeeded())
+	assert.Empty(t, got.Failed())
+}
+
+func TestBulkUpdateLoggingSettings(t *testing.T) {
+	got, err := BulkUpdateLoggingSettings(BulkUpdateLoggingSettingsParams{})
+	assert.EqualError(t, err, multierror.NewPrefixed("invalid allocator bulk logging settings params",
+		apierror.ErrMissingAPI,
+		errors.New("region not specified and is required for this operation"),
+		errors.New("at least one logging level must be specified"),
+	).Error())
+	assert.Nil(t, got)
+
+	got, err = BulkUpdateLoggingSettings(BulkUpdateLoggingSettingsParams{
+		BulkParams: newBulkParams(
+			mock.New500Response(mock.NewStringBody(`{"error": "some error"}`)),
+			mock.New200Response(mock.NewStringBody(`{"logging_levels": {"root": "DEBUG"}}`)),
+		),
+		Levels: map[string]string{"root": "DEBUG"},
+	})
+	assert.Error(t, err)
+	assert.Equal(t, []string{"allocator-2"}, got.Succeeded())
+	assert.Equal(t, []string{"allocator-1"}, got.Failed())
+}
@@ -0,0 +1,423 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package allocatorapi
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/output"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+var (
+	errZoneConcurrencyCannotBeZero = errors.New("zone concurrency cannot be 0")
+	errRollingMaintenanceAborted   = errors.New("rolling maintenance aborted after reaching the failure threshold")
+)
+
+// MaintenanceStep is a step of the rolling maintenance of an allocator.
+type MaintenanceStep string
+
+const (
+	// MaintenanceStepStart enables maintenance mode on the allocator.
+	MaintenanceStepStart MaintenanceStep = "start_maintenance"
+
+	// MaintenanceStepVacate moves all of the instances off the allocator.
+	MaintenanceStepVacate MaintenanceStep = "vacate"
+
+	// MaintenanceStepHook calls the caller supplied hook.
+	MaintenanceStepHook MaintenanceStep = "hook"
+
+	// MaintenanceStepStop disables maintenance mode on the allocator.
+	MaintenanceStepStop MaintenanceStep = "stop_maintenance"
+)
+
+// MaintenanceStatus is the status of a step or an allocator.
+type MaintenanceStatus string
+
+const (
+	// MaintenanceStarted is the status of a step which has started.
+	MaintenanceStarted MaintenanceStatus = "started"
+
+	// MaintenanceCompleted is the status of a successful step or allocator.
+	MaintenanceCompleted MaintenanceStatus = "completed"
+
+	// MaintenanceFailed is the status of a failed step or allocator.
+	MaintenanceFailed MaintenanceStatus = "failed"
+
+	// MaintenanceSkipped is the status of an allocator which wasn't processed
+	// because the rolling maintenance was aborted.
+	MaintenanceSkipped MaintenanceStatus = "skipped"
+)
+
+// MaintenanceEvent is emitted on every step transition of the rolling
+// maintenance.
+type MaintenanceEvent struct {
+	AllocatorID string
+	Zone        string
+	Step        MaintenanceStep
+	Status      MaintenanceStatus
+	Err         error
+	DryRun      bool
+
+	// VacatePlan contains the moves which would be performed. Only set on
+	// the completed vacate step of a dry run.
+	VacatePlan *VacatePlan
+}
+
+// RollingMaintenanceParams is consumed by RollingMaintenance.
+type RollingMaintenanceParams struct {
+	*api.API
+
+	Region string
+
+	// Ordered list of allocators to perform the maintenance on. The order is
+	// preserved within each of the zones.
+	Allocators []string
+
+	// Maximum number of zones which have an allocator under maintenance at
+	// any time. Within a zone, allocators are processed one at a time.
+	ZoneConcurrency int
+
+	// Optional function called after an allocator has been vacated and
+	// before its maintenance mode is disabled, i.e. to patch the host.
+	Hook func(allocatorID, zone string) error
+
+	// Number of allocator failures which are tolerated, once exceeded the
+	// OnFailureThreshold function is called. When 0, the first failure
+	// exceeds the threshold.
+	FailureThreshold int
+
+	// Optional function called once the failure threshold has been reached,
+	// no new allocators are processed until it returns. Returning nil resumes
+	// the rolling maintenance and resets the failure count, returning an
+	// error aborts it. When nil, reaching the threshold aborts.
+	OnFailureThreshold func(failures []error) error
+
+	// Optional function which receives the progress events. Calls are
+	// serialised.
+	OnEvent func(MaintenanceEvent)
+
+	// DryRun computes the vacate plan of each allocator without enabling the
+	// maintenance mode, moving any instances or calling the Hook.
+	DryRun bool
+
+	// Maximum number of concurrent cluster moves for each vacate.
+	VacateConcurrency uint16
+
+	// Output device where the vacate progress will be sent.
+	Output *output.Device
+
+	// OutputFormat to use
+	OutputFormat string
+
+	// Maximum number of errors to allow the plan status poller to tolerate.
+	MaxPollRetries uint8
+
+	// Poll frequency
+	TrackFrequency time.Duration
+
+	// Plan body overrides to place in all of the vacate clusters.
+	PlanOverrides
+}
+
+// Validate ensures the parameters are usable.
+func (params RollingMaintenanceParams) Validate() error {
+	var merr = multierror.NewPrefixed("invalid allocator rolling maintenance params")
+	if params.API == nil {
+		merr = merr.Append(errAPIMustNotBeNil)
+	}
+
+	if len(params.Allocators) == 0 {
+		merr = merr.Append(errMustSpecifyAtLeast1Allocator)
+	}
+
+	var seen = make(map[string]bool, len(params.Allocators))
+	for _, id := range params.Allocators {
+		if seen[id] {
+			merr = merr.Append(fmt.Errorf("allocator %s is specified more than once", id))
+		}
+		seen[id] = true
+	}
+
+	if params.ZoneConcurrency <= 0 {
+		merr = merr.Append(errZoneConcurrencyCannotBeZero)
+	}
+
+	if params.VacateConcurrency == 0 {
+		merr = merr.Append(errConcurrencyCannotBeZero)
+	}
+
+	if params.Output == nil {
+		merr = merr.Append(errOutputDeviceCannotBeNil)
+	}
+
+	if err := ec.RequireRegionSet(params.Region); err != nil {
+		merr = merr.Append(err)
+	}
+
+	return merr.ErrorOrNil()
+}
+
+// RollingMaintenanceReport contains the outcome of each of the allocators,
+// in the order in which they were specified.
+type RollingMaintenanceReport struct {
+	Allocators []AllocatorMaintenance `json:"allocators"`
+	Aborted    bool                   `json:"aborted"`
+}
+
+// AllocatorMaintenance is the outcome of an allocator's maintenance.
+type AllocatorMaintenance struct {
+	ID     string            `json:"id"`
+	Zone   string            `json:"zone"`
+	Status MaintenanceStatus `json:"status"`
+	Error  string            `json:"error,omitempty"`
+}
+
+// RollingMaintenance performs the maintenance of the allocators: enables the
+// maintenance mode, vacates the allocator, calls the Hook and disables the
+// maintenance mode. Allocators which fail are left in maintenance mode. The
+// returned report is populated once the parameters are validated, even when
+// an error is returned.
+func RollingMaintenance(params RollingMaintenanceParams) (*RollingMaintenanceReport, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	var r = newRollingMaintenance(params)
+	var zones []string
+	var queues = make(map[string][]int)
+	var merr = multierror.NewPrefixed("allocator rolling maintenance")
+	for i, id := range params.Allocators {
+		alloc, err := Get(GetParams{API: params.API, ID: id, Region: params.Region})
+		if err != nil {
+			r.report.Allocators[i] = AllocatorMaintenance{
+				ID: id, Status: MaintenanceFailed, Error: err.Error(),
+			}
+			for j := i + 1; j < len(params.Allocators); j++ {
+				r.report.Allocators[j] = AllocatorMaintenance{
+					ID: params.Allocators[j], Status: MaintenanceSkipped,
+				}
+			}
+			return r.report, merr.Append(fmt.Errorf("allocator %s: %w", id, err))
+		}
+
+		var zone string
+		if alloc.ZoneID != nil {
+			zone = *alloc.ZoneID
+		}
+		r.report.Allocators[i] = AllocatorMaintenance{
+			ID: id, Zone: zone, Status: MaintenanceSkipped,
+		}
+
+		if _, ok := queues[zone]; !ok {
+			zones = append(zones, zone)
+		}
+		queues[zone] = append(queues[zone], i)
+	}
+
+	var wg sync.WaitGroup
+	var sem = make(chan struct{}, params.ZoneConcurrency)
+	for _, zone := range zones {
+		sem <- struct{}{}
+		if r.isAborted() {
+			<-sem
+			break
+		}
+
+		wg.Add(1)
+		go func(queue []int) {
+			defer func() { <-sem; wg.Done() }()
+			for _, i := range queue {
+				if r.isAborted() {
+					return
+				}
+				r.maintain(i)
+			}
+		}(queues[zone])
+	}
+	wg.Wait()
+
+	for _, a := range r.report.Allocators {
+		if a.Status == MaintenanceFailed {
+			merr = merr.Append(fmt.Errorf("allocator %s: %s", a.ID, a.Error))
+		}
+	}
+
+	if r.report.Aborted {
+		merr = merr.Append(errRollingMaintenanceAborted)
+	}
+
+	return r.report, merr.ErrorOrNil()
+}
+
+type rollingMaintenance struct {
+	params RollingMaintenanceParams
+	report *RollingMaintenanceReport
+
+	// mu protects the report, the failures and the event emission.
+	mu       sync.Mutex
+	failures []error
+
+	// gate is held while OnFailureThreshold is called.
+	gate sync.Mutex
+}
+
+func newRollingMaintenance(params RollingMaintenanceParams) *rollingMaintenance {
+	return &rollingMaintenance{
+		params: params,
+		report: &RollingMaintenanceReport{
+			Allocators: make([]AllocatorMaintenance, len(params.Allocators)),
+		},
+	}
+}
+
+// isAborted waits for any threshold handling to finish and returns whether
+// the rolling maintenance has been aborted.
+func (r *rollingMaintenance) isAborted() bool {
+	r.gate.Lock()
+	defer r.gate.Unlock()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.report.Aborted
+}
+
+func (r *rollingMaintenance) emit(e MaintenanceEvent) {
+	if r.params.OnEvent == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e.DryRun = r.params.DryRun
+	r.params.OnEvent(e)
+}
+
+// maintain performs the maintenance steps of the allocator at the index.
+func (r *rollingMaintenance) maintain(i int) {
+	var alloc = r.report.Allocators[i]
+	var steps = []struct {
+		step MaintenanceStep
+		run  func() (*VacatePlan, error)
+	}{
+		{MaintenanceStepStart, func() (*VacatePlan, error) {
+			return nil, StartMaintenance(r.maintenanceParams(alloc.ID))
+		}},
+		{MaintenanceStepVacate, func() (*VacatePlan, error) { return r.vacate(alloc.ID) }},
+		{MaintenanceStepHook, func() (*VacatePlan, error) {
+			if r.params.Hook == nil {
+				return nil, nil
+			}
+			return nil, r.params.Hook(alloc.ID, alloc.Zone)
+		}},
+		{MaintenanceStepStop, func() (*VacatePlan, error) {
+			return nil, StopMaintenance(r.maintenanceParams(alloc.ID))
+		}},
+	}
+
+	for _, s := range steps {
+		var event = MaintenanceEvent{AllocatorID: alloc.ID, Zone: alloc.Zone, Step: s.step}
+		event.Status = MaintenanceStarted
+		r.emit(event)
+
+		var err error
+		if !r.params.DryRun || s.step == MaintenanceStepVacate {
+			event.VacatePlan, err = s.run()
+		}
+
+		if err != nil {
+			event.Status, event.Err = MaintenanceFailed, err
+			r.emit(event)
+			r.fail(i, err)
+			return
+		}
+
+		event.Status = MaintenanceCompleted
+		r.emit(event)
+	}
+
+	r.mu.Lock()
+	r.report.Allocators[i].Status = MaintenanceCompleted
+	r.mu.Unlock()
+}
+
+func (r *rollingMaintenance) maintenanceParams(id string) MaintenanceParams {
+	return MaintenanceParams{API: r.params.API, ID: id, Region: r.params.Region}
+}
+
+// vacate vacates the allocator, or computes its vacate plan on dry runs.
+func (r *rollingMaintenance) vacate(id string) (*VacatePlan, error) {
+	if r.params.DryRun {
+		return PlanVacate(VacatePlanParams{
+			API:           r.params.API,
+			Region:        r.params.Region,
+			Allocators:    []string{id},
+			PlanOverrides: r.params.PlanOverrides,
+		})
+	}
+
+	return nil, Vacate(&VacateParams{
+		API:            r.params.API,
+		Region:         r.params.Region,
+		Allocators:     []string{id},
+		Concurrency:    r.params.VacateConcurrency,
+		Output:         r.params.Output,
+		OutputFormat:   r.params.OutputFormat,
+		MaxPollRetries: r.params.MaxPollRetries,
+		TrackFrequency: r.params.TrackFrequency,
+		PlanOverrides:  r.params.PlanOverrides,
+	})
+}
+
+// fail records the allocator failure and handles the failure threshold.
+func (r *rollingMaintenance) fail(i int, err error) {
+	r.mu.Lock()
+	r.report.Allocators[i].Status = MaintenanceFailed
+	r.report.Allocators[i].Error = err.Error()
+	r.failures = append(r.failures, err)
+
+	var failures []error
+	if len(r.failures) > r.params.FailureThreshold {
+		failures, r.failures = r.failures, nil
+	}
+	r.mu.Unlock()
+
+	if failures == nil {
+		return
+	}
+
+	r.gate.Lock()
+	defer r.gate.Unlock()
+
+	var abort = r.params.OnFailureThreshold == nil
+	if !abort {
+		abort = r.params.OnFailureThreshold(failures) != nil
+	}
+
+	if abort {
+		r.mu.Lock()
+		r.report.Aborted = true
+		r.mu.Unlock()
+	}
+}
@@ -0,0 +1,259 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package allocatorapi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/mock"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/output"
+	sdkSync "github.com/elastic/cloud-sdk-go/pkg/sync"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+func newMaintenanceAllocator(id, zone string) mock.Response {
+	return mock.New200StructResponse(models.AllocatorInfo{
+		AllocatorID: ec.String(id),
+		ZoneID:      ec.String(zone),
+		Status: &models.AllocatorHealthStatus{
+			Connected: ec.Bool(true),
+			Healthy:   ec.Bool(true),
+		},
+	})
+}
+
+func newNoMoves() mock.Response {
+	return mock.New202Response(mock.NewStructBody(models.MoveClustersCommandResponse{
+		Moves:    &models.MoveClustersDetails{},
+		Failures: &models.MoveClustersDetails{},
+	}))
+}
+
+func newMaintenanceAssertion(id, action string) mock.Response {
+	return mock.New202ResponseAssertion(&mock.RequestAssertion{
+		Header: api.DefaultWriteMockHeaders,
+		Method: "POST",
+		Host:   api.DefaultMockHost,
+		Path:   "/api/v1/regions/us-east-1/platform/infrastructure/allocators/" + id + "/maintenance-mode/_" + action,
+	}, mock.NewStringBody(`{}`))
+}
+
+type maintenanceEvents struct {
+	events []MaintenanceEvent
+}
+
+func (e *maintenanceEvents) record(event MaintenanceEvent) {
+	event.VacatePlan = nil
+	e.events = append(e.events, event)
+}
+
+func newMaintenanceEvents(id, zone string, dryRun bool, steps ...MaintenanceStep) []MaintenanceEvent {
+	var events []MaintenanceEvent
+	for _, step := range steps {
+		events = append(events,
+			MaintenanceEvent{AllocatorID: id, Zone: zone, Step: step, Status: MaintenanceStarted, DryRun: dryRun},
+			MaintenanceEvent{AllocatorID: id, Zone: zone, Step: step, Status: MaintenanceCompleted, DryRun: dryRun},
+		)
+	}
+	return events
+}
+
+var allMaintenanceSteps = []MaintenanceStep{
+	MaintenanceStepStart, MaintenanceStepVacate, MaintenanceStepHook, MaintenanceStepStop,
+}
+
+func TestRollingMaintenance(t *testing.T) {
+	var someErr = errors.New("some error")
+	var newParams = func(a *api.API, allocators ...string) RollingMaintenanceParams {
+		return RollingMaintenanceParams{
+			API:               a,
+			Region:            "us-east-1",
+			Allocators:        allocators,
+			ZoneConcurrency:   1,
+			VacateConcurrency: 1,
+			Output:            output.NewDevice(sdkSync.NewBuffer()),
+		}
+	}
+	tests := []struct {
+		name       string
+		params     RollingMaintenanceParams
+		hookErrs   []error
+		onFailure  func(failures []error) error
+		want       *RollingMaintenanceReport
+		wantHooks  []string
+		wantEvents []MaintenanceEvent
+		err        error
+	}{
+		{
+			name:   "fails due to parameter validation",
+			params: RollingMaintenanceParams{Allocators: []string{"allocator-1", "allocator-1"}},
+			err: multierror.NewPrefixed("invalid allocator rolling maintenance params",
+				errAPIMustNotBeNil,
+				errors.New("allocator allocator-1 is specified more than once"),
+				errZoneConcurrencyCannotBeZero,
+				errConcurrencyCannotBeZero,
+				errOutputDeviceCannotBeNil,
+				errors.New("region not specified and is required for this operation"),
+			),
+		},
+		{
+			name: "returns the report when an allocator can't be obtained",
+			params: newParams(api.NewMock(
+				newMaintenanceAllocator("allocator-1", "zone-1"),
+				mock.New500Response(mock.NewStringBody(`{"error": "allocator not found"}`)),
+			), "allocator-1", "allocator-2", "allocator-3"),
+			want: &RollingMaintenanceReport{Allocators: []AllocatorMaintenance{
+				{ID: "allocator-1", Zone: "zone-1", Status: MaintenanceSkipped},
+				{ID: "allocator-2", Status: MaintenanceFailed, Error: `{"error": "allocator not found"}`},
+				{ID: "allocator-3", Status: MaintenanceSkipped},
+			}},
+			err: multierror.NewPrefixed("allocator rolling maintenance",
+				errors.New(`allocator allocator-2: {"error": "allocator not found"}`),
+			),
+		},
+		{
+			name: "computes the vacate plan on dry runs",
+			params: func() RollingMaintenanceParams {
+				var params = newParams(api.NewMock(
+					newMaintenanceAllocator("allocator-1", "zone-1"),
+					newMaintenanceAllocator("allocator-1", "zone-1"),
+					newNoMoves(),
+					mock.New200StructResponse(models.AllocatorOverview{}),
+				), "allocator-1")
+				params.DryRun = true
+				return params
+			}(),
+			want: &RollingMaintenanceReport{Allocators: []AllocatorMaintenance{
+				{ID: "allocator-1", Zone: "zone-1", Status: MaintenanceCompleted},
+			}},
+			wantEvents: newMaintenanceEvents("allocator-1", "zone-1", true, allMaintenanceSteps...),
+		},
+		{
+			name: "performs the maintenance of the allocators in order",
+			params: newParams(api.NewMock(
+				newMaintenanceAllocator("allocator-1", "zone-1"),
+				newMaintenanceAllocator("allocator-2", "zone-2"),
+				newMaintenanceAssertion("allocator-1", "start"),
+				newNoMoves(),
+				newMaintenanceAssertion("allocator-1", "stop"),
+				newMaintenanceAssertion("allocator-2", "start"),
+				newNoMoves(),
+				newMaintenanceAssertion("allocator-2", "stop"),
+			), "allocator-1", "allocator-2"),
+			want: &RollingMaintenanceReport{Allocators: []AllocatorMaintenance{
+				{ID: "allocator-1", Zone: "zone-1", Status: MaintenanceCompleted},
+				{ID: "allocator-2", Zone: "zone-2", Status: MaintenanceCompleted},
+			}},
+			wantHooks: []string{"allocator-1", "allocator-2"},
+			wantEvents: append(
+				newMaintenanceEvents("allocator-1", "zone-1", false, allMaintenanceSteps...),
+				newMaintenanceEvents("allocator-2", "zone-2", false, allMaintenanceSteps...)...,
+			),
+		},
+		{
+			name: "aborts when the failure threshold is exceeded",
+			params: newParams(api.NewMock(
+				newMaintenanceAllocator("allocator-1", "zone-1"),
+				newMaintenanceAllocator("allocator-2", "zone-1"),
+				newMaintenanceAssertion("allocator-1", "start"),
+				newNoMoves(),
+			), "allocator-1", "allocator-2"),
+			hookErrs: []error{someErr},
+			want: &RollingMaintenanceReport{
+				Allocators: []AllocatorMaintenance{
+					{ID: "allocator-1", Zone: "zone-1", Status: MaintenanceFailed, Error: "some error"},
+					{ID: "allocator-2", Zone: "zone-1", Status: MaintenanceSkipped},
+				},
+				Aborted: true,
+			},
+			wantHooks: []string{"allocator-1"},
+			wantEvents: append(
+				newMaintenanceEvents("allocator-1", "zone-1", false, MaintenanceStepStart, MaintenanceStepVacate),
+				MaintenanceEvent{AllocatorID: "allocator-1", Zone: "zone-1", Step: MaintenanceStepHook, Status: MaintenanceStarted},
+				MaintenanceEvent{AllocatorID: "allocator-1", Zone: "zone-1", Step: MaintenanceStepHook, Status: MaintenanceFailed, Err: someErr},
+			),
+			err: multierror.NewPrefixed("allocator rolling maintenance",
+				errors.New("allocator allocator-1: some error"),
+				errRollingMaintenanceAborted,
+			),
+		},
+		{
+			name: "resumes when the failure threshold function returns nil",
+			params: newParams(api.NewMock(
+				newMaintenanceAllocator("allocator-1", "zone-1"),
+				newMaintenanceAllocator("allocator-2", "zone-1"),
+				newMaintenanceAssertion("allocator-1", "start"),
+				newNoMoves(),
+				newMaintenanceAssertion("allocator-2", "start"),
+				newNoMoves(),
+				newMaintenanceAssertion("allocator-2", "stop"),
+			), "allocator-1", "allocator-2"),
+			hookErrs: []error{someErr},
+			onFailure: func(failures []error) error {
+				if len(failures) != 1 {
+					return errors.New("unexpected failures")
+				}
+				return nil
+			},
+			want: &RollingMaintenanceReport{Allocators: []AllocatorMaintenance{
+				{ID: "allocator-1", Zone: "zone-1", Status: MaintenanceFailed, Error: "some error"},
+				{ID: "allocator-2", Zone: "zone-1", Status: MaintenanceCompleted},
+			}},
+			wantHooks: []string{"allocator-1", "allocator-2"},
+			wantEvents: append(append(
+				newMaintenanceEvents("allocator-1", "zone-1", false, MaintenanceStepStart, MaintenanceStepVacate),
+				MaintenanceEvent{AllocatorID: "allocator-1", Zone: "zone-1", Step: MaintenanceStepHook, Status: MaintenanceStarted},
+				MaintenanceEvent{AllocatorID: "allocator-1", Zone: "zone-1", Step: MaintenanceStepHook, Status: MaintenanceFailed, Err: someErr},
+			), newMaintenanceEvents("allocator-2", "zone-1", false, allMaintenanceSteps...)...),
+			err: multierror.NewPrefixed("allocator rolling maintenance",
+				errors.New("allocator allocator-1: some error"),
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var events maintenanceEvents
+			var hooks []string
+			tt.params.OnEvent = events.record
+			tt.params.OnFailureThreshold = tt.onFailure
+			tt.params.Hook = func(id, zone string) error {
+				hooks = append(hooks, id)
+				if len(tt.hookErrs) >= len(hooks) {
+					return tt.hookErrs[len(hooks)-1]
+				}
+				return nil
+			}
+
+			got, err := RollingMaintenance(tt.params)
+			if tt.err != nil {
+				assert.EqualError(t, err, tt.err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantHooks, hooks)
+			assert.Equal(t, tt.wantEvents, events.events)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package allocatorapi
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/api/mock"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+func TestSearchIterator(t *testing.T) {
+	var pageResponse = func(from, size int32, zones map[string][]string) mock.Response {
+		var res models.AllocatorOverview
+		for _, zone := range []string{"zone-a", "zone-b"} {
+			var info = models.AllocatorZoneInfo{ZoneID: ec.String(zone)}
+			for _, id := range zones[zone] {
+				info.Allocators = append(info.Allocators, &models.AllocatorInfo{AllocatorID: ec.String(id)})
+			}
+			if len(info.Allocators) > 0 {
+				res.Zones = append(res.Zones, &info)
+			}
+		}
+		return mock.New200ResponseAssertion(&mock.RequestAssertion{
+			Header: api.DefaultWriteMockHeaders,
+			Method: "POST",
+			Host:   api.DefaultMockHost,
+			Path:   "/api/v1/regions/us-east-1/platform/infrastructure/allocators/_search",
+			Body:   mock.NewStructBody(models.SearchRequest{From: from, Size: size}),
+		}, mock.NewStructBody(res))
+	}
+
+	t.Run("fails due to parameter validation", func(t *testing.T) {
+		got, err := NewSearchIterator(context.Background(), SearchIteratorParams{})
+		assert.Nil(t, got)
+		assert.Equal(t, multierror.NewPrefixed("invalid allocator search iterator params",
+			apierror.ErrMissingAPI,
+			errors.New("region not specified and is required for this operation"),
+		), err)
+	})
+
+	t.Run("iterates over the allocators of all the zones and pages", func(t *testing.T) {
+		it, err := NewSearchIterator(context.Background(), SearchIteratorParams{
+			API: api.NewMock(
+				pageResponse(0, 3, map[string][]string{"zone-a": {"a1", "a2"}, "zone-b": {"b1"}}),
+				pageResponse(3, 3, map[string][]string{"zone-b": {"b2"}}),
+			),
+			Region:   "us-east-1",
+			PageSize: 3,
+		})
+		assert.NoError(t, err)
+
+		var got []string
+		for it.Next() {
+			got = append(got, *it.Allocator().AllocatorID)
+		}
+		assert.NoError(t, it.Err())
+		assert.Equal(t, []string{"a1", "a2", "b1", "b2"}, got)
+	})
+
+	t.Run("stops when a page fails to be fetched", func(t *testing.T) {
+		it, err := NewSearchIterator(context.Background(), SearchIteratorParams{
+			API:    api.NewMock(mock.New500Response(mock.NewStringBody(`{"error": "some error"}`))),
+			Region: "us-east-1",
+		})
+		assert.NoError(t, err)
+		assert.False(t, it.Next())
+		assert.EqualError(t, it.Err(), `{"error": "some error"}`)
+	})
+}
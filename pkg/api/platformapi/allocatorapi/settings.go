@@ -0,0 +1,152 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package allocatorapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/client/platform_infrastructure"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+var errSettingsCannotBeNil = errors.New("settings cannot be nil")
+
+// SettingsGetParams is used to retrieve the allocator settings
+type SettingsGetParams struct {
+	*api.API
+	ID     string
+	Region string
+}
+
+// Validate ensures that the parameters are correct
+func (params SettingsGetParams) Validate() error {
+	var merr = multierror.NewPrefixed("invalid allocator settings get params")
+	if params.API == nil {
+		merr = merr.Append(apierror.ErrMissingAPI)
+	}
+	if params.ID == "" {
+		merr = merr.Append(errors.New("id cannot be empty"))
+	}
+	if err := ec.RequireRegionSet(params.Region); err != nil {
+		merr = merr.Append(err)
+	}
+	return merr.ErrorOrNil()
+}
+
+// GetSettings retrieves the settings of a given allocator
+func GetSettings(params SettingsGetParams) (*models.AllocatorSettings, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	res, err := params.API.V1API.PlatformInfrastructure.GetAllocatorSettings(
+		platform_infrastructure.NewGetAllocatorSettingsParams().
+			WithContext(api.WithRegion(context.Background(), params.Region)).
+			WithAllocatorID(params.ID),
+		params.AuthWriter,
+	)
+	if err != nil {
+		return nil, api.UnwrapError(err)
+	}
+
+	return res.Payload, nil
+}
+
+// SettingsSetParams is used to set or update the allocator settings
+type SettingsSetParams struct {
+	*api.API
+	ID       string
+	Region   string
+	Settings *models.AllocatorSettings
+
+	// Optional version of the settings, the operation fails when it doesn't
+	// match the current version.
+	Version *int64
+}
+
+// Validate ensures that the parameters are correct
+func (params SettingsSetParams) Validate() error {
+	var merr = multierror.NewPrefixed("invalid allocator settings set params")
+	if params.API == nil {
+		merr = merr.Append(apierror.ErrMissingAPI)
+	}
+	if params.ID == "" {
+		merr = merr.Append(errors.New("id cannot be empty"))
+	}
+	if params.Settings == nil {
+		merr = merr.Append(errSettingsCannotBeNil)
+	}
+	if err := ec.RequireRegionSet(params.Region); err != nil {
+		merr = merr.Append(err)
+	}
+	return merr.ErrorOrNil()
+}
+
+// SetSettings replaces the settings of a given allocator
+func SetSettings(params SettingsSetParams) (*models.AllocatorSettings, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	res, err := params.API.V1API.PlatformInfrastructure.SetAllocatorSettings(
+		platform_infrastructure.NewSetAllocatorSettingsParams().
+			WithContext(api.WithRegion(context.Background(), params.Region)).
+			WithAllocatorID(params.ID).
+			WithVersion(params.Version).
+			WithBody(params.Settings),
+		params.AuthWriter,
+	)
+	if err != nil {
+		return nil, api.UnwrapError(err)
+	}
+
+	return res.Payload, nil
+}
+
+// UpdateSettings applies the non empty settings as a partial update of the
+// settings of a given allocator, i.e. to override the allocator capacity.
+func UpdateSettings(params SettingsSetParams) (*models.AllocatorSettings, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(params.Settings)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := params.API.V1API.PlatformInfrastructure.UpdateAllocatorSettings(
+		platform_infrastructure.NewUpdateAllocatorSettingsParams().
+			WithContext(api.WithRegion(context.Background(), params.Region)).
+			WithAllocatorID(params.ID).
+			WithVersion(params.Version).
+			WithBody(string(body)),
+		params.AuthWriter,
+	)
+	if err != nil {
+		return nil, api.UnwrapError(err)
+	}
+
+	return res.Payload, nil
+}
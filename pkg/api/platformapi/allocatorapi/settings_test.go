@@ -0,0 +1,188 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package allocatorapi
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/api/mock"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+const settingsPath = "/api/v1/regions/us-east-1/platform/infrastructure/allocators/allocator-1/settings"
+
+func TestGetSettings(t *testing.T) {
+	type args struct {
+		params SettingsGetParams
+	}
+	tests := []struct {
+		name string
+		args args
+		want *models.AllocatorSettings
+		err  error
+	}{
+		{
+			name: "fails due to parameter validation",
+			err: multierror.NewPrefixed("invalid allocator settings get params",
+				apierror.ErrMissingAPI,
+				errors.New("id cannot be empty"),
+				errors.New("region not specified and is required for this operation"),
+			),
+		},
+		{
+			name: "fails due to API failure",
+			args: args{params: SettingsGetParams{
+				ID:     "allocator-1",
+				Region: "us-east-1",
+				API:    api.NewMock(mock.New500Response(mock.NewStringBody(`{"error": "some error"}`))),
+			}},
+			err: errors.New(`{"error": "some error"}`),
+		},
+		{
+			name: "succeeds",
+			args: args{params: SettingsGetParams{
+				ID:     "allocator-1",
+				Region: "us-east-1",
+				API: api.NewMock(mock.New200ResponseAssertion(&mock.RequestAssertion{
+					Header: api.DefaultReadMockHeaders,
+					Method: "GET",
+					Host:   api.DefaultMockHost,
+					Path:   settingsPath,
+				}, mock.NewStringBody(`{"capacity": 8192}`))),
+			}},
+			want: &models.AllocatorSettings{Capacity: 8192},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetSettings(tt.args.params)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSetSettings(t *testing.T) {
+	type args struct {
+		params SettingsSetParams
+	}
+	tests := []struct {
+		name string
+		args args
+		want *models.AllocatorSettings
+		err  error
+	}{
+		{
+			name: "fails due to parameter validation",
+			err: multierror.NewPrefixed("invalid allocator settings set params",
+				apierror.ErrMissingAPI,
+				errors.New("id cannot be empty"),
+				errSettingsCannotBeNil,
+				errors.New("region not specified and is required for this operation"),
+			),
+		},
+		{
+			name: "succeeds",
+			args: args{params: SettingsSetParams{
+				ID:       "allocator-1",
+				Region:   "us-east-1",
+				Settings: &models.AllocatorSettings{Capacity: 8192},
+				Version:  ec.Int64(2),
+				API: api.NewMock(mock.New200ResponseAssertion(&mock.RequestAssertion{
+					Header: api.DefaultWriteMockHeaders,
+					Method: "PUT",
+					Host:   api.DefaultMockHost,
+					Path:   settingsPath,
+					Query:  url.Values{"version": {"2"}},
+					Body:   mock.NewStringBody(`{"capacity":8192}` + "\n"),
+				}, mock.NewStringBody(`{"capacity": 8192}`))),
+			}},
+			want: &models.AllocatorSettings{Capacity: 8192},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SetSettings(tt.args.params)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestUpdateSettings(t *testing.T) {
+	type args struct {
+		params SettingsSetParams
+	}
+	tests := []struct {
+		name string
+		args args
+		want *models.AllocatorSettings
+		err  error
+	}{
+		{
+			name: "fails due to parameter validation",
+			err: multierror.NewPrefixed("invalid allocator settings set params",
+				apierror.ErrMissingAPI,
+				errors.New("id cannot be empty"),
+				errSettingsCannotBeNil,
+				errors.New("region not specified and is required for this operation"),
+			),
+		},
+		{
+			name: "fails due to API failure",
+			args: args{params: SettingsSetParams{
+				ID:       "allocator-1",
+				Region:   "us-east-1",
+				Settings: &models.AllocatorSettings{Capacity: 8192},
+				API:      api.NewMock(mock.New500Response(mock.NewStringBody(`{"error": "some error"}`))),
+			}},
+			err: errors.New(`{"error": "some error"}`),
+		},
+		{
+			name: "succeeds",
+			args: args{params: SettingsSetParams{
+				ID:       "allocator-1",
+				Region:   "us-east-1",
+				Settings: &models.AllocatorSettings{Capacity: 16384},
+				API: api.NewMock(mock.New200ResponseAssertion(&mock.RequestAssertion{
+					Header: api.DefaultWriteMockHeaders,
+					Method: "PATCH",
+					Host:   api.DefaultMockHost,
+					Path:   settingsPath,
+					Body:   mock.NewStringBody(`"{\"capacity\":16384}"` + "\n"),
+				}, mock.NewStringBody(`{"capacity": 16384}`))),
+			}},
+			want: &models.AllocatorSettings{Capacity: 16384},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UpdateSettings(tt.args.params)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
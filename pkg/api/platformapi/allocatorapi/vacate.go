@@ -31,6 +31,7 @@ import (
 	"github.com/elastic/cloud-sdk-go/pkg/multierror"
 	"github.com/elastic/cloud-sdk-go/pkg/plan"
 	"github.com/elastic/cloud-sdk-go/pkg/plan/planutil"
+	"github.com/elastic/cloud-sdk-go/pkg/resourcekind"
 	"github.com/elastic/cloud-sdk-go/pkg/sync/pool"
 	"github.com/elastic/cloud-sdk-go/pkg/util"
 	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
@@ -53,6 +54,13 @@ func Vacate(params *VacateParams) error {
 		return err
 	}
 
+	return vacate(params, nil)
+}
+
+// vacate performs the vacate, skipping any of the moves which are recorded as
+// completed or in flight in the journaled entries, in flight moves are
+// re-attached to.
+func vacate(params *VacateParams, journaled map[string]VacateJournalEntry) error {
 	var emptyTimeout pool.Timeout
 	if params.PoolTimeout == emptyTimeout {
 		params.PoolTimeout = pool.DefaultTimeout
@@ -71,7 +79,11 @@ func Vacate(params *VacateParams) error {
 	// Errors reported here are returned by a dry-run execution of the move api (validation-only flag is used)
 	// and we don't want to stop the real vacate.
 	// Instead we are returning the validateOnlyErr with the actual vacate validateOnlyErr at the end of the function
-	leftovers, hasWork, validateOnlyErr := moveAllocators(params, p)
+	leftovers, hasWork, validateOnlyErr := moveAllocators(params, p, journaled)
+	if resumed := newResumedVacates(params, journaled); len(resumed) > 0 {
+		leftovers = append(leftovers, resumed...)
+		hasWork = true
+	}
 
 	if err := p.Start(); err != nil {
 		return err
@@ -99,12 +111,12 @@ func Vacate(params *VacateParams) error {
 // nodes off each allocator, finally, returns any leftovers from full pool
 // queues, whether or not any work was added to the pool, and potential errors
 // returned from API calls.
-func moveAllocators(params *VacateParams, p *pool.Pool) ([]pool.Validator, bool, error) {
+func moveAllocators(params *VacateParams, p *pool.Pool, journaled map[string]VacateJournalEntry) ([]pool.Validator, bool, error) {
 	var leftovers []pool.Validator
 	var merr = multierror.NewPrefixed("vacate error")
 	var hasWork bool
 	for _, id := range params.Allocators {
-		left, moved, err := moveNodes(id, params, p)
+		left, moved, err := moveNodes(id, params, p, journaled)
 		merr = merr.Append(err)
 		if len(left) > 0 {
 			leftovers = append(leftovers, left...)
@@ -118,7 +130,7 @@ func moveAllocators(params *VacateParams, p *pool.Pool) ([]pool.Validator, bool,
 }
 
 // moveNodes moves all of the nodes off the specified allocator
-func moveNodes(id string, params *VacateParams, p *pool.Pool) ([]pool.Validator, bool, error) {
+func moveNodes(id string, params *VacateParams, p *pool.Pool, journaled map[string]VacateJournalEntry) ([]pool.Validator, bool, error) {
 	var merr = multierror.NewPrefixed(fmt.Sprintf("allocator %s", id))
 	res, err := params.API.V1API.PlatformInfrastructure.MoveClusters(
 		platform_infrastructure.NewMoveClustersParams().
@@ -142,6 +154,7 @@ func moveNodes(id string, params *VacateParams, p *pool.Pool) ([]pool.Validator,
 		Pool:         p,
 		Moves:        res.Payload.Moves,
 		VacateParams: params,
+		Journaled:    journaled,
 	})
 
 	return work, hasWork, merr.ErrorOrNil()
@@ -192,64 +205,22 @@ func addAllocatorMovesToPool(params addAllocatorMovesToPoolParams) ([]pool.Valid
 
 	var filter = params.VacateParams.ClusterFilter
 	var kindFilter = params.VacateParams.KindFilter
-	for _, move := range params.Moves.ElasticsearchClusters {
-		if len(filter) > 0 && !slice.HasString(filter, *move.ClusterID) {
-			continue
-		}
-
-		var kind = util.Elasticsearch
-		if kindFilter != "" && kind != kindFilter {
-			break
-		}
-		vacates = append(vacates, newVacateClusterParams(params, *move.ClusterID, kind))
-	}
-
-	for _, move := range params.Moves.KibanaClusters {
-		if len(filter) > 0 && !slice.HasString(filter, *move.ClusterID) {
-			continue
-		}
-
-		var kind = util.Kibana
-		if kindFilter != "" && kind != kindFilter {
-			break
-		}
-		vacates = append(vacates, newVacateClusterParams(params, *move.ClusterID, kind))
-	}
-
-	for _, move := range params.Moves.ApmClusters {
-		if len(filter) > 0 && !slice.HasString(filter, *move.ClusterID) {
+	for _, kind := range resourcekind.All() {
+		if kindFilter != "" && kind.Name != kindFilter {
 			continue
 		}
 
-		var kind = util.Apm
-		if kindFilter != "" && kind != kindFilter {
-			break
+		for _, move := range kind.Moves(params.Moves) {
+			for _, id := range resourcekind.MoveClusterIDs(move) {
+				if len(filter) > 0 && !slice.HasString(filter, id) {
+					continue
+				}
+				if params.journaled(id, kind.Name) {
+					continue
+				}
+				vacates = append(vacates, newVacateClusterParams(params, id, kind.Name))
+			}
 		}
-		vacates = append(vacates, newVacateClusterParams(params, *move.ClusterID, kind))
-	}
-
-	for _, move := range params.Moves.AppsearchClusters {
-		if len(filter) > 0 && !slice.HasString(filter, *move.ClusterID) {
-			continue
-		}
-
-		var kind = util.Appsearch
-		if kindFilter != "" && kind != kindFilter {
-			break
-		}
-		vacates = append(vacates, newVacateClusterParams(params, *move.ClusterID, kind))
-	}
-
-	for _, move := range params.Moves.EnterpriseSearchClusters {
-		if len(filter) > 0 && !slice.HasString(filter, *move.ClusterID) {
-			continue
-		}
-
-		var kind = util.EnterpriseSearch
-		if kindFilter != "" && kind != kindFilter {
-			break
-		}
-		vacates = append(vacates, newVacateClusterParams(params, *move.ClusterID, kind))
 	}
 
 	if leftover, _ := params.Pool.Add(vacates...); len(leftover) > 0 {
@@ -275,6 +246,7 @@ func newVacateClusterParams(params addAllocatorMovesToPoolParams, id, kind strin
 		OutputFormat:        params.VacateParams.OutputFormat,
 		MoveOnly:            params.VacateParams.MoveOnly,
 		PlanOverrides:       params.VacateParams.PlanOverrides,
+		Journal:             params.VacateParams.Journal,
 	}
 }
 
@@ -299,15 +271,27 @@ func VacateCluster(params *VacateClusterParams) error {
 		return err
 	}
 
-	if err := moveClusterByType(params); err != nil {
-		return err
+	if !params.Resume {
+		if err := recordVacateState(params, VacatePending, nil); err != nil {
+			return err
+		}
+
+		if err := moveClusterByType(params); err != nil {
+			return recordVacateState(params, VacateFailed, err)
+		}
+
+		if err := recordVacateState(params, VacateMoving, nil); err != nil {
+			return err
+		}
 	}
 
+	// The untracked moves are recorded as completed once submitted, so that
+	// a resumed vacate doesn't re-attach to them.
 	if params.SkipTracking {
-		return nil
+		return recordVacateState(params, VacateCompleted, nil)
 	}
 
-	return planutil.TrackChange(planutil.TrackChangeParams{
+	err = planutil.TrackChange(planutil.TrackChangeParams{
 		TrackChangeParams: plan.TrackChangeParams{
 			API:              params.API,
 			ResourceID:       params.ClusterID,
@@ -321,6 +305,11 @@ func VacateCluster(params *VacateClusterParams) error {
 		Writer: params.Output,
 		Format: params.OutputFormat,
 	})
+	if err != nil {
+		return recordVacateState(params, VacateFailed, err)
+	}
+
+	return recordVacateState(params, VacateCompleted, nil)
 }
 
 // fillVacateClusterParams validates the parameters and fills any missing
@@ -388,24 +377,27 @@ func wrapVacateError(err error, params *VacateClusterParams, ctx string) error {
 
 // newMoveClusterParams
 func newMoveClusterParams(params *VacateClusterParams) (*platform_infrastructure.MoveClustersByTypeParams, error) {
-	res, err := params.API.V1API.PlatformInfrastructure.MoveClusters(
-		platform_infrastructure.NewMoveClustersParams().
-			WithAllocatorDown(params.AllocatorDown).
-			WithMoveOnly(params.MoveOnly).
-			WithAllocatorID(params.ID).
-			WithContext(api.WithRegion(context.Background(), params.Region)).
-			WithValidateOnly(ec.Bool(true)),
-		params.AuthWriter,
-	)
-	if err != nil {
-		return nil, wrapVacateError(api.UnwrapError(err), params, "validate_only")
-	}
+	var req = params.MoveRequest
+	if req == nil {
+		res, err := params.API.V1API.PlatformInfrastructure.MoveClusters(
+			platform_infrastructure.NewMoveClustersParams().
+				WithAllocatorDown(params.AllocatorDown).
+				WithMoveOnly(params.MoveOnly).
+				WithAllocatorID(params.ID).
+				WithContext(api.WithRegion(context.Background(), params.Region)).
+				WithValidateOnly(ec.Bool(true)),
+			params.AuthWriter,
+		)
+		if err != nil {
+			return nil, wrapVacateError(api.UnwrapError(err), params, "validate_only")
+		}
 
-	req := ComputeVacateRequest(res.Payload.Moves,
-		[]string{params.ClusterID},
-		params.PreferredAllocators,
-		params.PlanOverrides,
-	)
+		req = ComputeVacateRequest(res.Payload.Moves,
+			[]string{params.ClusterID},
+			params.PreferredAllocators,
+			params.PlanOverrides,
+		)
+	}
 
 	var moveParams = platform_infrastructure.NewMoveClustersByTypeParams().
 		WithAllocatorID(params.ID).
@@ -413,24 +405,10 @@ func newMoveClusterParams(params *VacateClusterParams) (*platform_infrastructure
 		WithContext(api.WithRegion(context.Background(), params.Region)).
 		WithBody(req)
 
-	if len(req.ElasticsearchClusters) > 0 {
-		moveParams.SetClusterType(ec.String(util.Elasticsearch))
-	}
-
-	if len(req.KibanaClusters) > 0 {
-		moveParams.SetClusterType(ec.String(util.Kibana))
-	}
-
-	if len(req.ApmClusters) > 0 {
-		moveParams.SetClusterType(ec.String(util.Apm))
-	}
-
-	if len(req.AppsearchClusters) > 0 {
-		moveParams.SetClusterType(ec.String(util.Appsearch))
-	}
-
-	if len(req.EnterpriseSearchClusters) > 0 {
-		moveParams.SetClusterType(ec.String(util.EnterpriseSearch))
+	for _, kind := range resourcekind.All() {
+		if len(kind.Moves(req)) > 0 {
+			moveParams.SetClusterType(ec.String(kind.Name))
+		}
 	}
 
 	return moveParams, nil
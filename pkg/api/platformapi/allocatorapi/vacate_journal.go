@@ -0,0 +1,283 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package allocatorapi
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/elastic/cloud-sdk-go/pkg/sync/pool"
+	"github.com/elastic/cloud-sdk-go/pkg/util/slice"
+)
+
+// VacateState is the state of a resource move recorded in a VacateJournal.
+type VacateState string
+
+const (
+	// VacatePending is recorded before the move is requested.
+	VacatePending VacateState = "pending"
+
+	// VacateMoving is recorded once the move plan has been submitted.
+	VacateMoving VacateState = "moving"
+
+	// VacateCompleted is recorded once the move plan has finished, or once it
+	// has been submitted when the moves aren't tracked.
+	VacateCompleted VacateState = "completed"
+
+	// VacateFailed is recorded when either the move or its plan fails.
+	VacateFailed VacateState = "failed"
+)
+
+// journalTailChunk is the size of the chunks in which the end of the journal
+// file is read when looking for a truncated line.
+const journalTailChunk = 4096
+
+var errJournalCannotBeNil = errors.New("journal cannot be nil")
+
+// VacateJournalEntry is a single state transition of a resource move.
+type VacateJournalEntry struct {
+	AllocatorID string      `json:"allocator_id"`
+	ClusterID   string      `json:"cluster_id"`
+	Kind        string      `json:"kind"`
+	State       VacateState `json:"state"`
+	Error       string      `json:"error,omitempty"`
+	Time        time.Time   `json:"time"`
+}
+
+// VacateJournal persists the state transitions of the resource moves of a
+// vacate, so that it can be resumed with ResumeVacate. Implementations must
+// be safe for concurrent use.
+type VacateJournal interface {
+	// Record persists a state transition.
+	Record(entry VacateJournalEntry) error
+
+	// Entries returns the recorded entries in the order they were recorded.
+	Entries() ([]VacateJournalEntry, error)
+}
+
+// FileJournal is a VacateJournal which appends the entries to a local file as
+// newline delimited JSON.
+type FileJournal struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileJournal returns a FileJournal which persists to the specified path.
+// The file is created on the first recorded entry.
+func NewFileJournal(path string) *FileJournal {
+	return &FileJournal{path: path}
+}
+
+// Record appends the entry to the journal file.
+func (j *FileJournal) Record(entry VacateJournalEntry) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	f, err := os.OpenFile(j.path, os.O_APPEND|os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// When the process died while writing, the last line is truncated and
+	// is dropped so that it doesn't corrupt the new entry.
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if err := dropTruncatedLine(f, info.Size()); err != nil {
+		return err
+	}
+
+	if err := json.NewEncoder(f).Encode(entry); err != nil {
+		return err
+	}
+
+	return f.Sync()
+}
+
+// Entries reads all of the entries from the journal file. A journal file
+// which doesn't exist returns no entries. Since the process may have died
+// while writing, a truncated last line is ignored.
+func (j *FileJournal) Entries() ([]VacateJournalEntry, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	f, err := os.Open(j.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []VacateJournalEntry
+	var reader = bufio.NewReader(f)
+	for line := 1; ; line++ {
+		b, err := reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+
+		var last = err == io.EOF
+		if len(bytes.TrimSpace(b)) > 0 {
+			var entry VacateJournalEntry
+			if err := json.NewDecoder(bytes.NewReader(b)).Decode(&entry); err != nil {
+				// Only the last line can be truncated, since the lines are
+				// written in full before being terminated by a newline.
+				if last && errors.Is(err, io.ErrUnexpectedEOF) {
+					return entries, nil
+				}
+				return nil, fmt.Errorf("journal line %d: %w", line, err)
+			}
+			entries = append(entries, entry)
+		}
+
+		if last {
+			return entries, nil
+		}
+	}
+}
+
+// dropTruncatedLine truncates the file after its last newline, removing the
+// last line when it isn't terminated. Only the end of the file is read, in
+// chunks, until the last newline is found.
+func dropTruncatedLine(f *os.File, size int64) error {
+	var buf = make([]byte, journalTailChunk)
+	for end := size; end > 0; {
+		var start = end - journalTailChunk
+		if start < 0 {
+			start = 0
+		}
+
+		var chunk = buf[:end-start]
+		if _, err := f.ReadAt(chunk, start); err != nil {
+			return err
+		}
+
+		if end == size && chunk[len(chunk)-1] == '\n' {
+			return nil
+		}
+		if i := bytes.LastIndexByte(chunk, '\n'); i >= 0 {
+			return f.Truncate(start + int64(i) + 1)
+		}
+		end = start
+	}
+
+	return f.Truncate(0)
+}
+
+// ResumeVacate resumes a vacate from the state recorded in params.Journal.
+// Moves which are recorded as completed are skipped, moves which are in
+// flight are re-attached to by tracking their plan, and any remaining nodes
+// are moved off the allocators as Vacate would.
+func ResumeVacate(params *VacateParams) error {
+	if err := params.Validate(); err != nil {
+		return err
+	}
+
+	if params.Journal == nil {
+		return errJournalCannotBeNil
+	}
+
+	entries, err := params.Journal.Entries()
+	if err != nil {
+		return fmt.Errorf("vacate resume: %w", err)
+	}
+
+	return vacate(params, latestVacateStates(entries))
+}
+
+// latestVacateStates returns the last recorded entry of each of the moves.
+func latestVacateStates(entries []VacateJournalEntry) map[string]VacateJournalEntry {
+	var states = make(map[string]VacateJournalEntry, len(entries))
+	for _, e := range entries {
+		states[vacateJournalKey(e.AllocatorID, e.ClusterID, e.Kind)] = e
+	}
+	return states
+}
+
+// newResumedVacates returns the work items which re-attach to the in flight
+// moves of the allocators which are being vacated, the cluster and kind
+// filters are applied to the moves as Vacate would.
+func newResumedVacates(params *VacateParams, journaled map[string]VacateJournalEntry) []pool.Validator {
+	var keys = make([]string, 0, len(journaled))
+	for k, e := range journaled {
+		if e.State != VacateMoving || !slice.HasString(params.Allocators, e.AllocatorID) {
+			continue
+		}
+		if params.KindFilter != "" && e.Kind != params.KindFilter {
+			continue
+		}
+		if len(params.ClusterFilter) > 0 && !slice.HasString(params.ClusterFilter, e.ClusterID) {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var work = make([]pool.Validator, 0, len(keys))
+	for _, k := range keys {
+		var e = journaled[k]
+		var vacateParams = newVacateClusterParams(addAllocatorMovesToPoolParams{
+			ID: e.AllocatorID, VacateParams: params,
+		}, e.ClusterID, e.Kind)
+		vacateParams.Resume = true
+		work = append(work, vacateParams)
+	}
+
+	return work
+}
+
+// recordVacateState records the state of the move in the journal if one is
+// set, returning the move error when not nil or the journal error otherwise.
+func recordVacateState(params *VacateClusterParams, state VacateState, err error) error {
+	if params.Journal == nil {
+		return err
+	}
+
+	var entry = VacateJournalEntry{
+		AllocatorID: params.ID,
+		ClusterID:   params.ClusterID,
+		Kind:        params.Kind,
+		State:       state,
+		Time:        time.Now(),
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+
+	if jerr := params.Journal.Record(entry); jerr != nil && err == nil {
+		return wrapVacateError(jerr, params, "journal")
+	}
+
+	return err
+}
+
+func vacateJournalKey(allocatorID, clusterID, kind string) string {
+	return allocatorID + "/" + clusterID + "/" + kind
+}
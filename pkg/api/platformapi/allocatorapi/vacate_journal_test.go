@@ -0,0 +1,311 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package allocatorapi
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/mock"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/output"
+	sdkSync "github.com/elastic/cloud-sdk-go/pkg/sync"
+)
+
+type memoryJournal struct {
+	mu      sync.Mutex
+	entries []VacateJournalEntry
+}
+
+func (j *memoryJournal) Record(entry VacateJournalEntry) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	entry.Time = time.Time{}
+	j.entries = append(j.entries, entry)
+	return nil
+}
+
+func (j *memoryJournal) Entries() ([]VacateJournalEntry, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return append([]VacateJournalEntry(nil), j.entries...), nil
+}
+
+func TestFileJournal(t *testing.T) {
+	dir, err := ioutil.TempDir("", "vacate-journal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var path = filepath.Join(dir, "journal.json")
+	var journal = NewFileJournal(path)
+
+	entries, err := journal.Entries()
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+
+	var recorded = []VacateJournalEntry{
+		{
+			AllocatorID: "allocator-1",
+			ClusterID:   "3ee11eb40eda22cac0cce259625c6734",
+			Kind:        "elasticsearch",
+			State:       VacatePending,
+			Time:        time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			AllocatorID: "allocator-1",
+			ClusterID:   "3ee11eb40eda22cac0cce259625c6734",
+			Kind:        "elasticsearch",
+			State:       VacateFailed,
+			Error:       "some error",
+			Time:        time.Date(2020, 1, 1, 0, 1, 0, 0, time.UTC),
+		},
+	}
+	for _, e := range recorded {
+		assert.NoError(t, journal.Record(e))
+	}
+
+	entries, err = journal.Entries()
+	assert.NoError(t, err)
+	assert.Equal(t, recorded, entries)
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString(`{"allocator_id": "allocator-1", "clus`); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	entries, err = journal.Entries()
+	assert.NoError(t, err, "a truncated line is ignored")
+	assert.Equal(t, recorded, entries)
+
+	assert.NoError(t, journal.Record(recorded[0]))
+	entries, err = journal.Entries()
+	assert.NoError(t, err, "a truncated line doesn't corrupt the next entry")
+	assert.Equal(t, append(recorded, recorded[0]), entries)
+
+	f, err = os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString(`{"allocator_id": "` + strings.Repeat("a", 3*journalTailChunk)); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	assert.NoError(t, journal.Record(recorded[1]))
+	entries, err = journal.Entries()
+	assert.NoError(t, err, "a truncated line longer than the tail chunk is dropped")
+	assert.Equal(t, append(recorded, recorded...), entries)
+
+	if err := ioutil.WriteFile(path, []byte("not json\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	_, err = journal.Entries()
+	assert.EqualError(t, err, "journal line 1: invalid character 'o' in literal null (expecting 'u')")
+
+	if err := ioutil.WriteFile(path, []byte(
+		"{\"allocator_id\": \"allocator-1\"}\n{\"allocator_id\": \"allocator-1\",\n{\"allocator_id\": \"allocator-2\"}\n",
+	), 0600); err != nil {
+		t.Fatal(err)
+	}
+	_, err = journal.Entries()
+	assert.EqualError(t, err, "journal line 2: unexpected EOF", "only the last line can be truncated")
+}
+
+func Test_latestVacateStates(t *testing.T) {
+	var got = latestVacateStates([]VacateJournalEntry{
+		{AllocatorID: "allocator-1", ClusterID: "cluster-1", Kind: "elasticsearch", State: VacatePending},
+		{AllocatorID: "allocator-1", ClusterID: "cluster-1", Kind: "elasticsearch", State: VacateMoving},
+		{AllocatorID: "allocator-1", ClusterID: "cluster-1", Kind: "kibana", State: VacatePending},
+		{AllocatorID: "allocator-2", ClusterID: "cluster-1", Kind: "elasticsearch", State: VacateCompleted},
+	})
+	assert.Equal(t, map[string]VacateJournalEntry{
+		"allocator-1/cluster-1/elasticsearch": {AllocatorID: "allocator-1", ClusterID: "cluster-1", Kind: "elasticsearch", State: VacateMoving},
+		"allocator-1/cluster-1/kibana":        {AllocatorID: "allocator-1", ClusterID: "cluster-1", Kind: "kibana", State: VacatePending},
+		"allocator-2/cluster-1/elasticsearch": {AllocatorID: "allocator-2", ClusterID: "cluster-1", Kind: "elasticsearch", State: VacateCompleted},
+	}, got)
+}
+
+func TestResumeVacate(t *testing.T) {
+	const (
+		completedID = "63d765d37613423e97b1040257cf20c8"
+		movingID    = "3ee11eb40eda22cac0cce259625c6734"
+		newID       = "d7ad23ad6f064709bbae7ab87a7e1bc9"
+	)
+	var newJournal = func(entries ...VacateJournalEntry) *memoryJournal {
+		return &memoryJournal{entries: entries}
+	}
+	var inFlight = []VacateJournalEntry{
+		{AllocatorID: "allocator-1", ClusterID: completedID, Kind: "elasticsearch", State: VacateMoving},
+		{AllocatorID: "allocator-1", ClusterID: completedID, Kind: "elasticsearch", State: VacateCompleted},
+		{AllocatorID: "allocator-1", ClusterID: movingID, Kind: "elasticsearch", State: VacateMoving},
+	}
+
+	_, trackResponses := newElasticsearchVacateMove(t, "allocator-1", vacateCaseClusterConfig{
+		ID: movingID,
+		plan: []*models.ClusterPlanStepInfo{
+			newPlanStep("step1", "success"),
+			newPlanStep("plan-completed", "success"),
+		},
+	}, "us-east-1")
+
+	type args struct {
+		params  *VacateParams
+		journal *memoryJournal
+	}
+	tests := []struct {
+		name string
+		args args
+		want []VacateJournalEntry
+		err  error
+	}{
+		{
+			name: "fails when no journal is set",
+			args: args{params: &VacateParams{
+				API:         api.NewMock(),
+				Region:      "us-east-1",
+				Allocators:  []string{"allocator-1"},
+				Concurrency: 1,
+				Output:      output.NewDevice(sdkSync.NewBuffer()),
+			}},
+			err: errJournalCannotBeNil,
+		},
+		{
+			name: "re-attaches to the in flight moves and skips the completed ones",
+			args: args{
+				journal: newJournal(inFlight...),
+				params: &VacateParams{
+					API: api.NewMock(append([]mock.Response{
+						// The validate_only call lists the journaled moves.
+						mock.New202Response(newMulipleMoves(t, "allocator-1",
+							[]string{completedID, movingID}, nil, nil, nil, nil,
+						)),
+						// Allocator health discovery of the resumed move.
+						trackResponses[0],
+					}, trackResponses[3:]...)...),
+					Region:         "us-east-1",
+					Allocators:     []string{"allocator-1"},
+					Concurrency:    1,
+					Output:         output.NewDevice(sdkSync.NewBuffer()),
+					TrackFrequency: time.Nanosecond,
+					MaxPollRetries: 1,
+				},
+			},
+			want: append(inFlight, VacateJournalEntry{
+				AllocatorID: "allocator-1", ClusterID: movingID, Kind: "elasticsearch", State: VacateCompleted,
+			}),
+		},
+		{
+			name: "moves the resources which aren't journaled",
+			args: args{
+				journal: newJournal(inFlight[:2]...),
+				params: &VacateParams{
+					API: api.NewMock(
+						mock.New202Response(newMulipleMoves(t, "allocator-1",
+							[]string{completedID, newID}, nil, nil, nil, nil,
+						)),
+						mock.New200Response(newAllocator(t, "allocator-1", newID, "elasticsearch")),
+						mock.New202Response(newElasticsearchMove(t, newID, "allocator-1")),
+						mock.New202Response(newElasticsearchMove(t, newID, "allocator-1")),
+					),
+					Region:       "us-east-1",
+					Allocators:   []string{"allocator-1"},
+					Concurrency:  1,
+					Output:       output.NewDevice(sdkSync.NewBuffer()),
+					SkipTracking: true,
+				},
+			},
+			want: append(inFlight[:2:2],
+				VacateJournalEntry{AllocatorID: "allocator-1", ClusterID: newID, Kind: "elasticsearch", State: VacatePending},
+				VacateJournalEntry{AllocatorID: "allocator-1", ClusterID: newID, Kind: "elasticsearch", State: VacateMoving},
+				VacateJournalEntry{AllocatorID: "allocator-1", ClusterID: newID, Kind: "elasticsearch", State: VacateCompleted},
+			),
+		},
+		{
+			name: "doesn't re-attach to the in flight moves which are filtered out",
+			args: args{
+				journal: newJournal(inFlight...),
+				params: &VacateParams{
+					API: api.NewMock(
+						mock.New202Response(newMulipleMoves(t, "allocator-1",
+							[]string{completedID, movingID}, nil, nil, nil, nil,
+						)),
+					),
+					Region:        "us-east-1",
+					Allocators:    []string{"allocator-1"},
+					ClusterFilter: []string{completedID},
+					Concurrency:   1,
+					Output:        output.NewDevice(sdkSync.NewBuffer()),
+				},
+			},
+			want: inFlight,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.args.journal != nil {
+				tt.args.params.Journal = tt.args.journal
+			}
+
+			err := ResumeVacate(tt.args.params)
+			if tt.err != nil {
+				assert.Equal(t, tt.err, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			entries, _ := tt.args.journal.Entries()
+			assert.Equal(t, tt.want, entries)
+		})
+	}
+}
+
+func TestVacateClusterJournal(t *testing.T) {
+	var journal = new(memoryJournal)
+	err := VacateCluster(&VacateClusterParams{
+		API: api.NewMock(
+			mock.New200Response(newAllocator(t, "allocator-1", planClusterID, "elasticsearch")),
+			mock.New500Response(mock.NewStringBody(`{"error": "some error"}`)),
+		),
+		ID:           "allocator-1",
+		Region:       "us-east-1",
+		ClusterID:    planClusterID,
+		Kind:         "elasticsearch",
+		Output:       output.NewDevice(sdkSync.NewBuffer()),
+		SkipTracking: true,
+		Journal:      journal,
+	})
+	assert.Error(t, err)
+	assert.Len(t, journal.entries, 2)
+	assert.Equal(t, VacatePending, journal.entries[0].State)
+	assert.Equal(t, VacateFailed, journal.entries[1].State)
+	assert.Equal(t, err.Error(), journal.entries[1].Error)
+}
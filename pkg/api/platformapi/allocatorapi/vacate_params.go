@@ -27,8 +27,8 @@ import (
 	"github.com/elastic/cloud-sdk-go/pkg/models"
 	"github.com/elastic/cloud-sdk-go/pkg/multierror"
 	"github.com/elastic/cloud-sdk-go/pkg/output"
+	"github.com/elastic/cloud-sdk-go/pkg/resourcekind"
 	"github.com/elastic/cloud-sdk-go/pkg/sync/pool"
-	"github.com/elastic/cloud-sdk-go/pkg/util"
 	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
 	"github.com/elastic/cloud-sdk-go/pkg/util/slice"
 )
@@ -41,13 +41,7 @@ var (
 	errOutputDeviceCannotBeNil      = errors.New("output device cannot be nil")
 	errCannotOverrideAllocatorDown  = errors.New("cannot set the AllocatorDown when multiple allocators are specified")
 
-	allowedClusterKinds = []string{
-		util.Apm,
-		util.Appsearch,
-		util.Elasticsearch,
-		util.EnterpriseSearch,
-		util.Kibana,
-	}
+	allowedClusterKinds = resourcekind.Names()
 )
 
 // VacateParams used to vacate N allocators or clusters.
@@ -107,6 +101,10 @@ type VacateParams struct {
 
 	// Plan body overrides to place in all of the vacate clusters.
 	PlanOverrides
+
+	// Optional journal where the state transitions of each of the moves are
+	// recorded. Required by ResumeVacate.
+	Journal VacateJournal
 }
 
 // Validate validates the parameters
@@ -170,6 +168,16 @@ type VacateClusterParams struct {
 	OutputFormat   string
 	MaxPollRetries uint8
 	SkipTracking   bool
+
+	// Optional move request which is sent as is, when set, the move isn't
+	// computed through a validate_only call. Used to execute a VacatePlan.
+	MoveRequest *models.MoveClustersRequest
+
+	// Optional journal where the state transitions of the move are recorded.
+	Journal VacateJournal
+
+	// Resume skips the move and only tracks the in flight plan.
+	Resume bool
 }
 
 // Validate validates the parameters
@@ -213,6 +221,13 @@ type addAllocatorMovesToPoolParams struct {
 	Moves        *models.MoveClustersDetails
 	Pool         *pool.Pool
 	VacateParams *VacateParams
+	Journaled    map[string]VacateJournalEntry
+}
+
+// journaled returns true when the move is recorded as completed or in flight.
+func (params addAllocatorMovesToPoolParams) journaled(clusterID, kind string) bool {
+	entry, ok := params.Journaled[vacateJournalKey(params.ID, clusterID, kind)]
+	return ok && (entry.State == VacateMoving || entry.State == VacateCompleted)
 }
 
 // PlanOverrides is used to override any API value that is returned by default
@@ -0,0 +1,522 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package allocatorapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/client/platform_infrastructure"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/output"
+	"github.com/elastic/cloud-sdk-go/pkg/resourcekind"
+	"github.com/elastic/cloud-sdk-go/pkg/sync/pool"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+	"github.com/elastic/cloud-sdk-go/pkg/util/slice"
+)
+
+var errPlanCannotBeNil = errors.New("vacate plan cannot be nil")
+
+// VacatePlanParams is consumed by PlanVacate.
+type VacatePlanParams struct {
+	*api.API
+
+	Region string
+
+	// List of allocators that will be vacated.
+	Allocators []string
+
+	// List of allocators to be used as potential targets.
+	PreferredAllocators []string
+
+	// Can specify a list of cluster IDs that will be moved.
+	ClusterFilter []string
+
+	// If specified it will only move clusters that match the kind.
+	KindFilter string
+
+	// Optional value to override the autodiscovery of an allocator's health.
+	// This can only be used when a single allocator is specified.
+	AllocatorDown *bool
+
+	// Optional value to just do the bare minimum to move the instances.
+	MoveOnly *bool
+
+	// Plan body overrides to place in all of the vacate clusters.
+	PlanOverrides
+}
+
+// Validate ensures the parameters are usable.
+func (params VacatePlanParams) Validate() error {
+	var merr = multierror.NewPrefixed("invalid allocator vacate plan params")
+	if params.API == nil {
+		merr = merr.Append(errAPIMustNotBeNil)
+	}
+
+	if len(params.Allocators) == 0 {
+		merr = merr.Append(errMustSpecifyAtLeast1Allocator)
+	}
+
+	if len(params.ClusterFilter) > 0 && len(params.KindFilter) > 0 {
+		merr = merr.Append(errCannotFilterByIDAndKind)
+	}
+
+	if params.AllocatorDown != nil && len(params.Allocators) > 1 {
+		merr = merr.Append(errCannotOverrideAllocatorDown)
+	}
+
+	if err := ec.RequireRegionSet(params.Region); err != nil {
+		merr = merr.Append(err)
+	}
+
+	return merr.ErrorOrNil()
+}
+
+// VacatePlan contains the moves which a vacate would perform, it can be
+// serialised for review and is executed with ExecuteVacatePlan.
+type VacatePlan struct {
+	Region string `json:"region"`
+
+	// Moves contains one entry per resource which would be moved.
+	Moves []VacateMove `json:"moves"`
+
+	// Targets contains the capacity of the candidate allocators and the
+	// memory which the moves are projected to use on each of them.
+	Targets []VacateTarget `json:"targets,omitempty"`
+
+	// RequiredMemory is the sum of the memory of all the moved instances.
+	RequiredMemory int32 `json:"required_memory"`
+
+	// Failures contains the resources which could not be moved, as reported
+	// by CheckVacateFailures, and the moves which don't fit in any of their
+	// candidate allocators.
+	Failures []string `json:"failures,omitempty"`
+}
+
+// VacateMove is a single resource move of a VacatePlan.
+type VacateMove struct {
+	SourceAllocator string `json:"source_allocator"`
+	SourceZone      string `json:"source_zone,omitempty"`
+	ClusterID       string `json:"cluster_id"`
+	Kind            string `json:"kind"`
+
+	// Instances are the resource instances which live on the source.
+	Instances []string `json:"instances,omitempty"`
+
+	// Memory is the sum of the memory of the moved instances.
+	Memory int32 `json:"memory"`
+
+	// CandidateAllocators are the allocators where the instances may be
+	// placed, either the preferred allocators or the healthy allocators of the
+	// source zone which aren't being vacated.
+	CandidateAllocators []string `json:"candidate_allocators,omitempty"`
+
+	// Target is the candidate allocator with the most free memory at the time
+	// of planning, where the instances are projected to be placed. The
+	// constructor makes the final decision at execution time.
+	Target string `json:"target,omitempty"`
+
+	AllocatorDown *bool `json:"allocator_down,omitempty"`
+
+	// Request is the body which is sent to the move API, it contains the
+	// calculated plan with any PlanOverrides applied.
+	Request *models.MoveClustersRequest `json:"request"`
+}
+
+// VacateTarget is the capacity of an allocator which is used as a target.
+type VacateTarget struct {
+	AllocatorID string `json:"allocator_id"`
+	ZoneID      string `json:"zone_id"`
+	Total       int32  `json:"total"`
+	Used        int32  `json:"used"`
+
+	// Planned is the memory which the moves are projected to use.
+	Planned int32 `json:"planned"`
+}
+
+func (t VacateTarget) free() int32 { return t.Total - t.Used - t.Planned }
+
+// PlanVacate computes the moves that Vacate would perform without moving
+// anything, so that they can be reviewed and executed afterwards with
+// ExecuteVacatePlan. Each move is projected onto the candidate allocator with
+// the most free memory. When an allocator or the targets can't be obtained,
+// the partial plan is returned together with the errors.
+func PlanVacate(params VacatePlanParams) (*VacatePlan, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	var vacatePlan = VacatePlan{Region: params.Region, Moves: make([]VacateMove, 0)}
+	var merr = multierror.NewPrefixed("vacate plan error")
+	for _, id := range params.Allocators {
+		moves, failures, err := planAllocatorMoves(id, params)
+		if err != nil {
+			merr = merr.Append(err)
+			continue
+		}
+
+		vacatePlan.Moves = append(vacatePlan.Moves, moves...)
+		vacatePlan.Failures = append(vacatePlan.Failures, failures...)
+	}
+
+	for _, move := range vacatePlan.Moves {
+		vacatePlan.RequiredMemory += move.Memory
+	}
+
+	targets, err := planVacateTargets(params)
+	if err != nil {
+		merr = merr.Append(err)
+		return &vacatePlan, merr.ErrorOrNil()
+	}
+
+	vacatePlan.Targets = targets
+	placeVacateMoves(&vacatePlan, len(params.PreferredAllocators) > 0)
+
+	return &vacatePlan, merr.ErrorOrNil()
+}
+
+// planVacateTargets returns the preferred allocators or, when none are set,
+// the connected and healthy allocators which aren't being vacated or in
+// maintenance mode, sorted by ID.
+func planVacateTargets(params VacatePlanParams) ([]VacateTarget, error) {
+	res, err := List(ListParams{API: params.API, Region: params.Region, ShowAll: true})
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []VacateTarget
+	var found = make(map[string]bool)
+	for _, z := range res.Zones {
+		for _, alloc := range z.Allocators {
+			if alloc.AllocatorID == nil {
+				continue
+			}
+
+			var id = *alloc.AllocatorID
+			if len(params.PreferredAllocators) > 0 {
+				if !slice.HasString(params.PreferredAllocators, id) {
+					continue
+				}
+			} else if slice.HasString(params.Allocators, id) || !schedulable(alloc) {
+				continue
+			}
+
+			found[id] = true
+			targets = append(targets, newVacateTarget(alloc))
+		}
+	}
+
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].AllocatorID < targets[j].AllocatorID
+	})
+
+	var merr = multierror.NewPrefixed("vacate targets")
+	for _, id := range params.PreferredAllocators {
+		if !found[id] {
+			merr = merr.Append(fmt.Errorf("preferred allocator %s not found", id))
+		}
+	}
+
+	return targets, merr.ErrorOrNil()
+}
+
+func schedulable(alloc *models.AllocatorInfo) bool {
+	if alloc.Status == nil {
+		return false
+	}
+
+	var s = alloc.Status
+	return s.Connected != nil && *s.Connected &&
+		s.Healthy != nil && *s.Healthy &&
+		(s.MaintenanceMode == nil || !*s.MaintenanceMode)
+}
+
+// placeVacateMoves projects each of the moves onto the candidate allocator
+// with the most free memory, adding the move memory to the target's planned
+// usage. Without preferred allocators, the candidates of a move are the
+// targets in the same zone as its source allocator. The moves which don't fit
+// in any candidate are reported as failures.
+func placeVacateMoves(vacatePlan *VacatePlan, preferred bool) {
+	for i := range vacatePlan.Moves {
+		var move = &vacatePlan.Moves[i]
+		var candidates []string
+		var best = -1
+		for t, target := range vacatePlan.Targets {
+			if !preferred && target.ZoneID != move.SourceZone {
+				continue
+			}
+
+			candidates = append(candidates, target.AllocatorID)
+			if target.free() < move.Memory {
+				continue
+			}
+			if best < 0 || target.free() > vacatePlan.Targets[best].free() {
+				best = t
+			}
+		}
+
+		if !preferred {
+			move.CandidateAllocators = candidates
+		}
+
+		if best < 0 {
+			vacatePlan.Failures = append(vacatePlan.Failures, fmt.Sprintf(
+				"resource id [%s][%s] doesn't fit in any of the candidate allocators",
+				move.ClusterID, move.Kind,
+			))
+			continue
+		}
+
+		move.Target = vacatePlan.Targets[best].AllocatorID
+		vacatePlan.Targets[best].Planned += move.Memory
+	}
+}
+
+// planAllocatorMoves returns the moves and failures of a single allocator.
+func planAllocatorMoves(id string, params VacatePlanParams) ([]VacateMove, []string, error) {
+	var merr = multierror.NewPrefixed("allocator " + id)
+	alloc, err := Get(GetParams{API: params.API, ID: id, Region: params.Region})
+	if err != nil {
+		return nil, nil, merr.Append(err)
+	}
+
+	var allocatorDown = params.AllocatorDown
+	if allocatorDown == nil && alloc.Status != nil {
+		allocatorDown = ec.Bool(!*alloc.Status.Connected || !*alloc.Status.Healthy)
+	}
+
+	res, err := params.API.V1API.PlatformInfrastructure.MoveClusters(
+		platform_infrastructure.NewMoveClustersParams().
+			WithAllocatorID(id).
+			WithAllocatorDown(allocatorDown).
+			WithMoveOnly(params.MoveOnly).
+			WithContext(api.WithRegion(context.Background(), params.Region)).
+			WithValidateOnly(ec.Bool(true)),
+		params.AuthWriter,
+	)
+	if err != nil {
+		return nil, nil, merr.Append(api.UnwrapError(err))
+	}
+
+	var failures []string
+	if err := CheckVacateFailures(res.Payload.Failures, params.ClusterFilter); err != nil {
+		if ferr, ok := err.(*multierror.Prefixed); ok {
+			for _, e := range ferr.Errors {
+				failures = append(failures, e.Error())
+			}
+		}
+	}
+
+	if res.Payload.Moves == nil {
+		return nil, failures, nil
+	}
+
+	var req = ComputeVacateRequest(res.Payload.Moves,
+		params.ClusterFilter, params.PreferredAllocators, params.PlanOverrides,
+	)
+
+	var sourceZone string
+	if alloc.ZoneID != nil {
+		sourceZone = *alloc.ZoneID
+	}
+
+	var moves []VacateMove
+	var add = func(clusterID, kind string, r *models.MoveClustersRequest) {
+		if params.KindFilter != "" && params.KindFilter != kind {
+			return
+		}
+
+		var move = VacateMove{
+			SourceAllocator:     id,
+			SourceZone:          sourceZone,
+			ClusterID:           clusterID,
+			Kind:                kind,
+			CandidateAllocators: params.PreferredAllocators,
+			AllocatorDown:       allocatorDown,
+			Request:             r,
+		}
+
+		for _, instance := range alloc.Instances {
+			if instance.ClusterID == nil || *instance.ClusterID != clusterID {
+				continue
+			}
+
+			if instance.InstanceName != nil {
+				move.Instances = append(move.Instances, *instance.InstanceName)
+			}
+			if instance.NodeMemory != nil {
+				move.Memory += *instance.NodeMemory
+			}
+		}
+
+		moves = append(moves, move)
+	}
+
+	for _, kind := range resourcekind.All() {
+		for _, c := range kind.Moves(req) {
+			var ids = resourcekind.MoveClusterIDs(c)
+			if len(ids) == 0 {
+				continue
+			}
+
+			var r models.MoveClustersRequest
+			kind.SetMoves(&r, []interface{}{c})
+			add(ids[0], kind.Name, &r)
+		}
+	}
+
+	return moves, failures, nil
+}
+
+func newVacateTarget(alloc *models.AllocatorInfo) VacateTarget {
+	var target = VacateTarget{AllocatorID: *alloc.AllocatorID}
+	if alloc.ZoneID != nil {
+		target.ZoneID = *alloc.ZoneID
+	}
+
+	if alloc.Capacity != nil && alloc.Capacity.Memory != nil {
+		if alloc.Capacity.Memory.Total != nil {
+			target.Total = *alloc.Capacity.Memory.Total
+		}
+		if alloc.Capacity.Memory.Used != nil {
+			target.Used = *alloc.Capacity.Memory.Used
+		}
+	}
+
+	return target
+}
+
+// ExecuteVacatePlanParams is consumed by ExecuteVacatePlan.
+type ExecuteVacatePlanParams struct {
+	*api.API
+
+	// Plan to execute, as returned by PlanVacate.
+	Plan *VacatePlan
+
+	// Maximum number of concurrent cluster moves at any time.
+	Concurrency uint16
+
+	// Output device where the progress will be sent.
+	Output *output.Device
+
+	// OutputFormat to use
+	OutputFormat string
+
+	// Maximum number of errors to allow the plan status poller to tolerate.
+	MaxPollRetries uint8
+
+	// Poll frequency
+	TrackFrequency time.Duration
+
+	// Optional value to be set to the pool on construction.
+	PoolTimeout pool.Timeout
+
+	// SkipTracking skips waiting for the individual moves to complete.
+	SkipTracking bool
+
+	// Optional journal where the state transitions of each of the moves are
+	// recorded.
+	Journal VacateJournal
+}
+
+// Validate ensures the parameters are usable.
+func (params ExecuteVacatePlanParams) Validate() error {
+	var merr = multierror.NewPrefixed("invalid allocator vacate plan params")
+	if params.API == nil {
+		merr = merr.Append(errAPIMustNotBeNil)
+	}
+
+	if params.Plan == nil {
+		merr = merr.Append(errPlanCannotBeNil)
+	} else if err := ec.RequireRegionSet(params.Plan.Region); err != nil {
+		merr = merr.Append(err)
+	}
+
+	if params.Concurrency == 0 {
+		merr = merr.Append(errConcurrencyCannotBeZero)
+	}
+
+	if params.Output == nil {
+		merr = merr.Append(errOutputDeviceCannotBeNil)
+	}
+
+	return merr.ErrorOrNil()
+}
+
+// ExecuteVacatePlan performs the moves of a previously computed VacatePlan
+// exactly as they were planned, the moves aren't recomputed.
+func ExecuteVacatePlan(params ExecuteVacatePlanParams) error {
+	if err := params.Validate(); err != nil {
+		return err
+	}
+
+	var emptyTimeout pool.Timeout
+	if params.PoolTimeout == emptyTimeout {
+		params.PoolTimeout = pool.DefaultTimeout
+	}
+
+	p, err := pool.NewPool(pool.Params{
+		Size:    params.Concurrency,
+		Run:     VacateClusterInPool,
+		Timeout: params.PoolTimeout,
+		Writer:  params.Output,
+	})
+	if err != nil {
+		return err
+	}
+
+	var work = make([]pool.Validator, 0, len(params.Plan.Moves))
+	for _, move := range params.Plan.Moves {
+		work = append(work, newPlannedVacateClusterParams(params, move))
+	}
+
+	if err := p.Start(); err != nil {
+		return err
+	}
+
+	for len(work) > 0 {
+		work, _ = p.Add(work...)
+	}
+
+	return waitVacateCompletion(p, len(params.Plan.Moves) > 0)
+}
+
+func newPlannedVacateClusterParams(params ExecuteVacatePlanParams, move VacateMove) *VacateClusterParams {
+	return &VacateClusterParams{
+		API:                 params.API,
+		ID:                  move.SourceAllocator,
+		Kind:                move.Kind,
+		ClusterID:           move.ClusterID,
+		Region:              params.Plan.Region,
+		SkipTracking:        params.SkipTracking,
+		ClusterFilter:       []string{move.ClusterID},
+		PreferredAllocators: move.CandidateAllocators,
+		MaxPollRetries:      params.MaxPollRetries,
+		TrackFrequency:      params.TrackFrequency,
+		Output:              params.Output,
+		OutputFormat:        params.OutputFormat,
+		AllocatorDown:       move.AllocatorDown,
+		MoveRequest:         move.Request,
+		Journal:             params.Journal,
+	}
+}
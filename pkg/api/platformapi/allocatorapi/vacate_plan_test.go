@@ -0,0 +1,416 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package allocatorapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/mock"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/output"
+	sdkSync "github.com/elastic/cloud-sdk-go/pkg/sync"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+const planClusterID = "3ee11eb40eda22cac0cce259625c6734"
+
+func newPlanAllocator(id, zone string, healthy bool, instances ...*models.AllocatedInstanceStatus) mock.Response {
+	return mock.New200StructResponse(newPlanAllocatorInfo(id, zone, healthy, instances...))
+}
+
+func newPlanAllocatorInfo(id, zone string, healthy bool, instances ...*models.AllocatedInstanceStatus) *models.AllocatorInfo {
+	return &models.AllocatorInfo{
+		AllocatorID: ec.String(id),
+		ZoneID:      ec.String(zone),
+		Status: &models.AllocatorHealthStatus{
+			Connected: ec.Bool(true),
+			Healthy:   ec.Bool(healthy),
+		},
+		Capacity: &models.AllocatorCapacity{Memory: &models.AllocatorCapacityMemory{
+			Total: ec.Int32(65536),
+			Used:  ec.Int32(16384),
+		}},
+		Instances: instances,
+	}
+}
+
+func newPlanAllocatorList(allocators ...*models.AllocatorInfo) mock.Response {
+	var overview models.AllocatorOverview
+	var zones = make(map[string]*models.AllocatorZoneInfo)
+	for _, alloc := range allocators {
+		if zones[*alloc.ZoneID] == nil {
+			zones[*alloc.ZoneID] = &models.AllocatorZoneInfo{ZoneID: alloc.ZoneID}
+			overview.Zones = append(overview.Zones, zones[*alloc.ZoneID])
+		}
+		zones[*alloc.ZoneID].Allocators = append(zones[*alloc.ZoneID].Allocators, alloc)
+	}
+	return mock.New200StructResponse(overview)
+}
+
+func withPlanAllocatorUsage(alloc *models.AllocatorInfo, used int32) *models.AllocatorInfo {
+	alloc.Capacity.Memory.Used = ec.Int32(used)
+	return alloc
+}
+
+func newPlannedElasticsearchMove(preferred []string, skipSnapshot *bool) *models.MoveClustersRequest {
+	return &models.MoveClustersRequest{
+		ElasticsearchClusters: []*models.MoveElasticsearchClusterConfiguration{{
+			ClusterIds: []string{planClusterID},
+			PlanOverride: &models.TransientElasticsearchPlanConfiguration{
+				PlanConfiguration: &models.ElasticsearchPlanControlConfiguration{
+					MoveAllocators:      []*models.AllocatorMoveRequest{{From: ec.String("allocator-1")}},
+					PreferredAllocators: preferred,
+					SkipSnapshot:        skipSnapshot,
+				},
+			},
+		}},
+	}
+}
+
+func TestPlanVacate(t *testing.T) {
+	var instance = &models.AllocatedInstanceStatus{
+		ClusterID:    ec.String(planClusterID),
+		ClusterType:  ec.String("elasticsearch"),
+		InstanceName: ec.String("instance-0000000001"),
+		NodeMemory:   ec.Int32(4096),
+	}
+	type args struct {
+		params VacatePlanParams
+	}
+	tests := []struct {
+		name string
+		args args
+		want *VacatePlan
+		err  error
+	}{
+		{
+			name: "fails due to parameter validation",
+			args: args{params: VacatePlanParams{
+				Allocators:    []string{"allocator-1", "allocator-2"},
+				ClusterFilter: []string{planClusterID},
+				KindFilter:    "elasticsearch",
+				AllocatorDown: ec.Bool(true),
+			}},
+			err: multierror.NewPrefixed("invalid allocator vacate plan params",
+				errAPIMustNotBeNil,
+				errCannotFilterByIDAndKind,
+				errCannotOverrideAllocatorDown,
+				errors.New("region not specified and is required for this operation"),
+			),
+		},
+		{
+			name: "fails when the move validation returns an error",
+			args: args{params: VacatePlanParams{
+				API: api.NewMock(
+					newPlanAllocator("allocator-1", "zone-1", true, instance),
+					mock.New500Response(mock.NewStringBody(`{"error": "some error"}`)),
+					newPlanAllocatorList(
+						newPlanAllocatorInfo("allocator-1", "zone-1", true, instance),
+						newPlanAllocatorInfo("allocator-2", "zone-1", true),
+					),
+				),
+				Region:     "us-east-1",
+				Allocators: []string{"allocator-1"},
+			}},
+			want: &VacatePlan{
+				Region: "us-east-1",
+				Moves:  []VacateMove{},
+				Targets: []VacateTarget{{
+					AllocatorID: "allocator-2",
+					ZoneID:      "zone-1",
+					Total:       65536,
+					Used:        16384,
+				}},
+			},
+			err: multierror.NewPrefixed("vacate plan error",
+				multierror.NewPrefixed("allocator allocator-1",
+					errors.New(`{"error": "some error"}`),
+				),
+			),
+		},
+		{
+			name: "computes the moves of an unhealthy allocator to the preferred allocators",
+			args: args{params: VacatePlanParams{
+				API: api.NewMock(
+					newPlanAllocator("allocator-1", "zone-1", false, instance),
+					mock.New202ResponseAssertion(&mock.RequestAssertion{
+						Method: "POST",
+						Header: api.DefaultWriteMockHeaders,
+						Host:   api.DefaultMockHost,
+						Path:   "/api/v1/regions/us-east-1/platform/infrastructure/allocators/allocator-1/clusters/_move",
+						Query: url.Values{
+							"allocator_down": {"true"},
+							"force_update":   {"false"},
+							"validate_only":  {"true"},
+						},
+					}, newElasticsearchMove(t, planClusterID, "allocator-1")),
+					newPlanAllocatorList(
+						newPlanAllocatorInfo("allocator-1", "zone-1", false, instance),
+						newPlanAllocatorInfo("allocator-3", "zone-1", true),
+						newPlanAllocatorInfo("allocator-2", "zone-2", true),
+					),
+				),
+				Region:              "us-east-1",
+				Allocators:          []string{"allocator-1"},
+				PreferredAllocators: []string{"allocator-2"},
+				PlanOverrides:       PlanOverrides{SkipSnapshot: ec.Bool(true)},
+			}},
+			want: &VacatePlan{
+				Region: "us-east-1",
+				Moves: []VacateMove{{
+					SourceAllocator:     "allocator-1",
+					SourceZone:          "zone-1",
+					ClusterID:           planClusterID,
+					Kind:                "elasticsearch",
+					Instances:           []string{"instance-0000000001"},
+					Memory:              4096,
+					CandidateAllocators: []string{"allocator-2"},
+					Target:              "allocator-2",
+					AllocatorDown:       ec.Bool(true),
+					Request: newPlannedElasticsearchMove(
+						[]string{"allocator-2"}, ec.Bool(true),
+					),
+				}},
+				Targets: []VacateTarget{{
+					AllocatorID: "allocator-2",
+					ZoneID:      "zone-2",
+					Total:       65536,
+					Used:        16384,
+					Planned:     4096,
+				}},
+				RequiredMemory: 4096,
+			},
+		},
+		{
+			name: "computes the targets from the healthy allocators of the source zone",
+			args: args{params: VacatePlanParams{
+				API: api.NewMock(
+					newPlanAllocator("allocator-1", "zone-1", true, instance),
+					mock.New202Response(newElasticsearchMove(t, planClusterID, "allocator-1")),
+					newPlanAllocatorList(
+						newPlanAllocatorInfo("allocator-1", "zone-1", true, instance),
+						newPlanAllocatorInfo("allocator-2", "zone-1", true),
+						withPlanAllocatorUsage(newPlanAllocatorInfo("allocator-3", "zone-1", true), 8192),
+						newPlanAllocatorInfo("allocator-4", "zone-1", false),
+						newPlanAllocatorInfo("allocator-5", "zone-2", true),
+					),
+				),
+				Region:     "us-east-1",
+				Allocators: []string{"allocator-1"},
+			}},
+			want: &VacatePlan{
+				Region: "us-east-1",
+				Moves: []VacateMove{{
+					SourceAllocator:     "allocator-1",
+					SourceZone:          "zone-1",
+					ClusterID:           planClusterID,
+					Kind:                "elasticsearch",
+					Instances:           []string{"instance-0000000001"},
+					Memory:              4096,
+					CandidateAllocators: []string{"allocator-2", "allocator-3"},
+					Target:              "allocator-3",
+					AllocatorDown:       ec.Bool(false),
+					Request:             newPlannedElasticsearchMove(nil, nil),
+				}},
+				Targets: []VacateTarget{
+					{AllocatorID: "allocator-2", ZoneID: "zone-1", Total: 65536, Used: 16384},
+					{AllocatorID: "allocator-3", ZoneID: "zone-1", Total: 65536, Used: 8192, Planned: 4096},
+					{AllocatorID: "allocator-5", ZoneID: "zone-2", Total: 65536, Used: 16384},
+				},
+				RequiredMemory: 4096,
+			},
+		},
+		{
+			name: "reports the moves which don't fit in any of the candidates",
+			args: args{params: VacatePlanParams{
+				API: api.NewMock(
+					newPlanAllocator("allocator-1", "zone-1", true, instance),
+					mock.New202Response(newElasticsearchMove(t, planClusterID, "allocator-1")),
+					newPlanAllocatorList(
+						newPlanAllocatorInfo("allocator-1", "zone-1", true, instance),
+						withPlanAllocatorUsage(newPlanAllocatorInfo("allocator-2", "zone-1", true), 63488),
+					),
+				),
+				Region:     "us-east-1",
+				Allocators: []string{"allocator-1"},
+			}},
+			want: &VacatePlan{
+				Region: "us-east-1",
+				Moves: []VacateMove{{
+					SourceAllocator:     "allocator-1",
+					SourceZone:          "zone-1",
+					ClusterID:           planClusterID,
+					Kind:                "elasticsearch",
+					Instances:           []string{"instance-0000000001"},
+					Memory:              4096,
+					CandidateAllocators: []string{"allocator-2"},
+					AllocatorDown:       ec.Bool(false),
+					Request:             newPlannedElasticsearchMove(nil, nil),
+				}},
+				Targets: []VacateTarget{
+					{AllocatorID: "allocator-2", ZoneID: "zone-1", Total: 65536, Used: 63488},
+				},
+				RequiredMemory: 4096,
+				Failures: []string{
+					"resource id [3ee11eb40eda22cac0cce259625c6734][elasticsearch] doesn't fit in any of the candidate allocators",
+				},
+			},
+		},
+		{
+			name: "reports the move failures and skips the filtered kinds",
+			args: args{params: VacatePlanParams{
+				API: api.NewMock(
+					newPlanAllocator("allocator-1", "zone-1", true, instance),
+					mock.New202Response(newKibanaMoveFailure(t, planClusterID, "allocator-1")),
+					newPlanAllocatorList(newPlanAllocatorInfo("allocator-1", "zone-1", true, instance)),
+				),
+				Region:     "us-east-1",
+				Allocators: []string{"allocator-1"},
+				KindFilter: "elasticsearch",
+			}},
+			want: &VacatePlan{
+				Region: "us-east-1",
+				Moves:  []VacateMove{},
+				Failures: []string{
+					"resource id [3ee11eb40eda22cac0cce259625c6734][kibana] failed vacating, reason: code: some code, message: failed for reason",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PlanVacate(tt.args.params)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestVacatePlanJSON(t *testing.T) {
+	var vacatePlan = VacatePlan{
+		Region: "us-east-1",
+		Moves: []VacateMove{{
+			SourceAllocator: "allocator-1",
+			ClusterID:       planClusterID,
+			Kind:            "elasticsearch",
+			Memory:          4096,
+			Request:         newPlannedElasticsearchMove(nil, nil),
+		}},
+		RequiredMemory: 4096,
+	}
+
+	b, err := json.Marshal(vacatePlan)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got VacatePlan
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, vacatePlan, got)
+}
+
+func TestExecuteVacatePlan(t *testing.T) {
+	var vacatePlan = &VacatePlan{
+		Region: "us-east-1",
+		Moves: []VacateMove{{
+			SourceAllocator:     "allocator-1",
+			ClusterID:           planClusterID,
+			Kind:                "elasticsearch",
+			CandidateAllocators: []string{"allocator-2"},
+			AllocatorDown:       ec.Bool(false),
+			Request:             newPlannedElasticsearchMove([]string{"allocator-2"}, nil),
+		}},
+	}
+	type args struct {
+		params ExecuteVacatePlanParams
+	}
+	tests := []struct {
+		name string
+		args args
+		err  error
+	}{
+		{
+			name: "fails due to parameter validation",
+			err: multierror.NewPrefixed("invalid allocator vacate plan params",
+				errAPIMustNotBeNil,
+				errPlanCannotBeNil,
+				errConcurrencyCannotBeZero,
+				errOutputDeviceCannotBeNil,
+			),
+		},
+		{
+			name: "returns the move failures",
+			args: args{params: ExecuteVacatePlanParams{
+				API: api.NewMock(
+					mock.New202Response(newKibanaMoveFailure(t, planClusterID, "allocator-1")),
+				),
+				Plan:         vacatePlan,
+				Concurrency:  1,
+				Output:       output.NewDevice(sdkSync.NewBuffer()),
+				SkipTracking: true,
+			}},
+			err: multierror.NewPrefixed("vacate error",
+				errors.New("resource id [3ee11eb40eda22cac0cce259625c6734][kibana] failed vacating, reason: code: some code, message: failed for reason"),
+			),
+		},
+		{
+			name: "moves the planned resources without recomputing the moves",
+			args: args{params: ExecuteVacatePlanParams{
+				API: api.NewMock(
+					mock.New202ResponseAssertion(&mock.RequestAssertion{
+						Method: "POST",
+						Header: api.DefaultWriteMockHeaders,
+						Host:   api.DefaultMockHost,
+						Path:   "/api/v1/regions/us-east-1/platform/infrastructure/allocators/allocator-1/clusters/elasticsearch/_move",
+						Query: url.Values{
+							"allocator_down": {"false"},
+							"force_update":   {"false"},
+							"move_only":      {"true"},
+							"validate_only":  {"false"},
+						},
+						Body: mock.NewStructBody(newPlannedElasticsearchMove([]string{"allocator-2"}, nil)),
+					}, mock.NewStructBody(models.MoveClustersCommandResponse{})),
+				),
+				Plan:         vacatePlan,
+				Concurrency:  1,
+				Output:       output.NewDevice(sdkSync.NewBuffer()),
+				SkipTracking: true,
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ExecuteVacatePlan(tt.args.params)
+			if tt.err != nil {
+				assert.EqualError(t, err, tt.err.Error())
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,294 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package bundleapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+)
+
+// Version is the bundle layout version written by WriteBundle. Bundles with a
+// greater version can't be read.
+const Version = 1
+
+const manifestFile = "bundle.json"
+
+// The bundle can contain credentials, such as the snapshot repository keys, so
+// its directories and files are only accessible by their owner.
+const (
+	dirPerm  os.FileMode = 0700
+	filePerm os.FileMode = 0600
+)
+
+// Kind of platform resource contained in a bundle, it's also the name of the
+// bundle subdirectory which holds the resources.
+type Kind string
+
+const (
+	// KindInstanceConfiguration holds the instance configurations.
+	KindInstanceConfiguration Kind = "instance_configurations"
+
+	// KindSnapshotRepository holds the snapshot repositories.
+	KindSnapshotRepository Kind = "snapshot_repositories"
+
+	// KindDeploymentTemplate holds the deployment templates.
+	KindDeploymentTemplate Kind = "deployment_templates"
+
+	// KindProxyFilteredGroup holds the proxy filtered groups.
+	KindProxyFilteredGroup Kind = "proxy_filtered_groups"
+
+	// KindSecurityRealm holds the security realms.
+	KindSecurityRealm Kind = "security_realms"
+
+	// KindRole holds the blueprinter roles.
+	KindRole Kind = "roles"
+)
+
+// Kinds contains all of the bundle kinds in the order they're imported,
+// instance configurations are imported before the deployment templates which
+// reference them.
+var Kinds = []Kind{
+	KindInstanceConfiguration,
+	KindSnapshotRepository,
+	KindDeploymentTemplate,
+	KindProxyFilteredGroup,
+	KindSecurityRealm,
+	KindRole,
+}
+
+// Manifest describes a bundle.
+type Manifest struct {
+	Version int    `json:"version"`
+	Region  string `json:"region,omitempty"`
+}
+
+// Bundle contains the platform configuration of a region.
+type Bundle struct {
+	Manifest Manifest
+
+	InstanceConfigurations []*models.InstanceConfiguration
+	SnapshotRepositories   []*models.RepositoryConfig
+	DeploymentTemplates    []*models.DeploymentTemplateInfo
+	ProxyFilteredGroups    []*models.ProxiesFilteredGroup
+	SecurityRealms         []*SecurityRealm
+	Roles                  []*models.Role
+}
+
+// SecurityRealm is a security realm configuration, only the settings which
+// correspond to its type are set.
+type SecurityRealm struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+
+	ActiveDirectory *models.ActiveDirectorySettings `json:"active_directory,omitempty"`
+	Ldap            *models.LdapSettings            `json:"ldap,omitempty"`
+	Saml            *models.SamlSettings            `json:"saml,omitempty"`
+}
+
+// WriteBundle persists the bundle in a directory. The resources of a bundle
+// previously written in the directory are removed. Since the bundle may
+// contain credentials, the directories and files are written with owner only
+// permissions.
+func WriteBundle(dir string, bundle *Bundle) error {
+	if err := os.MkdirAll(dir, dirPerm); err != nil {
+		return err
+	}
+
+	var manifest = bundle.Manifest
+	manifest.Version = Version
+	if err := writeJSON(filepath.Join(dir, manifestFile), manifest); err != nil {
+		return err
+	}
+
+	var items = make(map[Kind]map[string]interface{}, len(Kinds))
+	for _, c := range bundle.InstanceConfigurations {
+		addItem(items, KindInstanceConfiguration, c.ID, c)
+	}
+	for _, r := range bundle.SnapshotRepositories {
+		addItem(items, KindSnapshotRepository, stringValue(r.RepositoryName), r)
+	}
+	for _, t := range bundle.DeploymentTemplates {
+		addItem(items, KindDeploymentTemplate, t.ID, t)
+	}
+	for _, g := range bundle.ProxyFilteredGroups {
+		addItem(items, KindProxyFilteredGroup, g.ID, g)
+	}
+	for _, r := range bundle.SecurityRealms {
+		addItem(items, KindSecurityRealm, r.ID, r)
+	}
+	for _, r := range bundle.Roles {
+		addItem(items, KindRole, stringValue(r.ID), r)
+	}
+
+	var merr = multierror.NewPrefixed("failed writing platform configuration bundle")
+	for _, kind := range Kinds {
+		if err := os.MkdirAll(filepath.Join(dir, string(kind)), dirPerm); err != nil {
+			merr = merr.Append(err)
+			continue
+		}
+		if err := removeItems(filepath.Join(dir, string(kind))); err != nil {
+			merr = merr.Append(err)
+			continue
+		}
+		for id, item := range items[kind] {
+			if err := writeJSON(filepath.Join(dir, string(kind), id+".json"), item); err != nil {
+				merr = merr.Append(err)
+			}
+		}
+	}
+
+	return merr.ErrorOrNil()
+}
+
+// ReadBundle reads a bundle which was persisted by WriteBundle. The resources
+// of each kind are sorted by ID.
+func ReadBundle(dir string) (*Bundle, error) {
+	var bundle Bundle
+	if err := readJSON(filepath.Join(dir, manifestFile), &bundle.Manifest); err != nil {
+		return nil, err
+	}
+
+	if v := bundle.Manifest.Version; v < 1 || v > Version {
+		return nil, fmt.Errorf("unsupported platform configuration bundle version %d", v)
+	}
+
+	var merr = multierror.NewPrefixed("failed reading platform configuration bundle")
+	for _, kind := range Kinds {
+		files, err := filepath.Glob(filepath.Join(dir, string(kind), "*.json"))
+		if err != nil {
+			merr = merr.Append(err)
+			continue
+		}
+		sort.Strings(files)
+
+		for _, f := range files {
+			if err := readItem(&bundle, kind, f); err != nil {
+				merr = merr.Append(err)
+			}
+		}
+	}
+
+	return &bundle, merr.ErrorOrNil()
+}
+
+func readItem(bundle *Bundle, kind Kind, file string) error {
+	switch kind {
+	case KindInstanceConfiguration:
+		var c models.InstanceConfiguration
+		if err := readJSON(file, &c); err != nil {
+			return err
+		}
+		bundle.InstanceConfigurations = append(bundle.InstanceConfigurations, &c)
+	case KindSnapshotRepository:
+		var r models.RepositoryConfig
+		if err := readJSON(file, &r); err != nil {
+			return err
+		}
+		bundle.SnapshotRepositories = append(bundle.SnapshotRepositories, &r)
+	case KindDeploymentTemplate:
+		var t models.DeploymentTemplateInfo
+		if err := readJSON(file, &t); err != nil {
+			return err
+		}
+		bundle.DeploymentTemplates = append(bundle.DeploymentTemplates, &t)
+	case KindProxyFilteredGroup:
+		var g models.ProxiesFilteredGroup
+		if err := readJSON(file, &g); err != nil {
+			return err
+		}
+		bundle.ProxyFilteredGroups = append(bundle.ProxyFilteredGroups, &g)
+	case KindSecurityRealm:
+		var r SecurityRealm
+		if err := readJSON(file, &r); err != nil {
+			return err
+		}
+		bundle.SecurityRealms = append(bundle.SecurityRealms, &r)
+	case KindRole:
+		var r models.Role
+		if err := readJSON(file, &r); err != nil {
+			return err
+		}
+		bundle.Roles = append(bundle.Roles, &r)
+	}
+	return nil
+}
+
+// removeItems removes the resource files of a bundle kind directory.
+func removeItems(dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		if err := os.Remove(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func addItem(items map[Kind]map[string]interface{}, kind Kind, id string, item interface{}) {
+	if items[kind] == nil {
+		items[kind] = make(map[string]interface{})
+	}
+	items[kind][id] = item
+}
+
+func writeJSON(path string, v interface{}) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, filePerm)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// Files which already existed keep their permissions when opened.
+	if err := f.Chmod(filePerm); err != nil {
+		return err
+	}
+
+	var enc = json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func readJSON(path string, v interface{}) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := json.NewDecoder(f).Decode(v); err != nil {
+		return fmt.Errorf("%s: %s", path, err)
+	}
+	return nil
+}
+
+func stringValue(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
@@ -0,0 +1,163 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package bundleapi
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+func newTestBundle() *Bundle {
+	return &Bundle{
+		Manifest: Manifest{Version: Version, Region: "us-east-1"},
+		InstanceConfigurations: []*models.InstanceConfiguration{{
+			ID:           "data.default",
+			Name:         ec.String("data.default"),
+			InstanceType: ec.String("elasticsearch"),
+			DiscreteSizes: &models.DiscreteSizes{
+				DefaultSize: ec.Int32(1024),
+				Resource:    ec.String("memory"),
+				Sizes:       []int32{1024, 2048},
+			},
+		}},
+		SnapshotRepositories: []*models.RepositoryConfig{{
+			RepositoryName: ec.String("backups"),
+			Config:         map[string]interface{}{"bucket": "my-bucket", "region": "us-east-1"},
+		}},
+		DeploymentTemplates: []*models.DeploymentTemplateInfo{{
+			ID:          "default",
+			Name:        ec.String("Default"),
+			Description: "default template",
+			DeploymentTemplate: &models.DeploymentCreateRequest{
+				Resources: &models.DeploymentCreateResources{
+					Elasticsearch: []*models.ElasticsearchPayload{{
+						RefID:  ec.String("main-elasticsearch"),
+						Region: ec.String("ece-region"),
+						Plan: &models.ElasticsearchClusterPlan{
+							Elasticsearch: &models.ElasticsearchConfiguration{},
+							ClusterTopology: []*models.ElasticsearchClusterTopologyElement{{
+								InstanceConfigurationID: "data.default",
+							}},
+						},
+					}},
+				},
+			},
+		}},
+		ProxyFilteredGroups: []*models.ProxiesFilteredGroup{{
+			ID:                   "zone-a",
+			ExpectedProxiesCount: ec.Int32(2),
+			Filters: []*models.ProxiesFilter{
+				{Key: ec.String("proxyZone"), Value: ec.String("us-east-1a")},
+			},
+		}},
+		SecurityRealms: []*SecurityRealm{{
+			ID:   "ldap1",
+			Type: "ldap",
+			Ldap: &models.LdapSettings{
+				ID:   ec.String("ldap1"),
+				Name: ec.String("corporate"),
+			},
+		}},
+		Roles: []*models.Role{{
+			ID:          ec.String("proxy"),
+			AutoBlessed: ec.Bool(false),
+			Containers:  []*models.ContainersEntry{},
+		}},
+	}
+}
+
+func TestWriteReadBundle(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bundle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var bundle = newTestBundle()
+	if err := WriteBundle(dir, bundle); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, f := range []string{
+		"bundle.json",
+		"instance_configurations/data.default.json",
+		"snapshot_repositories/backups.json",
+		"deployment_templates/default.json",
+		"proxy_filtered_groups/zone-a.json",
+		"security_realms/ldap1.json",
+		"roles/proxy.json",
+	} {
+		info, err := os.Stat(filepath.Join(dir, f))
+		if assert.NoError(t, err, f) {
+			assert.Equal(t, os.FileMode(0600), info.Mode().Perm(), f)
+		}
+	}
+
+	for _, d := range []string{"roles", "snapshot_repositories"} {
+		info, err := os.Stat(filepath.Join(dir, d))
+		if assert.NoError(t, err, d) {
+			assert.Equal(t, os.FileMode(0700), info.Mode().Perm(), d)
+		}
+	}
+
+	got, err := ReadBundle(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, bundle, got)
+}
+
+func TestReadBundle(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bundle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	got, err := ReadBundle(dir)
+	assert.Nil(t, got)
+	assert.Error(t, err)
+
+	var manifest = filepath.Join(dir, manifestFile)
+	if err := ioutil.WriteFile(manifest, []byte(`{"version": 2}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	got, err = ReadBundle(dir)
+	assert.Nil(t, got)
+	assert.EqualError(t, err, "unsupported platform configuration bundle version 2")
+
+	if err := ioutil.WriteFile(manifest, []byte(`{"version": 1}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, string(KindRole)), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	var role = filepath.Join(dir, string(KindRole), "broken.json")
+	if err := ioutil.WriteFile(role, []byte(`{"id":`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	_, err = ReadBundle(dir)
+	assert.EqualError(t, err, "failed reading platform configuration bundle: 1 error occurred:\n\t* "+
+		role+": unexpected EOF\n\n",
+	)
+}
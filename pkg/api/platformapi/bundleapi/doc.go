@@ -0,0 +1,32 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package bundleapi exports the platform configuration of a region into a
+// versioned directory layout, a bundle, and imports such bundles into another
+// region, which allows environments to be promoted or kept in sync.
+//
+// A bundle has the following layout, where each resource is stored in a JSON
+// file named after its ID:
+//
+//	bundle.json
+//	instance_configurations/<id>.json
+//	snapshot_repositories/<name>.json
+//	deployment_templates/<id>.json
+//	proxy_filtered_groups/<id>.json
+//	security_realms/<id>.json
+//	roles/<id>.json
+package bundleapi
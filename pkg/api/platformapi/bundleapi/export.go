@@ -0,0 +1,201 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package bundleapi
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/api/platformapi/configurationtemplateapi"
+	"github.com/elastic/cloud-sdk-go/pkg/api/platformapi/instanceconfigapi"
+	"github.com/elastic/cloud-sdk-go/pkg/api/platformapi/proxyapi/filteredgroupapi"
+	"github.com/elastic/cloud-sdk-go/pkg/api/platformapi/roleapi"
+	"github.com/elastic/cloud-sdk-go/pkg/api/platformapi/snaprepoapi"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+// templateFormat is the deployment template format stored in bundles.
+const templateFormat = "deployment"
+
+var errDirectoryCannotBeEmpty = errors.New("directory not specified and is required for the operation")
+
+// ExportParams is consumed by Export.
+type ExportParams struct {
+	*api.API
+	Region    string
+	Directory string
+}
+
+// Validate ensures the parameters are usable.
+func (params ExportParams) Validate() error {
+	var merr = multierror.NewPrefixed("invalid platform configuration export params")
+	if params.API == nil {
+		merr = merr.Append(apierror.ErrMissingAPI)
+	}
+
+	if params.Directory == "" {
+		merr = merr.Append(errDirectoryCannotBeEmpty)
+	}
+
+	if err := ec.RequireRegionSet(params.Region); err != nil {
+		merr = merr.Append(err)
+	}
+
+	return merr.ErrorOrNil()
+}
+
+// Export obtains the platform configuration of a region and writes it as a
+// bundle in the directory, replacing any previously exported bundle. System
+// owned instance configurations and deployment templates aren't exported.
+// The bundle contains the snapshot repository configurations as they are,
+// including their credentials, so its files are only readable by the owner.
+func Export(params ExportParams) (*Bundle, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	bundle, err := collect(params.API, params.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := WriteBundle(params.Directory, bundle); err != nil {
+		return nil, err
+	}
+
+	return bundle, nil
+}
+
+// collect obtains the platform configuration of a region.
+func collect(a *api.API, region string) (*Bundle, error) {
+	var bundle = Bundle{Manifest: Manifest{Version: Version, Region: region}}
+
+	configs, err := instanceconfigapi.List(instanceconfigapi.ListParams{
+		API: a, Region: region,
+	})
+	if err != nil {
+		return nil, err
+	}
+	bundle.InstanceConfigurations = configs
+
+	repos, err := snaprepoapi.List(snaprepoapi.ListParams{API: a, Region: region})
+	if err != nil {
+		return nil, err
+	}
+	bundle.SnapshotRepositories = repos.Configs
+
+	templates, err := configurationtemplateapi.ListTemplates(
+		configurationtemplateapi.ListTemplateParams{
+			API: a, Region: region, Format: templateFormat,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range templates {
+		// Fields populated by the API which can't be set.
+		t.Source, t.InstanceConfigurations = nil, nil
+	}
+	bundle.DeploymentTemplates = templates
+
+	groups, err := filteredgroupapi.List(filteredgroupapi.ListParams{
+		API: a, Region: region,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range groups {
+		if g != nil && g.Group != nil {
+			bundle.ProxyFilteredGroups = append(bundle.ProxyFilteredGroups, g.Group)
+		}
+	}
+
+	realms, err := getRealms(a, region)
+	if err != nil {
+		return nil, err
+	}
+	bundle.SecurityRealms = realms
+
+	roles, err := roleapi.List(roleapi.ListParams{API: a, Region: region})
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range roles.Values {
+		if role := roleOf(r); role != nil {
+			bundle.Roles = append(bundle.Roles, role)
+		}
+	}
+
+	dropSystemOwned(&bundle)
+	sortBundle(&bundle)
+	return &bundle, nil
+}
+
+// dropSystemOwned removes the system owned instance configurations and
+// deployment templates, which are managed by the platform.
+func dropSystemOwned(b *Bundle) {
+	var configs = b.InstanceConfigurations[:0]
+	for _, c := range b.InstanceConfigurations {
+		if c.SystemOwned == nil || !*c.SystemOwned {
+			configs = append(configs, c)
+		}
+	}
+	b.InstanceConfigurations = configs
+
+	var templates = b.DeploymentTemplates[:0]
+	for _, t := range b.DeploymentTemplates {
+		if t.SystemOwned == nil || !*t.SystemOwned {
+			templates = append(templates, t)
+		}
+	}
+	b.DeploymentTemplates = templates
+}
+
+func sortBundle(b *Bundle) {
+	sort.SliceStable(b.InstanceConfigurations, func(i, j int) bool {
+		return b.InstanceConfigurations[i].ID < b.InstanceConfigurations[j].ID
+	})
+	sort.SliceStable(b.SnapshotRepositories, func(i, j int) bool {
+		return stringValue(b.SnapshotRepositories[i].RepositoryName) <
+			stringValue(b.SnapshotRepositories[j].RepositoryName)
+	})
+	sort.SliceStable(b.DeploymentTemplates, func(i, j int) bool {
+		return b.DeploymentTemplates[i].ID < b.DeploymentTemplates[j].ID
+	})
+	sort.SliceStable(b.ProxyFilteredGroups, func(i, j int) bool {
+		return b.ProxyFilteredGroups[i].ID < b.ProxyFilteredGroups[j].ID
+	})
+	sort.SliceStable(b.SecurityRealms, func(i, j int) bool {
+		return b.SecurityRealms[i].ID < b.SecurityRealms[j].ID
+	})
+	sort.SliceStable(b.Roles, func(i, j int) bool {
+		return stringValue(b.Roles[i].ID) < stringValue(b.Roles[j].ID)
+	})
+}
+
+// roleOf returns the role of an aggregate.
+func roleOf(r *models.RoleAggregate) *models.Role {
+	if r == nil || r.Role == nil {
+		return nil
+	}
+	return r.Role.Value
+}
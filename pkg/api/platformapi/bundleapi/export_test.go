@@ -0,0 +1,197 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package bundleapi
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/api/mock"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+const regionPath = "/api/v1/regions/us-east-1"
+
+func readAssertion(path string, query url.Values) *mock.RequestAssertion {
+	return &mock.RequestAssertion{
+		Header: api.DefaultReadMockHeaders,
+		Method: "GET",
+		Host:   api.DefaultMockHost,
+		Path:   regionPath + path,
+		Query:  query,
+	}
+}
+
+// collectResponses returns the responses which make collect obtain the bundle.
+func collectResponses(b *Bundle) []mock.Response {
+	var templates = make([]*models.DeploymentTemplateInfo, 0, len(b.DeploymentTemplates))
+	for _, t := range b.DeploymentTemplates {
+		var template = *t
+		template.Source = &models.ChangeSourceInfo{Action: ec.String("create")}
+		templates = append(templates, &template)
+	}
+
+	var groups []*models.ProxiesFilteredGroupHealth
+	for _, g := range b.ProxyFilteredGroups {
+		groups = append(groups, &models.ProxiesFilteredGroupHealth{
+			Group: g, Status: ec.String("Green"), ObservedProxiesCount: ec.Int32(2),
+		})
+	}
+
+	var realms = []*models.SecurityRealmInfo{
+		{ID: ec.String("native"), Type: ec.String("native"), Name: ec.String("native")},
+	}
+	var realmResponses []mock.Response
+	for _, r := range b.SecurityRealms {
+		realms = append(realms, &models.SecurityRealmInfo{
+			ID: ec.String(r.ID), Type: ec.String(r.Type), Name: r.Ldap.Name,
+		})
+		realmResponses = append(realmResponses, mock.New200ResponseAssertion(
+			readAssertion("/platform/configuration/security/realms/ldap/"+r.ID, nil),
+			mock.NewStructBody(r.Ldap),
+		))
+	}
+
+	var roles []*models.RoleAggregate
+	for _, r := range b.Roles {
+		roles = append(roles, &models.RoleAggregate{
+			ID: r.ID, Role: &models.RoleWithMeta{Value: r, Meta: &models.Metadata{}},
+		})
+	}
+
+	var responses = []mock.Response{
+		mock.New200ResponseAssertion(
+			readAssertion("/platform/configuration/instances", nil),
+			mock.NewStructBody(b.InstanceConfigurations),
+		),
+		mock.New200ResponseAssertion(
+			readAssertion("/platform/configuration/snapshots/repositories", nil),
+			mock.NewStructBody(models.RepositoryConfigs{Configs: b.SnapshotRepositories}),
+		),
+		mock.New200ResponseAssertion(
+			readAssertion("/platform/configuration/templates/deployments", url.Values{
+				"format":                       {"deployment"},
+				"show_hidden":                  {"false"},
+				"show_instance_configurations": {"false"},
+			}),
+			mock.NewStructBody(templates),
+		),
+		mock.New200ResponseAssertion(
+			readAssertion("/platform/infrastructure/proxies/health", nil),
+			mock.NewStructBody(models.ProxiesHealth{FilteredGroups: groups}),
+		),
+		mock.New200ResponseAssertion(
+			readAssertion("/platform/configuration/security/realms", nil),
+			mock.NewStructBody(models.SecurityRealmInfoList{Realms: realms}),
+		),
+	}
+	responses = append(responses, realmResponses...)
+	return append(responses, mock.New200ResponseAssertion(
+		readAssertion("/platform/infrastructure/blueprinter/roles", nil),
+		mock.NewStructBody(models.RoleAggregates{Values: roles}),
+	))
+}
+
+func TestExport(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bundle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Resources of a previous export which no longer exist are removed.
+	if err := WriteBundle(dir, &Bundle{
+		InstanceConfigurations: []*models.InstanceConfiguration{{ID: "removed"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var withSystemOwned = newTestBundle()
+	withSystemOwned.InstanceConfigurations = append(withSystemOwned.InstanceConfigurations,
+		&models.InstanceConfiguration{ID: "kibana", SystemOwned: ec.Bool(true)},
+	)
+	withSystemOwned.DeploymentTemplates = append(withSystemOwned.DeploymentTemplates,
+		&models.DeploymentTemplateInfo{ID: "system", SystemOwned: ec.Bool(true)},
+	)
+
+	tests := []struct {
+		name   string
+		params ExportParams
+		want   *Bundle
+		err    error
+	}{
+		{
+			name: "fails due to parameter validation",
+			err: multierror.NewPrefixed("invalid platform configuration export params",
+				apierror.ErrMissingAPI,
+				errDirectoryCannotBeEmpty,
+				errors.New("region not specified and is required for this operation"),
+			),
+		},
+		{
+			name: "fails obtaining the instance configurations",
+			params: ExportParams{
+				Region:    "us-east-1",
+				Directory: dir,
+				API:       api.NewMock(mock.New500Response(mock.NewStringBody(`{"error": "some error"}`))),
+			},
+			err: errors.New(`{"error": "some error"}`),
+		},
+		{
+			name: "exports the platform configuration",
+			params: ExportParams{
+				Region:    "us-east-1",
+				Directory: dir,
+				API:       api.NewMock(collectResponses(newTestBundle())...),
+			},
+			want: newTestBundle(),
+		},
+		{
+			name: "doesn't export the system owned resources",
+			params: ExportParams{
+				Region:    "us-east-1",
+				Directory: dir,
+				API:       api.NewMock(collectResponses(withSystemOwned)...),
+			},
+			want: newTestBundle(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Export(tt.params)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.want, got)
+			if tt.want == nil {
+				return
+			}
+
+			read, err := ReadBundle(dir)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, read)
+		})
+	}
+}
@@ -0,0 +1,488 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package bundleapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/api/platformapi/configurationtemplateapi"
+	"github.com/elastic/cloud-sdk-go/pkg/api/platformapi/instanceconfigapi"
+	"github.com/elastic/cloud-sdk-go/pkg/api/platformapi/proxyapi/filteredgroupapi"
+	"github.com/elastic/cloud-sdk-go/pkg/api/platformapi/roleapi"
+	"github.com/elastic/cloud-sdk-go/pkg/api/platformapi/snaprepoapi"
+	"github.com/elastic/cloud-sdk-go/pkg/client/platform_infrastructure"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+// defaultRepositoryType is used when a snapshot repository config doesn't
+// specify its type.
+const defaultRepositoryType = "s3"
+
+// Action performed on a bundle resource when imported.
+type Action string
+
+const (
+	// ActionCreate is performed when the resource doesn't exist.
+	ActionCreate Action = "create"
+
+	// ActionUpdate is performed when the resource differs.
+	ActionUpdate Action = "update"
+
+	// ActionNone is reported when the resource is unchanged.
+	ActionNone Action = "unchanged"
+
+	// ActionSkip is reported when the resource can't be applied, the reason
+	// is set in the change.
+	ActionSkip Action = "skipped"
+)
+
+// Change contains the difference between a bundle resource and the resource
+// in the target region.
+type Change struct {
+	Kind   Kind
+	ID     string
+	Action Action
+
+	// Diff between the target region resource and the bundle resource, only
+	// set on updates.
+	Diff string
+
+	// Reason why the change is skipped.
+	Reason string
+
+	// Applied is true once the change has been successfully applied.
+	Applied bool
+
+	apply func(create bool) error
+}
+
+// ImportReport contains the changes computed by Import, in the order they're
+// applied.
+type ImportReport struct {
+	Changes []Change
+	DryRun  bool
+}
+
+// Changed returns the changes which create or update a resource.
+func (r ImportReport) Changed() []Change {
+	var changes []Change
+	for _, c := range r.Changes {
+		if c.Action == ActionCreate || c.Action == ActionUpdate {
+			changes = append(changes, c)
+		}
+	}
+	return changes
+}
+
+// ImportParams is consumed by Import.
+type ImportParams struct {
+	*api.API
+	Region    string
+	Directory string
+
+	// IDMapping remaps the bundle resource IDs to the IDs in the target
+	// region, references to remapped instance configurations in the
+	// deployment templates are remapped as well.
+	IDMapping map[string]string
+
+	// RealmPasswords contains the bind passwords of the LDAP and Active
+	// Directory security realms keyed by realm ID. The API doesn't return
+	// them, so realms with a bind DN are skipped unless their password is set.
+	RealmPasswords map[string]string
+
+	// DryRun computes the changes without applying them.
+	DryRun bool
+}
+
+// Validate ensures the parameters are usable.
+func (params ImportParams) Validate() error {
+	var merr = multierror.NewPrefixed("invalid platform configuration import params")
+	if params.API == nil {
+		merr = merr.Append(apierror.ErrMissingAPI)
+	}
+
+	if params.Directory == "" {
+		merr = merr.Append(errDirectoryCannotBeEmpty)
+	}
+
+	for from, to := range params.IDMapping {
+		if from == "" || to == "" {
+			merr = merr.Append(fmt.Errorf(`invalid id mapping "%s" to "%s"`, from, to))
+		}
+	}
+
+	if err := ec.RequireRegionSet(params.Region); err != nil {
+		merr = merr.Append(err)
+	}
+
+	return merr.ErrorOrNil()
+}
+
+// Import reads a bundle from the directory and compares its resources with
+// the platform configuration of the target region. Resources which don't
+// exist are created and the ones which differ are updated, following the
+// order of Kinds, so the instance configurations exist before the deployment
+// templates which reference them. When a resource fails to be applied, the
+// resources of the subsequent kinds aren't applied. Security realm passwords
+// aren't returned by the API and thus aren't part of the exported bundles,
+// they need to be set in RealmPasswords. System owned instance configurations
+// and deployment templates are never imported.
+func Import(params ImportParams) (*ImportReport, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	bundle, err := ReadBundle(params.Directory)
+	if err != nil {
+		return nil, err
+	}
+
+	dropSystemOwned(bundle)
+	if err := remap(bundle, params.IDMapping); err != nil {
+		return nil, err
+	}
+
+	target, err := collect(params.API, params.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := computeChanges(params, bundle, target)
+	if err != nil {
+		return nil, err
+	}
+
+	var report = ImportReport{Changes: changes, DryRun: params.DryRun}
+	if params.DryRun {
+		return &report, nil
+	}
+
+	var merr = multierror.NewPrefixed("failed importing platform configuration")
+	for i := range report.Changes {
+		var change = &report.Changes[i]
+		if i > 0 && change.Kind != report.Changes[i-1].Kind && merr.ErrorOrNil() != nil {
+			break
+		}
+
+		if change.Action == ActionNone || change.Action == ActionSkip {
+			continue
+		}
+
+		if err := change.apply(change.Action == ActionCreate); err != nil {
+			merr = merr.Append(fmt.Errorf("%s %s: %w", change.Kind, change.ID, err))
+			continue
+		}
+		change.Applied = true
+	}
+
+	return &report, merr.ErrorOrNil()
+}
+
+func computeChanges(params ImportParams, bundle, target *Bundle) ([]Change, error) {
+	var changes []Change
+	var add = func(kind Kind, id string, exists bool, current, desired interface{}, apply func(bool) error) error {
+		var change = Change{Kind: kind, ID: id, Action: ActionCreate, apply: apply}
+		if exists {
+			equal, diff, err := ec.CompareStructs(current, desired)
+			if err != nil {
+				return err
+			}
+			change.Action = ActionNone
+			if !equal {
+				change.Action, change.Diff = ActionUpdate, diff
+			}
+		}
+		changes = append(changes, change)
+		return nil
+	}
+
+	var currentConfigs = make(map[string]*models.InstanceConfiguration)
+	for _, c := range target.InstanceConfigurations {
+		currentConfigs[c.ID] = c
+	}
+	for _, c := range bundle.InstanceConfigurations {
+		var config = c
+		current, ok := currentConfigs[c.ID]
+		if err := add(KindInstanceConfiguration, c.ID, ok, current, c, func(bool) error {
+			return instanceconfigapi.Update(instanceconfigapi.UpdateParams{
+				API: params.API, Region: params.Region, ID: config.ID, Config: config,
+			})
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	var currentRepos = make(map[string]*models.RepositoryConfig)
+	for _, r := range target.SnapshotRepositories {
+		currentRepos[stringValue(r.RepositoryName)] = r
+	}
+	for _, r := range bundle.SnapshotRepositories {
+		var repo, name = r, stringValue(r.RepositoryName)
+		current, ok := currentRepos[name]
+		if err := add(KindSnapshotRepository, name, ok, current, r, func(bool) error {
+			repoType, settings := repositorySettings(repo)
+			return snaprepoapi.Set(snaprepoapi.SetParams{
+				API: params.API, Region: params.Region, Name: name,
+				Type: repoType, Config: settings,
+			})
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	var currentTemplates = make(map[string]*models.DeploymentTemplateInfo)
+	for _, t := range target.DeploymentTemplates {
+		currentTemplates[t.ID] = t
+	}
+	for _, t := range bundle.DeploymentTemplates {
+		var template = t
+		current, ok := currentTemplates[t.ID]
+		if err := add(KindDeploymentTemplate, t.ID, ok, current, t, func(bool) error {
+			return configurationtemplateapi.UpdateTemplate(configurationtemplateapi.UpdateTemplateParams{
+				API: params.API, Region: params.Region, ID: template.ID,
+				DeploymentTemplateInfo: template,
+			})
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	var currentGroups = make(map[string]*models.ProxiesFilteredGroup)
+	for _, g := range target.ProxyFilteredGroups {
+		currentGroups[g.ID] = g
+	}
+	for _, g := range bundle.ProxyFilteredGroups {
+		var group = g
+		current, ok := currentGroups[g.ID]
+		if err := add(KindProxyFilteredGroup, g.ID, ok, current, g, func(create bool) error {
+			return setFilteredGroup(params, group, create)
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	var currentRealms = make(map[string]*SecurityRealm)
+	for _, r := range target.SecurityRealms {
+		currentRealms[r.ID] = r
+	}
+	for _, r := range bundle.SecurityRealms {
+		var realm = r
+		current, ok := currentRealms[r.ID]
+		password, hasPassword := params.RealmPasswords[r.ID]
+		if err := add(KindSecurityRealm, r.ID, ok, current, r, func(create bool) error {
+			return setRealm(params.API, params.Region, withBindPassword(realm, password), create)
+		}); err != nil {
+			return nil, err
+		}
+
+		var change = &changes[len(changes)-1]
+		if change.Action != ActionNone && requiresBindPassword(realm) && !hasPassword {
+			change.Action, change.Diff = ActionSkip, ""
+			change.Reason = "bind password not set in the realm passwords"
+		}
+	}
+
+	var currentRoles = make(map[string]*models.Role)
+	for _, r := range target.Roles {
+		currentRoles[stringValue(r.ID)] = r
+	}
+	for _, r := range bundle.Roles {
+		var role, id = r, stringValue(r.ID)
+		current, ok := currentRoles[id]
+		if err := add(KindRole, id, ok, current, r, func(create bool) error {
+			if create {
+				return roleapi.Create(roleapi.CreateParams{
+					API: params.API, Region: params.Region,
+					Role: &models.RoleAggregateCreateData{Role: role},
+				})
+			}
+			return roleapi.Update(roleapi.UpdateParams{
+				API: params.API, Region: params.Region, ID: id, Role: role,
+			})
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	return changes, nil
+}
+
+// setFilteredGroup creates or updates a proxy filtered group, updates are
+// sent with the current version of the group.
+func setFilteredGroup(params ImportParams, group *models.ProxiesFilteredGroup, create bool) error {
+	var filters = make(map[string]string, len(group.Filters))
+	for _, f := range group.Filters {
+		if f != nil {
+			filters[stringValue(f.Key)] = stringValue(f.Value)
+		}
+	}
+
+	var expected int32
+	if group.ExpectedProxiesCount != nil {
+		expected = *group.ExpectedProxiesCount
+	}
+
+	if create {
+		_, err := filteredgroupapi.Create(filteredgroupapi.CreateParams{
+			API: params.API, Region: params.Region, ID: group.ID,
+			Filters: filters, ExpectedProxiesCount: expected,
+		})
+		return err
+	}
+
+	res, err := params.V1API.PlatformInfrastructure.GetProxiesFilteredGroup(
+		platform_infrastructure.NewGetProxiesFilteredGroupParams().
+			WithContext(api.WithRegion(context.Background(), params.Region)).
+			WithProxiesFilteredGroupID(group.ID),
+		params.AuthWriter,
+	)
+	if err != nil {
+		return apierror.Unwrap(err)
+	}
+
+	var version int64
+	if res.XCloudResourceVersion != "" {
+		if version, err = strconv.ParseInt(res.XCloudResourceVersion, 10, 64); err != nil {
+			return err
+		}
+	}
+
+	_, err = filteredgroupapi.Update(filteredgroupapi.UpdateParams{
+		API: params.API, Region: params.Region, ID: group.ID,
+		Filters: filters, ExpectedProxiesCount: expected, Version: version,
+	})
+	return err
+}
+
+// repositorySettings returns the type and settings of a snapshot repository
+// config, which are either nested in the "type" and "settings" keys or are
+// the config itself for the default repository type.
+func repositorySettings(repo *models.RepositoryConfig) (string, snaprepoapi.GenericConfig) {
+	var config, _ = repo.Config.(map[string]interface{})
+	if t, ok := config["type"].(string); ok {
+		if settings, ok := config["settings"].(map[string]interface{}); ok {
+			return t, snaprepoapi.GenericConfig(settings)
+		}
+	}
+	return defaultRepositoryType, snaprepoapi.GenericConfig(config)
+}
+
+// remap renames the bundle resources and the instance configuration
+// references of the deployment templates following the mapping.
+func remap(bundle *Bundle, mapping map[string]string) error {
+	if len(mapping) == 0 {
+		return nil
+	}
+
+	var mapID = func(id string) string {
+		if to, ok := mapping[id]; ok {
+			return to
+		}
+		return id
+	}
+
+	for _, c := range bundle.InstanceConfigurations {
+		c.ID = mapID(c.ID)
+	}
+
+	for _, r := range bundle.SnapshotRepositories {
+		r.RepositoryName = ec.String(mapID(stringValue(r.RepositoryName)))
+	}
+
+	for i, t := range bundle.DeploymentTemplates {
+		remapped, err := remapTemplate(t, mapping)
+		if err != nil {
+			return fmt.Errorf("deployment template %s: %s", t.ID, err)
+		}
+		remapped.ID = mapID(t.ID)
+		bundle.DeploymentTemplates[i] = remapped
+	}
+
+	for _, g := range bundle.ProxyFilteredGroups {
+		g.ID = mapID(g.ID)
+	}
+
+	for _, r := range bundle.SecurityRealms {
+		r.ID = mapID(r.ID)
+		switch {
+		case r.ActiveDirectory != nil:
+			r.ActiveDirectory.ID = ec.String(r.ID)
+		case r.Ldap != nil:
+			r.Ldap.ID = ec.String(r.ID)
+		case r.Saml != nil:
+			r.Saml.ID = ec.String(r.ID)
+		}
+	}
+
+	for _, r := range bundle.Roles {
+		r.ID = ec.String(mapID(stringValue(r.ID)))
+	}
+
+	return nil
+}
+
+// remapTemplate replaces the values of any "instance_configuration_id" field
+// of the template following the mapping.
+func remapTemplate(t *models.DeploymentTemplateInfo, mapping map[string]string) (*models.DeploymentTemplateInfo, error) {
+	b, err := json.Marshal(t)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, err
+	}
+	remapInstanceConfigurationIDs(raw, mapping)
+
+	if b, err = json.Marshal(raw); err != nil {
+		return nil, err
+	}
+
+	var res models.DeploymentTemplateInfo
+	if err := json.Unmarshal(b, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func remapInstanceConfigurationIDs(v interface{}, mapping map[string]string) {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for k, field := range value {
+			if id, ok := field.(string); ok && k == "instance_configuration_id" {
+				if to, ok := mapping[id]; ok {
+					value[k] = to
+				}
+				continue
+			}
+			remapInstanceConfigurationIDs(field, mapping)
+		}
+	case []interface{}:
+		for _, item := range value {
+			remapInstanceConfigurationIDs(item, mapping)
+		}
+	}
+}
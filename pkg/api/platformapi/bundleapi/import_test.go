@@ -0,0 +1,304 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package bundleapi
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/api/mock"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+func changeSummary(r *ImportReport) []string {
+	if r == nil {
+		return nil
+	}
+
+	var res []string
+	for _, c := range r.Changes {
+		res = append(res, fmt.Sprintf("%s %s %s %t", c.Kind, c.ID, c.Action, c.Applied))
+	}
+	return res
+}
+
+func TestImport(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bundle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := WriteBundle(dir, newTestBundle()); err != nil {
+		t.Fatal(err)
+	}
+
+	var changedBundle = func() *Bundle {
+		var b = newTestBundle()
+		b.InstanceConfigurations[0].Name = ec.String("old name")
+		b.SnapshotRepositories[0].Config = map[string]interface{}{"bucket": "old-bucket"}
+		return b
+	}
+	var instanceConfigAssertion = &mock.RequestAssertion{
+		Header: api.DefaultWriteMockHeaders,
+		Method: "PUT",
+		Host:   api.DefaultMockHost,
+		Path:   regionPath + "/platform/configuration/instances/data.default",
+		Body:   mock.NewStructBody(newTestBundle().InstanceConfigurations[0]),
+	}
+	var repoAssertion = &mock.RequestAssertion{
+		Header: api.DefaultWriteMockHeaders,
+		Method: "PUT",
+		Host:   api.DefaultMockHost,
+		Path:   regionPath + "/platform/configuration/snapshots/repositories/backups",
+		Body: mock.NewStructBody(models.SnapshotRepositoryConfiguration{
+			Type:     ec.String("s3"),
+			Settings: map[string]interface{}{"bucket": "my-bucket", "region": "us-east-1"},
+		}),
+	}
+
+	tests := []struct {
+		name    string
+		params  ImportParams
+		want    []string
+		changed int
+		err     error
+	}{
+		{
+			name: "fails due to parameter validation",
+			params: ImportParams{
+				IDMapping: map[string]string{"data.default": ""},
+			},
+			err: multierror.NewPrefixed("invalid platform configuration import params",
+				apierror.ErrMissingAPI,
+				errDirectoryCannotBeEmpty,
+				errors.New(`invalid id mapping "data.default" to ""`),
+				errors.New("region not specified and is required for this operation"),
+			),
+		},
+		{
+			name: "fails reading the bundle",
+			params: ImportParams{
+				API:       api.NewMock(),
+				Region:    "us-east-1",
+				Directory: "nonexistent",
+			},
+			err: errors.New("open nonexistent/bundle.json: no such file or directory"),
+		},
+		{
+			name: "computes the changes against an empty region on dry run",
+			params: ImportParams{
+				API:       api.NewMock(collectResponses(&Bundle{})...),
+				Region:    "us-east-1",
+				Directory: dir,
+				DryRun:    true,
+			},
+			want: []string{
+				"instance_configurations data.default create false",
+				"snapshot_repositories backups create false",
+				"deployment_templates default create false",
+				"proxy_filtered_groups zone-a create false",
+				"security_realms ldap1 create false",
+				"roles proxy create false",
+			},
+			changed: 6,
+		},
+		{
+			name: "computes the changes of remapped ids on dry run",
+			params: ImportParams{
+				API:       api.NewMock(collectResponses(newTestBundle())...),
+				Region:    "us-east-1",
+				Directory: dir,
+				DryRun:    true,
+				IDMapping: map[string]string{"data.default": "data.prod"},
+			},
+			want: []string{
+				"instance_configurations data.prod create false",
+				"snapshot_repositories backups unchanged false",
+				"deployment_templates default update false",
+				"proxy_filtered_groups zone-a unchanged false",
+				"security_realms ldap1 unchanged false",
+				"roles proxy unchanged false",
+			},
+			changed: 2,
+		},
+		{
+			name: "applies the changed resources",
+			params: ImportParams{
+				API: api.NewMock(append(collectResponses(changedBundle()),
+					mock.New200ResponseAssertion(instanceConfigAssertion, mock.NewStringBody("{}")),
+					mock.New200ResponseAssertion(repoAssertion, mock.NewStringBody("{}")),
+				)...),
+				Region:    "us-east-1",
+				Directory: dir,
+			},
+			want: []string{
+				"instance_configurations data.default update true",
+				"snapshot_repositories backups update true",
+				"deployment_templates default unchanged false",
+				"proxy_filtered_groups zone-a unchanged false",
+				"security_realms ldap1 unchanged false",
+				"roles proxy unchanged false",
+			},
+			changed: 2,
+		},
+		{
+			name: "stops applying the subsequent kinds after a failure",
+			params: ImportParams{
+				API: api.NewMock(append(collectResponses(changedBundle()),
+					mock.New500Response(mock.NewStringBody(`{"error": "some error"}`)),
+				)...),
+				Region:    "us-east-1",
+				Directory: dir,
+			},
+			want: []string{
+				"instance_configurations data.default update false",
+				"snapshot_repositories backups update false",
+				"deployment_templates default unchanged false",
+				"proxy_filtered_groups zone-a unchanged false",
+				"security_realms ldap1 unchanged false",
+				"roles proxy unchanged false",
+			},
+			changed: 2,
+			err: multierror.NewPrefixed("failed importing platform configuration",
+				fmt.Errorf("instance_configurations data.default: %w",
+					errors.New(`{"error": "some error"}`),
+				),
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Import(tt.params)
+			if tt.err != nil {
+				assert.EqualError(t, err, tt.err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, changeSummary(got))
+			if got != nil {
+				assert.Len(t, got.Changed(), tt.changed)
+				assert.Equal(t, tt.params.DryRun, got.DryRun)
+			}
+		})
+	}
+}
+
+func TestRemap(t *testing.T) {
+	var bundle = newTestBundle()
+	err := remap(bundle, map[string]string{
+		"data.default": "data.prod",
+		"ldap1":        "ldap2",
+		"proxy":        "proxy-prod",
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "data.prod", bundle.InstanceConfigurations[0].ID)
+	assert.Equal(t, "data.prod", bundle.DeploymentTemplates[0].DeploymentTemplate.
+		Resources.Elasticsearch[0].Plan.ClusterTopology[0].InstanceConfigurationID,
+	)
+	assert.Equal(t, "default", bundle.DeploymentTemplates[0].ID)
+	assert.Equal(t, "ldap2", bundle.SecurityRealms[0].ID)
+	assert.Equal(t, ec.String("ldap2"), bundle.SecurityRealms[0].Ldap.ID)
+	assert.Equal(t, ec.String("proxy-prod"), bundle.Roles[0].ID)
+	assert.Equal(t, ec.String("backups"), bundle.SnapshotRepositories[0].RepositoryName)
+}
+
+func TestImport_realmPasswords(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bundle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var newRealm = func(password string) *SecurityRealm {
+		return &SecurityRealm{ID: "ldap1", Type: "ldap", Ldap: &models.LdapSettings{
+			ID:           ec.String("ldap1"),
+			Name:         ec.String("corporate"),
+			BindDn:       "cn=admin,dc=example,dc=com",
+			BindPassword: password,
+		}}
+	}
+	if err := WriteBundle(dir, &Bundle{
+		Manifest:       Manifest{Version: Version},
+		SecurityRealms: []*SecurityRealm{newRealm("")},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		params  ImportParams
+		want    []Change
+		changed int
+	}{
+		{
+			name: "skips the realms which bind without a password",
+			params: ImportParams{
+				API:       api.NewMock(collectResponses(&Bundle{})...),
+				Region:    "us-east-1",
+				Directory: dir,
+			},
+			want: []Change{{
+				Kind: KindSecurityRealm, ID: "ldap1", Action: ActionSkip,
+				Reason: "bind password not set in the realm passwords",
+			}},
+		},
+		{
+			name: "creates the realms with their bind password",
+			params: ImportParams{
+				API: api.NewMock(append(collectResponses(&Bundle{}),
+					mock.New201ResponseAssertion(&mock.RequestAssertion{
+						Header: api.DefaultWriteMockHeaders,
+						Method: "POST",
+						Host:   api.DefaultMockHost,
+						Path:   regionPath + "/platform/configuration/security/realms/ldap",
+						Body:   mock.NewStructBody(newRealm("secret").Ldap),
+					}, mock.NewStringBody("{}")),
+				)...),
+				Region:         "us-east-1",
+				Directory:      dir,
+				RealmPasswords: map[string]string{"ldap1": "secret"},
+			},
+			want: []Change{{
+				Kind: KindSecurityRealm, ID: "ldap1", Action: ActionCreate, Applied: true,
+			}},
+			changed: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Import(tt.params)
+			assert.NoError(t, err)
+			for i := range got.Changes {
+				got.Changes[i].apply = nil
+			}
+			assert.Equal(t, tt.want, got.Changes)
+			assert.Len(t, got.Changed(), tt.changed)
+		})
+	}
+}
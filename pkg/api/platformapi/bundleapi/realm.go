@@ -0,0 +1,166 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package bundleapi
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/client/platform_configuration_security"
+)
+
+// Security realm types which can be exported and imported. The native realm
+// is built in and can't be configured.
+const (
+	realmActiveDirectory = "active_directory"
+	realmLdap            = "ldap"
+	realmSaml            = "saml"
+)
+
+// getRealms obtains the configuration of all the configurable security realms.
+func getRealms(a *api.API, region string) ([]*SecurityRealm, error) {
+	var ctx = api.WithRegion(context.Background(), region)
+	var security = a.V1API.PlatformConfigurationSecurity
+	res, err := security.GetSecurityRealmConfigurations(
+		platform_configuration_security.NewGetSecurityRealmConfigurationsParams().
+			WithContext(ctx),
+		a.AuthWriter,
+	)
+	if err != nil {
+		return nil, apierror.Unwrap(err)
+	}
+
+	var realms []*SecurityRealm
+	for _, info := range res.Payload.Realms {
+		if info == nil || info.ID == nil || info.Type == nil {
+			continue
+		}
+
+		var realm = SecurityRealm{ID: *info.ID, Type: *info.Type}
+		switch realm.Type {
+		case realmActiveDirectory:
+			res, err := security.GetActiveDirectoryConfiguration(
+				platform_configuration_security.NewGetActiveDirectoryConfigurationParams().
+					WithContext(ctx).WithRealmID(realm.ID),
+				a.AuthWriter,
+			)
+			if err != nil {
+				return nil, apierror.Unwrap(err)
+			}
+			realm.ActiveDirectory = res.Payload
+		case realmLdap:
+			res, err := security.GetLdapConfiguration(
+				platform_configuration_security.NewGetLdapConfigurationParams().
+					WithContext(ctx).WithRealmID(realm.ID),
+				a.AuthWriter,
+			)
+			if err != nil {
+				return nil, apierror.Unwrap(err)
+			}
+			realm.Ldap = res.Payload
+		case realmSaml:
+			res, err := security.GetSamlConfiguration(
+				platform_configuration_security.NewGetSamlConfigurationParams().
+					WithContext(ctx).WithRealmID(realm.ID),
+				a.AuthWriter,
+			)
+			if err != nil {
+				return nil, apierror.Unwrap(err)
+			}
+			realm.Saml = res.Payload
+		default:
+			continue
+		}
+		realms = append(realms, &realm)
+	}
+
+	return realms, nil
+}
+
+// requiresBindPassword returns true when the realm binds with a DN, whose
+// password isn't returned by the API.
+func requiresBindPassword(realm *SecurityRealm) bool {
+	return (realm.Ldap != nil && realm.Ldap.BindDn != "") ||
+		(realm.ActiveDirectory != nil && realm.ActiveDirectory.BindDn != "")
+}
+
+// withBindPassword returns a copy of the realm with the bind password set,
+// the realm is returned as is when the password is empty.
+func withBindPassword(realm *SecurityRealm, password string) *SecurityRealm {
+	if password == "" {
+		return realm
+	}
+
+	var res = *realm
+	if realm.Ldap != nil {
+		var ldap = *realm.Ldap
+		ldap.BindPassword, res.Ldap = password, &ldap
+	}
+	if realm.ActiveDirectory != nil {
+		var ad = *realm.ActiveDirectory
+		ad.BindPassword, res.ActiveDirectory = password, &ad
+	}
+	return &res
+}
+
+// setRealm creates or updates a security realm configuration.
+func setRealm(a *api.API, region string, realm *SecurityRealm, create bool) error {
+	var ctx = api.WithRegion(context.Background(), region)
+	var security = a.V1API.PlatformConfigurationSecurity
+	switch {
+	case realm.ActiveDirectory != nil && create:
+		return api.ReturnErrOnly(security.CreateActiveDirectoryConfiguration(
+			platform_configuration_security.NewCreateActiveDirectoryConfigurationParams().
+				WithContext(ctx).WithBody(realm.ActiveDirectory),
+			a.AuthWriter,
+		))
+	case realm.ActiveDirectory != nil:
+		return api.ReturnErrOnly(security.UpdateActiveDirectoryConfiguration(
+			platform_configuration_security.NewUpdateActiveDirectoryConfigurationParams().
+				WithContext(ctx).WithRealmID(realm.ID).WithBody(realm.ActiveDirectory),
+			a.AuthWriter,
+		))
+	case realm.Ldap != nil && create:
+		return api.ReturnErrOnly(security.CreateLdapConfiguration(
+			platform_configuration_security.NewCreateLdapConfigurationParams().
+				WithContext(ctx).WithBody(realm.Ldap),
+			a.AuthWriter,
+		))
+	case realm.Ldap != nil:
+		return api.ReturnErrOnly(security.UpdateLdapConfiguration(
+			platform_configuration_security.NewUpdateLdapConfigurationParams().
+				WithContext(ctx).WithRealmID(realm.ID).WithBody(realm.Ldap),
+			a.AuthWriter,
+		))
+	case realm.Saml != nil && create:
+		return api.ReturnErrOnly(security.CreateSamlConfiguration(
+			platform_configuration_security.NewCreateSamlConfigurationParams().
+				WithContext(ctx).WithBody(realm.Saml),
+			a.AuthWriter,
+		))
+	case realm.Saml != nil:
+		return api.ReturnErrOnly(security.UpdateSamlConfiguration(
+			platform_configuration_security.NewUpdateSamlConfigurationParams().
+				WithContext(ctx).WithRealmID(realm.ID).WithBody(realm.Saml),
+			a.AuthWriter,
+		))
+	}
+	return fmt.Errorf("security realm %s of type %s has no settings", realm.ID, realm.Type)
+}
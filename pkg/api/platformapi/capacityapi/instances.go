@@ -0,0 +1,108 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package capacityapi
+
+import (
+	"fmt"
+
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/resourcekind"
+	"github.com/elastic/cloud-sdk-go/pkg/util/slice"
+)
+
+// Instance is an instance which is placed on an allocator, its memory is
+// expressed in MB.
+type Instance struct {
+	ID     string `json:"id"`
+	Memory int32  `json:"memory"`
+
+	// Group contains the instances which must be placed in distinct zones,
+	// i.e. the instances of the same topology element.
+	Group string `json:"group,omitempty"`
+
+	// Optional zone where the instance must be placed.
+	Zone string `json:"zone,omitempty"`
+}
+
+// VacateInstances returns the instances which would be moved when vacating
+// the specified allocators. Since a vacate moves instances within the same
+// zone, the instances are pinned to the zone of their allocator.
+func (m *Model) VacateInstances(allocators ...string) []Instance {
+	var instances []Instance
+	for _, a := range m.Allocators {
+		if slice.HasString(allocators, a.ID) {
+			instances = append(instances, a.Instances...)
+		}
+	}
+	return instances
+}
+
+// DeploymentInstances returns the instances of a deployment's resources
+// topology. Each of the topology elements creates one instance per zone,
+// grouped by the resource RefID and the element's index so that they are
+// placed in distinct zones. Topology elements without a memory size are
+// ignored.
+func DeploymentInstances(resources *models.DeploymentCreateResources) []Instance {
+	if resources == nil {
+		return nil
+	}
+
+	var instances []Instance
+	for _, kind := range resourcekind.All() {
+		for _, payload := range kind.Payloads(resources) {
+			var ref = kind.RefID(payload)
+			if ref == "" {
+				ref = kind.Name
+			}
+
+			for i, t := range kind.Topology(payload) {
+				instances = append(instances, topologyInstances(
+					ref, i, resourcekind.TopologySize(t), resourcekind.ZoneCount(t),
+				)...)
+			}
+		}
+	}
+
+	return instances
+}
+
+func topologyInstances(ref string, index int, size *models.TopologySize, zones int32) []Instance {
+	if size == nil || size.Value == nil || *size.Value == 0 {
+		return nil
+	}
+
+	if size.Resource != nil && *size.Resource != "memory" {
+		return nil
+	}
+
+	if zones == 0 {
+		zones = 1
+	}
+
+	var group = fmt.Sprintf("%s-%d", ref, index)
+	var instances = make([]Instance, 0, zones)
+	for z := int32(0); z < zones; z++ {
+		instances = append(instances, Instance{
+			ID:     fmt.Sprintf("%s-%d", group, z),
+			Memory: *size.Value,
+			Group:  group,
+		})
+	}
+
+	return instances
+}
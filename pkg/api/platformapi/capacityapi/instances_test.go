@@ -0,0 +1,93 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package capacityapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+func TestDeploymentInstances(t *testing.T) {
+	tests := []struct {
+		name      string
+		resources *models.DeploymentCreateResources
+		want      []Instance
+	}{
+		{name: "returns no instances when the resources are nil"},
+		{
+			name: "returns one instance per zone of each topology element",
+			resources: &models.DeploymentCreateResources{
+				Elasticsearch: []*models.ElasticsearchPayload{{
+					RefID: ec.String("main-elasticsearch"),
+					Plan: &models.ElasticsearchClusterPlan{
+						ClusterTopology: []*models.ElasticsearchClusterTopologyElement{
+							{
+								ZoneCount: 2,
+								Size:      &models.TopologySize{Resource: ec.String("memory"), Value: ec.Int32(4096)},
+							},
+							{
+								ZoneCount: 1,
+								Size:      &models.TopologySize{Resource: ec.String("memory"), Value: ec.Int32(0)},
+							},
+						},
+					},
+				}},
+				Kibana: []*models.KibanaPayload{{
+					Plan: &models.KibanaClusterPlan{
+						ClusterTopology: []*models.KibanaClusterTopologyElement{{
+							Size: &models.TopologySize{Resource: ec.String("memory"), Value: ec.Int32(1024)},
+						}},
+					},
+				}},
+				Apm: []*models.ApmPayload{{
+					RefID: ec.String("main-apm"),
+					Plan: &models.ApmPlan{
+						ClusterTopology: []*models.ApmTopologyElement{{
+							ZoneCount: 1,
+							Size:      &models.TopologySize{Resource: ec.String("storage"), Value: ec.Int32(1024)},
+						}},
+					},
+				}},
+			},
+			want: []Instance{
+				{ID: "main-elasticsearch-0-0", Memory: 4096, Group: "main-elasticsearch-0"},
+				{ID: "main-elasticsearch-0-1", Memory: 4096, Group: "main-elasticsearch-0"},
+				{ID: "kibana-0-0", Memory: 1024, Group: "kibana-0"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, DeploymentInstances(tt.resources))
+		})
+	}
+}
+
+func TestModel_VacateInstances(t *testing.T) {
+	assert.Equal(t, []Instance{{
+		ID:     "instance-0000000000",
+		Memory: 4096,
+		Group:  "3ee11eb40eda22cac0cce259625c6734-data.default",
+		Zone:   "zone-1",
+	}}, overviewModel.VacateInstances("allocator-1", "allocator-3"))
+	assert.Nil(t, overviewModel.VacateInstances("allocator-2"))
+}
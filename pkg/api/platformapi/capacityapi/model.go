@@ -0,0 +1,186 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package capacityapi
+
+import (
+	"sort"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/api/platformapi/allocatorapi"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+// Model is the capacity model of a set of allocators.
+type Model struct {
+	Allocators []Allocator `json:"allocators"`
+}
+
+// Allocator is the capacity model of a single allocator, memory values are
+// expressed in MB.
+type Allocator struct {
+	ID    string            `json:"id"`
+	Zone  string            `json:"zone"`
+	Total int32             `json:"total"`
+	Used  int32             `json:"used"`
+	Tags  map[string]string `json:"tags,omitempty"`
+
+	// Schedulable is false when the allocator is either disconnected,
+	// unhealthy or in maintenance mode, no instances are placed on it.
+	Schedulable bool `json:"schedulable"`
+
+	// Instances are the instances which are allocated on the allocator.
+	Instances []Instance `json:"instances,omitempty"`
+}
+
+// Free returns the memory which isn't used.
+func (a Allocator) Free() int32 { return a.Total - a.Used }
+
+// NewModelParams is consumed by NewModel.
+type NewModelParams struct {
+	*api.API
+
+	Region string
+
+	// Optional allocator search query.
+	Query string
+
+	// Optional tags which the allocators must have. Expected format is a
+	// key:value slice. i.e. [key:val, key:value]
+	FilterTags string
+}
+
+// Validate ensures the parameters are usable.
+func (params NewModelParams) Validate() error {
+	var merr = multierror.NewPrefixed("capacity model")
+	if params.API == nil {
+		merr = merr.Append(apierror.ErrMissingAPI)
+	}
+
+	if err := ec.RequireRegionSet(params.Region); err != nil {
+		merr = merr.Append(err)
+	}
+
+	return merr.ErrorOrNil()
+}
+
+// NewModel obtains the allocators of a region and builds their capacity
+// model.
+func NewModel(params NewModelParams) (*Model, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	res, err := allocatorapi.List(allocatorapi.ListParams{
+		API:        params.API,
+		Region:     params.Region,
+		Query:      params.Query,
+		FilterTags: params.FilterTags,
+		ShowAll:    true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return NewModelFromOverview(res), nil
+}
+
+// NewModelFromOverview builds the capacity model of the allocators contained
+// in the overview. The allocators are sorted by ID.
+func NewModelFromOverview(overview *models.AllocatorOverview) *Model {
+	var model = Model{Allocators: make([]Allocator, 0)}
+	if overview == nil {
+		return &model
+	}
+
+	for _, z := range overview.Zones {
+		for _, a := range z.Allocators {
+			model.Allocators = append(model.Allocators, newAllocator(a))
+		}
+	}
+
+	sort.Slice(model.Allocators, func(i, j int) bool {
+		return model.Allocators[i].ID < model.Allocators[j].ID
+	})
+
+	return &model
+}
+
+func newAllocator(a *models.AllocatorInfo) Allocator {
+	var alloc = Allocator{ID: *a.AllocatorID}
+	if a.ZoneID != nil {
+		alloc.Zone = *a.ZoneID
+	}
+
+	if a.Capacity != nil && a.Capacity.Memory != nil {
+		if a.Capacity.Memory.Total != nil {
+			alloc.Total = *a.Capacity.Memory.Total
+		}
+		if a.Capacity.Memory.Used != nil {
+			alloc.Used = *a.Capacity.Memory.Used
+		}
+	}
+
+	if s := a.Status; s != nil {
+		alloc.Schedulable = s.Connected != nil && *s.Connected &&
+			s.Healthy != nil && *s.Healthy &&
+			(s.MaintenanceMode == nil || !*s.MaintenanceMode)
+	}
+
+	for _, m := range a.Metadata {
+		if m.Key == nil || m.Value == nil {
+			continue
+		}
+		if alloc.Tags == nil {
+			alloc.Tags = make(map[string]string)
+		}
+		alloc.Tags[*m.Key] = *m.Value
+	}
+
+	for _, i := range a.Instances {
+		var instance = Instance{Zone: alloc.Zone}
+		if i.InstanceName != nil {
+			instance.ID = *i.InstanceName
+		}
+		instance.Group = instanceGroup(i)
+		if i.NodeMemory != nil {
+			instance.Memory = *i.NodeMemory
+		}
+		alloc.Instances = append(alloc.Instances, instance)
+	}
+
+	return alloc
+}
+
+// instanceGroup returns the group of an allocated instance, which is its
+// cluster topology element identified by the cluster ID and the instance
+// configuration ID, so that the instances of different tiers of the same
+// cluster can share a zone.
+func instanceGroup(i *models.AllocatedInstanceStatus) string {
+	if i.ClusterID == nil || *i.ClusterID == "" {
+		return ""
+	}
+
+	if i.InstanceConfigurationID == "" {
+		return *i.ClusterID
+	}
+
+	return *i.ClusterID + "-" + i.InstanceConfigurationID
+}
@@ -0,0 +1,154 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package capacityapi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/api/mock"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+func newAllocatorInfo(id, zone string, total, used int32, healthy bool, instances ...*models.AllocatedInstanceStatus) *models.AllocatorInfo {
+	return &models.AllocatorInfo{
+		AllocatorID: ec.String(id),
+		ZoneID:      ec.String(zone),
+		Capacity: &models.AllocatorCapacity{Memory: &models.AllocatorCapacityMemory{
+			Total: ec.Int32(total),
+			Used:  ec.Int32(used),
+		}},
+		Status: &models.AllocatorHealthStatus{
+			Connected:       ec.Bool(true),
+			Healthy:         ec.Bool(healthy),
+			MaintenanceMode: ec.Bool(false),
+		},
+		Metadata: []*models.MetadataItem{
+			{Key: ec.String("hardware"), Value: ec.String("io")},
+		},
+		Instances: instances,
+	}
+}
+
+var overview = &models.AllocatorOverview{Zones: []*models.AllocatorZoneInfo{
+	{
+		ZoneID: ec.String("zone-2"),
+		Allocators: []*models.AllocatorInfo{
+			newAllocatorInfo("allocator-3", "zone-2", 8192, 0, false),
+		},
+	},
+	{
+		ZoneID: ec.String("zone-1"),
+		Allocators: []*models.AllocatorInfo{
+			newAllocatorInfo("allocator-2", "zone-1", 8192, 1024, true),
+			newAllocatorInfo("allocator-1", "zone-1", 8192, 4096, true,
+				&models.AllocatedInstanceStatus{
+					ClusterID:               ec.String("3ee11eb40eda22cac0cce259625c6734"),
+					InstanceConfigurationID: "data.default",
+					InstanceName:            ec.String("instance-0000000000"),
+					NodeMemory:              ec.Int32(4096),
+				},
+			),
+		},
+	},
+}}
+
+var overviewModel = &Model{Allocators: []Allocator{
+	{
+		ID: "allocator-1", Zone: "zone-1", Total: 8192, Used: 4096,
+		Tags: map[string]string{"hardware": "io"}, Schedulable: true,
+		Instances: []Instance{{
+			ID:     "instance-0000000000",
+			Memory: 4096,
+			Group:  "3ee11eb40eda22cac0cce259625c6734-data.default",
+			Zone:   "zone-1",
+		}},
+	},
+	{
+		ID: "allocator-2", Zone: "zone-1", Total: 8192, Used: 1024,
+		Tags: map[string]string{"hardware": "io"}, Schedulable: true,
+	},
+	{
+		ID: "allocator-3", Zone: "zone-2", Total: 8192,
+		Tags: map[string]string{"hardware": "io"},
+	},
+}}
+
+func TestNewModel(t *testing.T) {
+	type args struct {
+		params NewModelParams
+	}
+	tests := []struct {
+		name string
+		args args
+		want *Model
+		err  error
+	}{
+		{
+			name: "fails due to parameter validation",
+			err: multierror.NewPrefixed("capacity model",
+				apierror.ErrMissingAPI,
+				errors.New("region not specified and is required for this operation"),
+			),
+		},
+		{
+			name: "fails when the API returns an error",
+			args: args{params: NewModelParams{
+				API:    api.NewMock(mock.New500Response(mock.NewStringBody(`{"error": "some error"}`))),
+				Region: "us-east-1",
+			}},
+			err: errors.New(`{"error": "some error"}`),
+		},
+		{
+			name: "builds the model from the allocators",
+			args: args{params: NewModelParams{
+				API: api.NewMock(mock.New200ResponseAssertion(&mock.RequestAssertion{
+					Header: api.DefaultReadMockHeaders,
+					Method: "GET",
+					Host:   api.DefaultMockHost,
+					Path:   "/api/v1/regions/us-east-1/platform/infrastructure/allocators",
+					Query: map[string][]string{
+						"q": {"allocator_id:allocator*"},
+					},
+				}, mock.NewStructBody(overview))),
+				Region:     "us-east-1",
+				Query:      "allocator_id:allocator*",
+				FilterTags: "hardware:io",
+			}},
+			want: overviewModel,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewModel(tt.args.params)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewModelFromOverview(t *testing.T) {
+	assert.Equal(t, &Model{Allocators: []Allocator{}}, NewModelFromOverview(nil))
+	assert.Equal(t, overviewModel, NewModelFromOverview(overview))
+}
@@ -0,0 +1,169 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package capacityapi
+
+import (
+	"sort"
+
+	"github.com/elastic/cloud-sdk-go/pkg/util/slice"
+)
+
+// SimulateParams is consumed by Model.Simulate.
+type SimulateParams struct {
+	// Instances to place.
+	Instances []Instance
+
+	// Allocators which can't be used to place the instances. When simulating
+	// a vacate, the vacated allocators must be listed here, otherwise their
+	// instances may be placed back on them.
+	Exclude []string
+}
+
+// Result is the outcome of a placement simulation.
+type Result struct {
+	// Fits is true when all of the instances could be placed.
+	Fits bool `json:"fits"`
+
+	Placements []Placement `json:"placements"`
+
+	// Unplaced contains the instances which didn't fit.
+	Unplaced []Instance `json:"unplaced,omitempty"`
+
+	// Zones contains the headroom of each zone after the placement.
+	Zones []ZoneHeadroom `json:"zones"`
+}
+
+// Placement is the allocator where an instance is placed.
+type Placement struct {
+	Instance    Instance `json:"instance"`
+	AllocatorID string   `json:"allocator_id"`
+	Zone        string   `json:"zone"`
+}
+
+// ZoneHeadroom is the memory capacity of the allocators of a zone which can
+// be used to place instances.
+type ZoneHeadroom struct {
+	Zone  string `json:"zone"`
+	Total int32  `json:"total"`
+	Used  int32  `json:"used"`
+	Free  int32  `json:"free"`
+}
+
+// Simulate places the instances on the model's allocators without modifying
+// the model. The placement is greedy: the largest instances are placed first,
+// each on the allocator which has the least free memory left afterwards. The
+// instances of the same group are placed in distinct zones, and instances
+// with a zone are only placed in that zone. Since instances with a zone keep
+// the zone they already had, i.e. a vacate, they aren't subject to the
+// distinct zone constraint.
+func (m *Model) Simulate(params SimulateParams) *Result {
+	var candidates []Allocator
+	var groupZones = make(map[string]map[string]bool)
+	for _, a := range m.Allocators {
+		if slice.HasString(params.Exclude, a.ID) {
+			continue
+		}
+
+		for _, i := range a.Instances {
+			markZone(groupZones, i.Group, a.Zone)
+		}
+
+		if a.Schedulable {
+			candidates = append(candidates, a)
+		}
+	}
+
+	var instances = append([]Instance(nil), params.Instances...)
+	sort.SliceStable(instances, func(i, j int) bool {
+		return instances[i].Memory > instances[j].Memory
+	})
+
+	var result = Result{Placements: make([]Placement, 0, len(instances))}
+	for _, instance := range instances {
+		var best = -1
+		for c, a := range candidates {
+			if a.Free() < instance.Memory {
+				continue
+			}
+			if instance.Zone != "" && instance.Zone != a.Zone {
+				continue
+			}
+			if instance.Zone == "" && instance.Group != "" && groupZones[instance.Group][a.Zone] {
+				continue
+			}
+			if best < 0 || a.Free() < candidates[best].Free() {
+				best = c
+			}
+		}
+
+		if best < 0 {
+			result.Unplaced = append(result.Unplaced, instance)
+			continue
+		}
+
+		var a = &candidates[best]
+		a.Used += instance.Memory
+		markZone(groupZones, instance.Group, a.Zone)
+		result.Placements = append(result.Placements, Placement{
+			Instance:    instance,
+			AllocatorID: a.ID,
+			Zone:        a.Zone,
+		})
+	}
+
+	result.Fits = len(result.Unplaced) == 0
+	result.Zones = zoneHeadroom(candidates)
+
+	return &result
+}
+
+func markZone(groupZones map[string]map[string]bool, group, zone string) {
+	if group == "" {
+		return
+	}
+
+	if groupZones[group] == nil {
+		groupZones[group] = make(map[string]bool)
+	}
+	groupZones[group][zone] = true
+}
+
+func zoneHeadroom(allocators []Allocator) []ZoneHeadroom {
+	var zones = make(map[string]*ZoneHeadroom)
+	var names []string
+	for _, a := range allocators {
+		z, ok := zones[a.Zone]
+		if !ok {
+			z = &ZoneHeadroom{Zone: a.Zone}
+			zones[a.Zone] = z
+			names = append(names, a.Zone)
+		}
+
+		z.Total += a.Total
+		z.Used += a.Used
+		z.Free += a.Free()
+	}
+
+	sort.Strings(names)
+	var headroom = make([]ZoneHeadroom, 0, len(names))
+	for _, name := range names {
+		headroom = append(headroom, *zones[name])
+	}
+
+	return headroom
+}
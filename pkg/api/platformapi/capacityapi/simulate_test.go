@@ -0,0 +1,162 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package capacityapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+func TestModel_Simulate(t *testing.T) {
+	var model = &Model{Allocators: []Allocator{
+		{ID: "allocator-1", Zone: "zone-1", Total: 8192, Used: 4096, Schedulable: true,
+			Instances: []Instance{{ID: "i-0", Memory: 4096, Group: "cluster", Zone: "zone-1"}},
+		},
+		{ID: "allocator-2", Zone: "zone-1", Total: 8192, Used: 2048, Schedulable: true},
+		{ID: "allocator-3", Zone: "zone-1", Total: 8192, Used: 0, Schedulable: true},
+		{ID: "allocator-4", Zone: "zone-2", Total: 8192, Used: 0, Schedulable: true},
+		{ID: "allocator-5", Zone: "zone-3", Total: 8192, Used: 0},
+	}}
+	tests := []struct {
+		name   string
+		params SimulateParams
+		want   *Result
+	}{
+		{
+			name: "places the vacated instances in the same zone",
+			params: SimulateParams{
+				Instances: model.VacateInstances("allocator-1"),
+				Exclude:   []string{"allocator-1"},
+			},
+			want: &Result{
+				Fits: true,
+				Placements: []Placement{{
+					Instance:    Instance{ID: "i-0", Memory: 4096, Group: "cluster", Zone: "zone-1"},
+					AllocatorID: "allocator-2",
+					Zone:        "zone-1",
+				}},
+				Zones: []ZoneHeadroom{
+					{Zone: "zone-1", Total: 16384, Used: 6144, Free: 10240},
+					{Zone: "zone-2", Total: 8192, Used: 0, Free: 8192},
+				},
+			},
+		},
+		{
+			name: "places the instances of a group in distinct zones",
+			params: SimulateParams{Instances: []Instance{
+				{ID: "es-0", Memory: 2048, Group: "es"},
+				{ID: "es-1", Memory: 2048, Group: "es"},
+				{ID: "es-2", Memory: 2048, Group: "es"},
+				{ID: "kibana-0", Memory: 8192, Group: "kibana"},
+			}},
+			want: &Result{
+				Fits: false,
+				Placements: []Placement{
+					{
+						Instance:    Instance{ID: "kibana-0", Memory: 8192, Group: "kibana"},
+						AllocatorID: "allocator-3",
+						Zone:        "zone-1",
+					},
+					{
+						Instance:    Instance{ID: "es-0", Memory: 2048, Group: "es"},
+						AllocatorID: "allocator-1",
+						Zone:        "zone-1",
+					},
+					{
+						Instance:    Instance{ID: "es-1", Memory: 2048, Group: "es"},
+						AllocatorID: "allocator-4",
+						Zone:        "zone-2",
+					},
+				},
+				Unplaced: []Instance{{ID: "es-2", Memory: 2048, Group: "es"}},
+				Zones: []ZoneHeadroom{
+					{Zone: "zone-1", Total: 24576, Used: 16384, Free: 8192},
+					{Zone: "zone-2", Total: 8192, Used: 2048, Free: 6144},
+				},
+			},
+		},
+		{
+			name: "reports the instances which don't fit",
+			params: SimulateParams{
+				Instances: []Instance{{ID: "big", Memory: 16384}},
+			},
+			want: &Result{
+				Placements: []Placement{},
+				Unplaced:   []Instance{{ID: "big", Memory: 16384}},
+				Zones: []ZoneHeadroom{
+					{Zone: "zone-1", Total: 24576, Used: 6144, Free: 18432},
+					{Zone: "zone-2", Total: 8192, Used: 0, Free: 8192},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, model.Simulate(tt.params))
+		})
+	}
+
+	assert.Equal(t, int32(4096), model.Allocators[0].Used, "the model is not modified")
+}
+
+func TestModel_Simulate_clusterTiers(t *testing.T) {
+	var newInstance = func(name, instanceConfiguration string) *models.AllocatedInstanceStatus {
+		return &models.AllocatedInstanceStatus{
+			ClusterID:               ec.String("3ee11eb40eda22cac0cce259625c6734"),
+			InstanceConfigurationID: instanceConfiguration,
+			InstanceName:            ec.String(name),
+			NodeMemory:              ec.Int32(1024),
+		}
+	}
+	var model = NewModelFromOverview(&models.AllocatorOverview{Zones: []*models.AllocatorZoneInfo{{
+		ZoneID: ec.String("zone-1"),
+		Allocators: []*models.AllocatorInfo{
+			newAllocatorInfo("allocator-1", "zone-1", 8192, 1024, true,
+				newInstance("instance-0000000000", "data.default"),
+			),
+			newAllocatorInfo("allocator-2", "zone-1", 8192, 1024, true,
+				newInstance("tiebreaker-0000000001", "master"),
+			),
+			newAllocatorInfo("allocator-3", "zone-1", 4096, 3584, true),
+		},
+	}}})
+
+	assert.Equal(t, &Result{
+		Fits: true,
+		Placements: []Placement{{
+			Instance: Instance{
+				ID:     "instance-0000000000",
+				Memory: 1024,
+				Group:  "3ee11eb40eda22cac0cce259625c6734-data.default",
+				Zone:   "zone-1",
+			},
+			AllocatorID: "allocator-2",
+			Zone:        "zone-1",
+		}},
+		Zones: []ZoneHeadroom{
+			{Zone: "zone-1", Total: 12288, Used: 5632, Free: 6656},
+		},
+	}, model.Simulate(SimulateParams{
+		Instances: model.VacateInstances("allocator-1"),
+		Exclude:   []string{"allocator-1"},
+	}))
+}
@@ -0,0 +1,188 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package configstoreapi
+
+import (
+	"fmt"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+// Action performed on a config store option when applied.
+type Action string
+
+const (
+	// ActionCreate is performed when the option doesn't exist.
+	ActionCreate Action = "create"
+
+	// ActionUpdate is performed when the option value differs.
+	ActionUpdate Action = "update"
+
+	// ActionNone is reported when the option value is unchanged.
+	ActionNone Action = "unchanged"
+)
+
+// Change contains the difference between an option stored in a directory and
+// the live option.
+type Change struct {
+	Name    string
+	Action  Action
+	Current string
+	Desired string
+}
+
+// ApplyReport contains the changes which were computed and, unless DryRun is
+// set, applied by ApplyFromDirectory.
+type ApplyReport struct {
+	Changes []Change
+	DryRun  bool
+}
+
+// Changed returns the changes which create or update an option.
+func (r ApplyReport) Changed() []Change {
+	var changes []Change
+	for _, c := range r.Changes {
+		if c.Action != ActionNone {
+			changes = append(changes, c)
+		}
+	}
+	return changes
+}
+
+// ApplyFromDirectoryParams is used to apply the config store options stored
+// in a local directory.
+type ApplyFromDirectoryParams struct {
+	*api.API
+	Directory string
+	Region    string
+
+	// DryRun computes the changes without applying them.
+	DryRun bool
+
+	// IgnoreVersion updates the options regardless of them having been
+	// modified since they were pulled.
+	IgnoreVersion bool
+}
+
+// Validate ensures that the parameters are correct.
+func (params ApplyFromDirectoryParams) Validate() error {
+	var merr = multierror.NewPrefixed("invalid config store option apply params")
+	if params.API == nil {
+		merr = merr.Append(apierror.ErrMissingAPI)
+	}
+
+	if params.Directory == "" {
+		merr = merr.Append(errDirectoryCannotBeEmpty)
+	}
+
+	if err := ec.RequireRegionSet(params.Region); err != nil {
+		merr = merr.Append(err)
+	}
+
+	return merr.ErrorOrNil()
+}
+
+// ApplyFromDirectory compares the config store options stored in a local
+// folder with the live options, creating the missing options and updating
+// the ones whose value differs. Updates send the version which was stored by
+// PullToDirectory, so an option which has been modified since it was pulled
+// fails with a conflict instead of overwriting the newer value. Live options
+// which aren't in the directory are left untouched.
+func ApplyFromDirectory(params ApplyFromDirectoryParams) (*ApplyReport, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	options, err := ReadDirectory(params.Directory)
+	if err != nil {
+		return nil, err
+	}
+
+	live, err := List(ListParams{API: params.API, Region: params.Region})
+	if err != nil {
+		return nil, err
+	}
+
+	var current = make(map[string]string, len(live))
+	for _, o := range live {
+		if o == nil || o.Name == nil {
+			continue
+		}
+		var value string
+		if o.Value != nil {
+			value = *o.Value
+		}
+		current[*o.Name] = value
+	}
+
+	var report = ApplyReport{DryRun: params.DryRun}
+	var merr = multierror.NewPrefixed("failed applying config store options")
+	for _, option := range options {
+		var change = Change{Name: option.Name, Desired: option.Value}
+		value, ok := current[option.Name]
+		switch {
+		case !ok:
+			change.Action = ActionCreate
+		case value != option.Value:
+			change.Action, change.Current = ActionUpdate, value
+		default:
+			change.Action, change.Current = ActionNone, value
+		}
+		report.Changes = append(report.Changes, change)
+
+		if params.DryRun {
+			continue
+		}
+
+		if err := applyChange(params, option, change); err != nil {
+			merr = merr.Append(fmt.Errorf("option %s: %w", option.Name, err))
+		}
+	}
+
+	return &report, merr.ErrorOrNil()
+}
+
+func applyChange(params ApplyFromDirectoryParams, option Option, change Change) error {
+	switch change.Action {
+	case ActionCreate:
+		_, err := Create(CreateParams{
+			API:    params.API,
+			Region: params.Region,
+			Name:   option.Name,
+			Value:  option.Value,
+		})
+		return err
+	case ActionUpdate:
+		var version = option.Version
+		if params.IgnoreVersion {
+			version = nil
+		}
+		_, err := Update(UpdateParams{
+			API:     params.API,
+			Region:  params.Region,
+			Name:    option.Name,
+			Value:   option.Value,
+			Version: version,
+		})
+		return err
+	}
+	return nil
+}
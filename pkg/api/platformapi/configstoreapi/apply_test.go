@@ -0,0 +1,183 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package configstoreapi
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/api/mock"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+)
+
+func TestApplyFromDirectory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "configstore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var files = map[string]string{
+		"option-a.json": `{"name": "option-a", "value": "a", "version": 1}`,
+		"option-b.json": `{"name": "option-b", "value": "new-b", "version": 5}`,
+		"option-c.json": `{"name": "option-c", "value": "c"}`,
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var changes = []Change{
+		{Name: "option-a", Action: ActionNone, Current: "a", Desired: "a"},
+		{Name: "option-b", Action: ActionUpdate, Current: "b", Desired: "new-b"},
+		{Name: "option-c", Action: ActionCreate, Desired: "c"},
+	}
+	var listResponse = func() mock.Response {
+		return mock.New200ResponseAssertion(&mock.RequestAssertion{
+			Header: api.DefaultReadMockHeaders,
+			Method: "GET",
+			Host:   api.DefaultMockHost,
+			Path:   storePath,
+		}, mock.NewStringBody(listOptionsResponse))
+	}
+
+	tests := []struct {
+		name   string
+		params ApplyFromDirectoryParams
+		want   *ApplyReport
+		err    string
+	}{
+		{
+			name: "fails due to param validation",
+			err: multierror.NewPrefixed("invalid config store option apply params",
+				apierror.ErrMissingAPI,
+				errDirectoryCannotBeEmpty,
+				errors.New("region not specified and is required for this operation"),
+			).Error(),
+		},
+		{
+			name: "fails listing the live options",
+			params: ApplyFromDirectoryParams{
+				Region:    "us-east-1",
+				Directory: dir,
+				API:       api.NewMock(mock.New500Response(mock.NewStringBody(`{"error": "some error"}`))),
+			},
+			err: `{"error": "some error"}`,
+		},
+		{
+			name: "computes the changes on dry run",
+			params: ApplyFromDirectoryParams{
+				Region:    "us-east-1",
+				Directory: dir,
+				DryRun:    true,
+				API:       api.NewMock(listResponse()),
+			},
+			want: &ApplyReport{Changes: changes, DryRun: true},
+		},
+		{
+			name: "applies the changes with the pulled versions",
+			params: ApplyFromDirectoryParams{
+				Region:    "us-east-1",
+				Directory: dir,
+				API: api.NewMock(
+					listResponse(),
+					optionResponse(200, &mock.RequestAssertion{
+						Header: api.DefaultWriteMockHeaders,
+						Method: "PUT",
+						Host:   api.DefaultMockHost,
+						Path:   storePath + "/option-b",
+						Query:  url.Values{"version": {"5"}},
+						Body:   mock.NewStringBody(`{"value":"new-b"}` + "\n"),
+					}, "option-b", "new-b", "6"),
+					optionResponse(201, &mock.RequestAssertion{
+						Header: api.DefaultWriteMockHeaders,
+						Method: "POST",
+						Host:   api.DefaultMockHost,
+						Path:   storePath + "/option-c",
+						Body:   mock.NewStringBody(`{"value":"c"}` + "\n"),
+					}, "option-c", "c", "1"),
+				),
+			},
+			want: &ApplyReport{Changes: changes},
+		},
+		{
+			name: "ignores the pulled versions",
+			params: ApplyFromDirectoryParams{
+				Region:        "us-east-1",
+				Directory:     dir,
+				IgnoreVersion: true,
+				API: api.NewMock(
+					listResponse(),
+					optionResponse(200, &mock.RequestAssertion{
+						Header: api.DefaultWriteMockHeaders,
+						Method: "PUT",
+						Host:   api.DefaultMockHost,
+						Path:   storePath + "/option-b",
+						Body:   mock.NewStringBody(`{"value":"new-b"}` + "\n"),
+					}, "option-b", "new-b", "6"),
+					optionResponse(201, nil, "option-c", "c", "1"),
+				),
+			},
+			want: &ApplyReport{Changes: changes},
+		},
+		{
+			name: "reports the options which fail to be applied",
+			params: ApplyFromDirectoryParams{
+				Region:    "us-east-1",
+				Directory: dir,
+				API: api.NewMock(
+					listResponse(),
+					mock.New500Response(mock.NewStringBody(`{"error": "version conflict"}`)),
+					optionResponse(201, nil, "option-c", "c", "1"),
+				),
+			},
+			want: &ApplyReport{Changes: changes},
+			err: multierror.NewPrefixed("failed applying config store options",
+				errors.New(`option option-b: {"error": "version conflict"}`),
+			).Error(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ApplyFromDirectory(tt.params)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestApplyReport_Changed(t *testing.T) {
+	var report = ApplyReport{Changes: []Change{
+		{Name: "option-a", Action: ActionNone},
+		{Name: "option-b", Action: ActionUpdate},
+	}}
+	assert.Equal(t, []Change{{Name: "option-b", Action: ActionUpdate}}, report.Changed())
+}
@@ -0,0 +1,299 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package configstoreapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/client/platform_infrastructure"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+var (
+	errNameCannotBeEmpty  = errors.New("name not specified and is required for the operation")
+	errValueCannotBeEmpty = errors.New("value not specified and is required for the operation")
+)
+
+// Option is a config store option and its version.
+type Option struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+
+	// Version of the option in the store, it is used to detect concurrent
+	// modifications when the option is updated.
+	Version *int64 `json:"version,omitempty"`
+}
+
+// ListParams is used to list the config store options.
+type ListParams struct {
+	*api.API
+	Region string
+}
+
+// Validate ensures that the parameters are correct.
+func (params ListParams) Validate() error {
+	var merr = multierror.NewPrefixed("invalid config store option list params")
+	if params.API == nil {
+		merr = merr.Append(apierror.ErrMissingAPI)
+	}
+
+	if err := ec.RequireRegionSet(params.Region); err != nil {
+		merr = merr.Append(err)
+	}
+
+	return merr.ErrorOrNil()
+}
+
+// List obtains all of the config store options.
+func List(params ListParams) ([]*models.ConfigStoreOption, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	res, err := params.V1API.PlatformInfrastructure.ListConfigStoreOption(
+		platform_infrastructure.NewListConfigStoreOptionParams().
+			WithContext(api.WithRegion(context.Background(), params.Region)),
+		params.AuthWriter,
+	)
+	if err != nil {
+		return nil, apierror.Unwrap(err)
+	}
+
+	return res.Payload.Values, nil
+}
+
+// GetParams is used to obtain a config store option by name.
+type GetParams struct {
+	*api.API
+	Region string
+	Name   string
+}
+
+// Validate ensures that the parameters are correct.
+func (params GetParams) Validate() error {
+	var merr = multierror.NewPrefixed("invalid config store option get params")
+	if params.API == nil {
+		merr = merr.Append(apierror.ErrMissingAPI)
+	}
+
+	if params.Name == "" {
+		merr = merr.Append(errNameCannotBeEmpty)
+	}
+
+	if err := ec.RequireRegionSet(params.Region); err != nil {
+		merr = merr.Append(err)
+	}
+
+	return merr.ErrorOrNil()
+}
+
+// Get obtains a config store option and its version.
+func Get(params GetParams) (*Option, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	res, err := params.V1API.PlatformInfrastructure.GetConfigStoreOption(
+		platform_infrastructure.NewGetConfigStoreOptionParams().
+			WithContext(api.WithRegion(context.Background(), params.Region)).
+			WithConfigOptionID(params.Name),
+		params.AuthWriter,
+	)
+	if err != nil {
+		return nil, apierror.Unwrap(err)
+	}
+
+	return newOption(params.Name, res.Payload, res.XCloudResourceVersion)
+}
+
+// CreateParams is used to create a config store option.
+type CreateParams struct {
+	*api.API
+	Region string
+	Name   string
+	Value  string
+}
+
+// Validate ensures that the parameters are correct.
+func (params CreateParams) Validate() error {
+	var merr = multierror.NewPrefixed("invalid config store option create params")
+	if params.API == nil {
+		merr = merr.Append(apierror.ErrMissingAPI)
+	}
+
+	if params.Name == "" {
+		merr = merr.Append(errNameCannotBeEmpty)
+	}
+
+	if params.Value == "" {
+		merr = merr.Append(errValueCannotBeEmpty)
+	}
+
+	if err := ec.RequireRegionSet(params.Region); err != nil {
+		merr = merr.Append(err)
+	}
+
+	return merr.ErrorOrNil()
+}
+
+// Create creates a config store option.
+func Create(params CreateParams) (*Option, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	res, err := params.V1API.PlatformInfrastructure.CreateConfigStoreOption(
+		platform_infrastructure.NewCreateConfigStoreOptionParams().
+			WithContext(api.WithRegion(context.Background(), params.Region)).
+			WithConfigOptionID(params.Name).
+			WithBody(&models.ConfigStoreOptionData{Value: ec.String(params.Value)}),
+		params.AuthWriter,
+	)
+	if err != nil {
+		return nil, apierror.Unwrap(err)
+	}
+
+	return newOption(params.Name, res.Payload, res.XCloudResourceVersion)
+}
+
+// UpdateParams is used to update a config store option.
+type UpdateParams struct {
+	*api.API
+	Region string
+	Name   string
+	Value  string
+
+	// Optional version of the option, the update fails with a conflict when
+	// it doesn't match the stored version.
+	Version *int64
+}
+
+// Validate ensures that the parameters are correct.
+func (params UpdateParams) Validate() error {
+	var merr = multierror.NewPrefixed("invalid config store option update params")
+	if params.API == nil {
+		merr = merr.Append(apierror.ErrMissingAPI)
+	}
+
+	if params.Name == "" {
+		merr = merr.Append(errNameCannotBeEmpty)
+	}
+
+	if params.Value == "" {
+		merr = merr.Append(errValueCannotBeEmpty)
+	}
+
+	if err := ec.RequireRegionSet(params.Region); err != nil {
+		merr = merr.Append(err)
+	}
+
+	return merr.ErrorOrNil()
+}
+
+// Update updates the value of a config store option.
+func Update(params UpdateParams) (*Option, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	res, err := params.V1API.PlatformInfrastructure.PutConfigStoreOption(
+		platform_infrastructure.NewPutConfigStoreOptionParams().
+			WithContext(api.WithRegion(context.Background(), params.Region)).
+			WithConfigOptionID(params.Name).
+			WithVersion(params.Version).
+			WithBody(&models.ConfigStoreOptionData{Value: ec.String(params.Value)}),
+		params.AuthWriter,
+	)
+	if err != nil {
+		return nil, apierror.Unwrap(err)
+	}
+
+	return newOption(params.Name, res.Payload, res.XCloudResourceVersion)
+}
+
+// DeleteParams is used to delete a config store option.
+type DeleteParams struct {
+	*api.API
+	Region string
+	Name   string
+}
+
+// Validate ensures that the parameters are correct.
+func (params DeleteParams) Validate() error {
+	var merr = multierror.NewPrefixed("invalid config store option delete params")
+	if params.API == nil {
+		merr = merr.Append(apierror.ErrMissingAPI)
+	}
+
+	if params.Name == "" {
+		merr = merr.Append(errNameCannotBeEmpty)
+	}
+
+	if err := ec.RequireRegionSet(params.Region); err != nil {
+		merr = merr.Append(err)
+	}
+
+	return merr.ErrorOrNil()
+}
+
+// Delete deletes a config store option.
+func Delete(params DeleteParams) error {
+	if err := params.Validate(); err != nil {
+		return err
+	}
+
+	return api.ReturnErrOnly(
+		params.V1API.PlatformInfrastructure.DeleteConfigStoreOption(
+			platform_infrastructure.NewDeleteConfigStoreOptionParams().
+				WithContext(api.WithRegion(context.Background(), params.Region)).
+				WithConfigOptionID(params.Name),
+			params.AuthWriter,
+		),
+	)
+}
+
+func newOption(name string, option *models.ConfigStoreOption, version string) (*Option, error) {
+	var res = Option{Name: name}
+	if option != nil {
+		if option.Name != nil {
+			res.Name = *option.Name
+		}
+		if option.Value != nil {
+			res.Value = *option.Value
+		}
+	}
+
+	if version == "" {
+		return &res, nil
+	}
+
+	v, err := strconv.ParseInt(version, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config store option version %s: %s", version, err)
+	}
+	res.Version = &v
+
+	return &res, nil
+}
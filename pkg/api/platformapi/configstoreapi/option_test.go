@@ -0,0 +1,313 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package configstoreapi
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/api/mock"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+const storePath = "/api/v1/regions/us-east-1/platform/configuration/store"
+
+func optionResponse(code int, assertion *mock.RequestAssertion, name, value, version string) mock.Response {
+	var header = http.Header{}
+	if version != "" {
+		header.Set("X-Cloud-Resource-Version", version)
+	}
+	return mock.Response{
+		Response: http.Response{
+			StatusCode: code,
+			Header:     header,
+			Body: mock.NewStructBody(models.ConfigStoreOption{
+				Name:    ec.String(name),
+				Value:   ec.String(value),
+				Changed: ec.Bool(false),
+			}),
+		},
+		Assert: assertion,
+	}
+}
+
+func TestList(t *testing.T) {
+	tests := []struct {
+		name   string
+		params ListParams
+		want   []*models.ConfigStoreOption
+		err    error
+	}{
+		{
+			name: "fails due to parameter validation",
+			err: multierror.NewPrefixed("invalid config store option list params",
+				apierror.ErrMissingAPI,
+				errors.New("region not specified and is required for this operation"),
+			),
+		},
+		{
+			name: "fails due to API failure",
+			params: ListParams{
+				Region: "us-east-1",
+				API:    api.NewMock(mock.New500Response(mock.NewStringBody(`{"error": "some error"}`))),
+			},
+			err: errors.New(`{"error": "some error"}`),
+		},
+		{
+			name: "succeeds",
+			params: ListParams{
+				Region: "us-east-1",
+				API: api.NewMock(mock.New200ResponseAssertion(&mock.RequestAssertion{
+					Header: api.DefaultReadMockHeaders,
+					Method: "GET",
+					Host:   api.DefaultMockHost,
+					Path:   storePath,
+				}, mock.NewStringBody(`{"values": [{"name": "option", "value": "a", "changed": false}]}`))),
+			},
+			want: []*models.ConfigStoreOption{
+				{Name: ec.String("option"), Value: ec.String("a"), Changed: ec.Bool(false)},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := List(tt.params)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGet(t *testing.T) {
+	tests := []struct {
+		name   string
+		params GetParams
+		want   *Option
+		err    string
+	}{
+		{
+			name: "fails due to parameter validation",
+			err: multierror.NewPrefixed("invalid config store option get params",
+				apierror.ErrMissingAPI,
+				errNameCannotBeEmpty,
+				errors.New("region not specified and is required for this operation"),
+			).Error(),
+		},
+		{
+			name: "fails due to API failure",
+			params: GetParams{
+				Name:   "option",
+				Region: "us-east-1",
+				API:    api.NewMock(mock.New500Response(mock.NewStringBody(`{"error": "some error"}`))),
+			},
+			err: `{"error": "some error"}`,
+		},
+		{
+			name: "fails due to an invalid version",
+			params: GetParams{
+				Name:   "option",
+				Region: "us-east-1",
+				API:    api.NewMock(optionResponse(200, nil, "option", "a", "x")),
+			},
+			err: `invalid config store option version x: strconv.ParseInt: parsing "x": invalid syntax`,
+		},
+		{
+			name: "succeeds",
+			params: GetParams{
+				Name:   "option",
+				Region: "us-east-1",
+				API: api.NewMock(optionResponse(200, &mock.RequestAssertion{
+					Header: api.DefaultReadMockHeaders,
+					Method: "GET",
+					Host:   api.DefaultMockHost,
+					Path:   storePath + "/option",
+				}, "option", "a", "2")),
+			},
+			want: &Option{Name: "option", Value: "a", Version: ec.Int64(2)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Get(tt.params)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	tests := []struct {
+		name   string
+		params CreateParams
+		want   *Option
+		err    error
+	}{
+		{
+			name: "fails due to parameter validation",
+			err: multierror.NewPrefixed("invalid config store option create params",
+				apierror.ErrMissingAPI,
+				errNameCannotBeEmpty,
+				errValueCannotBeEmpty,
+				errors.New("region not specified and is required for this operation"),
+			),
+		},
+		{
+			name: "fails due to API failure",
+			params: CreateParams{
+				Name:   "option",
+				Value:  "a",
+				Region: "us-east-1",
+				API:    api.NewMock(mock.New500Response(mock.NewStringBody(`{"error": "some error"}`))),
+			},
+			err: errors.New(`{"error": "some error"}`),
+		},
+		{
+			name: "succeeds",
+			params: CreateParams{
+				Name:   "option",
+				Value:  "a",
+				Region: "us-east-1",
+				API: api.NewMock(optionResponse(201, &mock.RequestAssertion{
+					Header: api.DefaultWriteMockHeaders,
+					Method: "POST",
+					Host:   api.DefaultMockHost,
+					Path:   storePath + "/option",
+					Body:   mock.NewStringBody(`{"value":"a"}` + "\n"),
+				}, "option", "a", "1")),
+			},
+			want: &Option{Name: "option", Value: "a", Version: ec.Int64(1)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Create(tt.params)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	tests := []struct {
+		name   string
+		params UpdateParams
+		want   *Option
+		err    error
+	}{
+		{
+			name: "fails due to parameter validation",
+			err: multierror.NewPrefixed("invalid config store option update params",
+				apierror.ErrMissingAPI,
+				errNameCannotBeEmpty,
+				errValueCannotBeEmpty,
+				errors.New("region not specified and is required for this operation"),
+			),
+		},
+		{
+			name: "fails due to API failure",
+			params: UpdateParams{
+				Name:   "option",
+				Value:  "a",
+				Region: "us-east-1",
+				API:    api.NewMock(mock.New500Response(mock.NewStringBody(`{"error": "some error"}`))),
+			},
+			err: errors.New(`{"error": "some error"}`),
+		},
+		{
+			name: "succeeds",
+			params: UpdateParams{
+				Name:    "option",
+				Value:   "b",
+				Version: ec.Int64(2),
+				Region:  "us-east-1",
+				API: api.NewMock(optionResponse(200, &mock.RequestAssertion{
+					Header: api.DefaultWriteMockHeaders,
+					Method: "PUT",
+					Host:   api.DefaultMockHost,
+					Path:   storePath + "/option",
+					Query:  url.Values{"version": {"2"}},
+					Body:   mock.NewStringBody(`{"value":"b"}` + "\n"),
+				}, "option", "b", "3")),
+			},
+			want: &Option{Name: "option", Value: "b", Version: ec.Int64(3)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Update(tt.params)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	tests := []struct {
+		name   string
+		params DeleteParams
+		err    error
+	}{
+		{
+			name: "fails due to parameter validation",
+			err: multierror.NewPrefixed("invalid config store option delete params",
+				apierror.ErrMissingAPI,
+				errNameCannotBeEmpty,
+				errors.New("region not specified and is required for this operation"),
+			),
+		},
+		{
+			name: "fails due to API failure",
+			params: DeleteParams{
+				Name:   "option",
+				Region: "us-east-1",
+				API:    api.NewMock(mock.New500Response(mock.NewStringBody(`{"error": "some error"}`))),
+			},
+			err: errors.New(`{"error": "some error"}`),
+		},
+		{
+			name: "succeeds",
+			params: DeleteParams{
+				Name:   "option",
+				Region: "us-east-1",
+				API: api.NewMock(mock.New200ResponseAssertion(&mock.RequestAssertion{
+					Header: api.DefaultWriteMockHeaders,
+					Method: "DELETE",
+					Host:   api.DefaultMockHost,
+					Path:   storePath + "/option",
+				}, mock.NewStringBody(`{}`))),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.err, Delete(tt.params))
+		})
+	}
+}
@@ -0,0 +1,161 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package configstoreapi
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+var errDirectoryCannotBeEmpty = errors.New("folder not specified and is required for the operation")
+
+// PullToDirectoryParams is used to store all of the config store options in a
+// local directory.
+type PullToDirectoryParams struct {
+	*api.API
+	Directory string
+	Region    string
+}
+
+// Validate ensures that the parameters are correct.
+func (params PullToDirectoryParams) Validate() error {
+	var merr = multierror.NewPrefixed("invalid config store option pull params")
+	if params.API == nil {
+		merr = merr.Append(apierror.ErrMissingAPI)
+	}
+
+	if params.Directory == "" {
+		merr = merr.Append(errDirectoryCannotBeEmpty)
+	}
+
+	if err := ec.RequireRegionSet(params.Region); err != nil {
+		merr = merr.Append(err)
+	}
+
+	return merr.ErrorOrNil()
+}
+
+// PullToDirectory downloads the config store options with their versions and
+// saves them in a local folder. The stored versions are used by
+// ApplyFromDirectory to detect options which have been modified since the
+// pull.
+func PullToDirectory(params PullToDirectoryParams) error {
+	if err := params.Validate(); err != nil {
+		return err
+	}
+
+	res, err := List(ListParams{API: params.API, Region: params.Region})
+	if err != nil {
+		return err
+	}
+
+	var options = make([]*Option, 0, len(res))
+	for _, o := range res {
+		if o == nil || o.Name == nil {
+			continue
+		}
+
+		option, err := Get(GetParams{API: params.API, Region: params.Region, Name: *o.Name})
+		if err != nil {
+			return err
+		}
+		options = append(options, option)
+	}
+
+	return writeOptionsToDirectory(params.Directory, options)
+}
+
+// writeOptionsToDirectory writes all the config store options to a folder
+// following this structure:
+//
+//	folder/
+//	folder/name.json
+func writeOptionsToDirectory(folder string, options []*Option) error {
+	if err := os.MkdirAll(folder, os.ModePerm); err != nil {
+		return err
+	}
+
+	var merr = multierror.NewPrefixed("failed persisting config store options")
+	for _, option := range options {
+		f, err := os.Create(filepath.Join(folder, option.Name+".json"))
+		if err != nil {
+			merr = merr.Append(err)
+			continue
+		}
+
+		var enc = json.NewEncoder(f)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(option); err != nil {
+			merr = merr.Append(err)
+		}
+
+		if err := f.Close(); err != nil {
+			merr = merr.Append(err)
+		}
+	}
+
+	return merr.ErrorOrNil()
+}
+
+// ReadDirectory reads the config store options stored in a local folder by
+// PullToDirectory, sorted by name.
+func ReadDirectory(folder string) ([]Option, error) {
+	matches, err := filepath.Glob(filepath.Join(folder, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var merr = multierror.NewPrefixed("failed reading config store options")
+	var options = make([]Option, 0, len(matches))
+	for _, m := range matches {
+		f, err := os.Open(m)
+		if err != nil {
+			merr = merr.Append(err)
+			continue
+		}
+
+		var option Option
+		if err := json.NewDecoder(f).Decode(&option); err != nil {
+			merr = merr.Append(err)
+		}
+		f.Close()
+
+		if option.Name == "" {
+			option.Name = trimExt(filepath.Base(m))
+		}
+		options = append(options, option)
+	}
+
+	sort.SliceStable(options, func(i, j int) bool {
+		return options[i].Name < options[j].Name
+	})
+
+	return options, merr.ErrorOrNil()
+}
+
+func trimExt(name string) string {
+	return name[:len(name)-len(filepath.Ext(name))]
+}
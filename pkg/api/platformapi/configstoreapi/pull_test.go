@@ -0,0 +1,150 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package configstoreapi
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/api/mock"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+const listOptionsResponse = `{"values": [
+  {"name": "option-a", "value": "a", "changed": false},
+  {"name": "option-b", "value": "b", "changed": true}
+]}`
+
+func TestPullToDirectory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "configstore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name   string
+		params PullToDirectoryParams
+		want   map[string]string
+		err    error
+	}{
+		{
+			name: "fails due to param validation",
+			err: multierror.NewPrefixed("invalid config store option pull params",
+				apierror.ErrMissingAPI,
+				errDirectoryCannotBeEmpty,
+				errors.New("region not specified and is required for this operation"),
+			),
+		},
+		{
+			name: "fails obtaining an option",
+			params: PullToDirectoryParams{
+				Region:    "us-east-1",
+				Directory: filepath.Join(dir, "failed"),
+				API: api.NewMock(
+					mock.New200Response(mock.NewStringBody(listOptionsResponse)),
+					mock.New500Response(mock.NewStringBody(`{"error": "some error"}`)),
+				),
+			},
+			err: errors.New(`{"error": "some error"}`),
+		},
+		{
+			name: "pulls the options with their versions",
+			params: PullToDirectoryParams{
+				Region:    "us-east-1",
+				Directory: filepath.Join(dir, "options"),
+				API: api.NewMock(
+					mock.New200Response(mock.NewStringBody(listOptionsResponse)),
+					optionResponse(200, &mock.RequestAssertion{
+						Header: api.DefaultReadMockHeaders,
+						Method: "GET",
+						Host:   api.DefaultMockHost,
+						Path:   storePath + "/option-a",
+					}, "option-a", "a", "1"),
+					optionResponse(200, &mock.RequestAssertion{
+						Header: api.DefaultReadMockHeaders,
+						Method: "GET",
+						Host:   api.DefaultMockHost,
+						Path:   storePath + "/option-b",
+					}, "option-b", "b", "5"),
+				),
+			},
+			want: map[string]string{
+				"option-a.json": "{\n  \"name\": \"option-a\",\n  \"value\": \"a\",\n  \"version\": 1\n}\n",
+				"option-b.json": "{\n  \"name\": \"option-b\",\n  \"value\": \"b\",\n  \"version\": 5\n}\n",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := PullToDirectory(tt.params)
+			assert.Equal(t, tt.err, err)
+			if tt.want == nil {
+				return
+			}
+
+			matches, err := filepath.Glob(filepath.Join(tt.params.Directory, "*.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got = make(map[string]string, len(matches))
+			for _, m := range matches {
+				b, err := ioutil.ReadFile(m)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got[filepath.Base(m)] = string(b)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestReadDirectory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "configstore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var files = map[string]string{
+		"option-b.json": `{"name": "option-b", "value": "b", "version": 5}`,
+		"option-a.json": `{"value": "a"}`,
+		"ignored.txt":   `not an option`,
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := ReadDirectory(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, []Option{
+		{Name: "option-a", Value: "a"},
+		{Name: "option-b", Value: "b", Version: ec.Int64(5)},
+	}, got)
+}
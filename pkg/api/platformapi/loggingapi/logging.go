@@ -0,0 +1,309 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package loggingapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/client/platform_infrastructure"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+)
+
+// GetParams is consumed by Get.
+type GetParams struct {
+	Params
+}
+
+// Validate ensures the parameters are usable by Get.
+func (params GetParams) Validate() error {
+	return multierror.NewPrefixed("invalid logging settings get params",
+		params.errors()...,
+	).ErrorOrNil()
+}
+
+// Get obtains the logging settings of a component instance.
+func Get(params GetParams) (*models.LoggingSettings, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	var ctx = api.WithRegion(context.Background(), params.Region)
+	var infra = params.V1API.PlatformInfrastructure
+	switch params.Component {
+	case Adminconsole:
+		res, err := infra.GetAdminconsoleLoggingSettings(
+			platform_infrastructure.NewGetAdminconsoleLoggingSettingsParams().
+				WithContext(ctx).WithAdminconsoleID(params.ID),
+			params.AuthWriter,
+		)
+		if err != nil {
+			return nil, api.UnwrapError(err)
+		}
+		return res.Payload, nil
+	case Allocator:
+		res, err := infra.GetAllocatorLoggingSettings(
+			platform_infrastructure.NewGetAllocatorLoggingSettingsParams().
+				WithContext(ctx).WithAllocatorID(params.ID),
+			params.AuthWriter,
+		)
+		if err != nil {
+			return nil, api.UnwrapError(err)
+		}
+		return res.Payload, nil
+	case Constructor:
+		res, err := infra.GetConstructorLoggingSettings(
+			platform_infrastructure.NewGetConstructorLoggingSettingsParams().
+				WithContext(ctx).WithConstructorID(params.ID),
+			params.AuthWriter,
+		)
+		if err != nil {
+			return nil, api.UnwrapError(err)
+		}
+		return res.Payload, nil
+	default:
+		res, err := infra.GetRunnerLoggingSettings(
+			platform_infrastructure.NewGetRunnerLoggingSettingsParams().
+				WithContext(ctx).WithRunnerID(params.ID),
+			params.AuthWriter,
+		)
+		if err != nil {
+			return nil, api.UnwrapError(err)
+		}
+		return res.Payload, nil
+	}
+}
+
+// SetParams is consumed by Set.
+type SetParams struct {
+	Params
+
+	// Levels replaces all of the component's logging levels.
+	Levels map[string]string
+}
+
+// Validate ensures the parameters are usable by Set.
+func (params SetParams) Validate() error {
+	return multierror.NewPrefixed("invalid logging settings set params",
+		params.errors()...,
+	).ErrorOrNil()
+}
+
+// Set replaces the logging settings of a component instance with the
+// specified levels.
+func Set(params SetParams) (*models.LoggingSettings, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	var ctx = api.WithRegion(context.Background(), params.Region)
+	var infra = params.V1API.PlatformInfrastructure
+	var body = &models.LoggingSettings{LoggingLevels: params.Levels}
+	if body.LoggingLevels == nil {
+		body.LoggingLevels = make(map[string]string)
+	}
+
+	switch params.Component {
+	case Adminconsole:
+		res, err := infra.SetAdminconsoleLoggingSettings(
+			platform_infrastructure.NewSetAdminconsoleLoggingSettingsParams().
+				WithContext(ctx).WithAdminconsoleID(params.ID).WithBody(body),
+			params.AuthWriter,
+		)
+		if err != nil {
+			return nil, api.UnwrapError(err)
+		}
+		return res.Payload, nil
+	case Allocator:
+		res, err := infra.SetAllocatorLoggingSettings(
+			platform_infrastructure.NewSetAllocatorLoggingSettingsParams().
+				WithContext(ctx).WithAllocatorID(params.ID).WithBody(body),
+			params.AuthWriter,
+		)
+		if err != nil {
+			return nil, api.UnwrapError(err)
+		}
+		return res.Payload, nil
+	case Constructor:
+		res, err := infra.SetConstructorLoggingSettings(
+			platform_infrastructure.NewSetConstructorLoggingSettingsParams().
+				WithContext(ctx).WithConstructorID(params.ID).WithBody(body),
+			params.AuthWriter,
+		)
+		if err != nil {
+			return nil, api.UnwrapError(err)
+		}
+		return res.Payload, nil
+	default:
+		res, err := infra.SetRunnerLoggingSettings(
+			platform_infrastructure.NewSetRunnerLoggingSettingsParams().
+				WithContext(ctx).WithRunnerID(params.ID).WithBody(body),
+			params.AuthWriter,
+		)
+		if err != nil {
+			return nil, api.UnwrapError(err)
+		}
+		return res.Payload, nil
+	}
+}
+
+// UpdateParams is consumed by Update.
+type UpdateParams struct {
+	Params
+
+	// Levels to update by logger name, i.e. {"root": "DEBUG"}.
+	Levels map[string]string
+}
+
+// Validate ensures the parameters are usable by Update.
+func (params UpdateParams) Validate() error {
+	var merr = multierror.NewPrefixed("invalid logging settings update params",
+		params.errors()...,
+	)
+	if len(params.Levels) == 0 {
+		merr = merr.Append(errors.New("at least one logging level must be specified"))
+	}
+	return merr.ErrorOrNil()
+}
+
+// Update modifies the specified logging levels of a component instance,
+// leaving any other levels untouched.
+func Update(params UpdateParams) (*models.LoggingSettings, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	b, err := json.Marshal(models.LoggingSettings{LoggingLevels: params.Levels})
+	if err != nil {
+		return nil, err
+	}
+
+	var ctx = api.WithRegion(context.Background(), params.Region)
+	var infra = params.V1API.PlatformInfrastructure
+	var body = string(b)
+	switch params.Component {
+	case Adminconsole:
+		res, err := infra.UpdateAdminconsoleLoggingSettings(
+			platform_infrastructure.NewUpdateAdminconsoleLoggingSettingsParams().
+				WithContext(ctx).WithAdminconsoleID(params.ID).WithBody(body),
+			params.AuthWriter,
+		)
+		if err != nil {
+			return nil, api.UnwrapError(err)
+		}
+		return res.Payload, nil
+	case Allocator:
+		res, err := infra.UpdateAllocatorLoggingSettings(
+			platform_infrastructure.NewUpdateAllocatorLoggingSettingsParams().
+				WithContext(ctx).WithAllocatorID(params.ID).WithBody(body),
+			params.AuthWriter,
+		)
+		if err != nil {
+			return nil, api.UnwrapError(err)
+		}
+		return res.Payload, nil
+	case Constructor:
+		res, err := infra.UpdateConstructorLoggingSettings(
+			platform_infrastructure.NewUpdateConstructorLoggingSettingsParams().
+				WithContext(ctx).WithConstructorID(params.ID).WithBody(body),
+			params.AuthWriter,
+		)
+		if err != nil {
+			return nil, api.UnwrapError(err)
+		}
+		return res.Payload, nil
+	default:
+		res, err := infra.UpdateRunnerLoggingSettings(
+			platform_infrastructure.NewUpdateRunnerLoggingSettingsParams().
+				WithContext(ctx).WithRunnerID(params.ID).WithBody(body),
+			params.AuthWriter,
+		)
+		if err != nil {
+			return nil, api.UnwrapError(err)
+		}
+		return res.Payload, nil
+	}
+}
+
+// ResetParams is consumed by Reset.
+type ResetParams struct {
+	Params
+}
+
+// Validate ensures the parameters are usable by Reset.
+func (params ResetParams) Validate() error {
+	return multierror.NewPrefixed("invalid logging settings reset params",
+		params.errors()...,
+	).ErrorOrNil()
+}
+
+// Reset deletes any logging settings of a component instance, which reverts
+// its logging levels to the defaults.
+func Reset(params ResetParams) (*models.LoggingSettings, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	var ctx = api.WithRegion(context.Background(), params.Region)
+	var infra = params.V1API.PlatformInfrastructure
+	switch params.Component {
+	case Adminconsole:
+		res, err := infra.DeleteAdminconsoleLoggingSettings(
+			platform_infrastructure.NewDeleteAdminconsoleLoggingSettingsParams().
+				WithContext(ctx).WithAdminconsoleID(params.ID),
+			params.AuthWriter,
+		)
+		if err != nil {
+			return nil, api.UnwrapError(err)
+		}
+		return res.Payload, nil
+	case Allocator:
+		res, err := infra.DeleteAllocatorLoggingSettings(
+			platform_infrastructure.NewDeleteAllocatorLoggingSettingsParams().
+				WithContext(ctx).WithAllocatorID(params.ID),
+			params.AuthWriter,
+		)
+		if err != nil {
+			return nil, api.UnwrapError(err)
+		}
+		return res.Payload, nil
+	case Constructor:
+		res, err := infra.DeleteConstructorLoggingSettings(
+			platform_infrastructure.NewDeleteConstructorLoggingSettingsParams().
+				WithContext(ctx).WithConstructorID(params.ID),
+			params.AuthWriter,
+		)
+		if err != nil {
+			return nil, api.UnwrapError(err)
+		}
+		return res.Payload, nil
+	default:
+		res, err := infra.DeleteRunnerLoggingSettings(
+			platform_infrastructure.NewDeleteRunnerLoggingSettingsParams().
+				WithContext(ctx).WithRunnerID(params.ID),
+			params.AuthWriter,
+		)
+		if err != nil {
+			return nil, api.UnwrapError(err)
+		}
+		return res.Payload, nil
+	}
+}
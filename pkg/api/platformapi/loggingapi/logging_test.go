@@ -0,0 +1,289 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package loggingapi
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/api/mock"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+)
+
+func loggingSettingsPath(c Component, id string) string {
+	return fmt.Sprintf(
+		"/api/v1/regions/us-east-1/platform/infrastructure/%ss/%s/logging_settings", c, id,
+	)
+}
+
+func TestGet(t *testing.T) {
+	tests := []struct {
+		name   string
+		params GetParams
+		want   *models.LoggingSettings
+		err    error
+	}{
+		{
+			name: "fails due to parameter validation",
+			params: GetParams{Params: Params{
+				Component: "something",
+			}},
+			err: multierror.NewPrefixed("invalid logging settings get params",
+				apierror.ErrMissingAPI,
+				errors.New(`invalid component "something", valid components are [adminconsole allocator constructor runner]`),
+				errors.New("id cannot be empty"),
+				errors.New("region not specified and is required for this operation"),
+			),
+		},
+		{
+			name: "fails due to API failure",
+			params: GetParams{Params: Params{
+				Component: Runner,
+				ID:        "192.168.44.10",
+				Region:    "us-east-1",
+				API:       api.NewMock(mock.New500Response(mock.NewStringBody(`{"error": "some error"}`))),
+			}},
+			err: errors.New(`{"error": "some error"}`),
+		},
+	}
+	for _, c := range Components {
+		tests = append(tests, struct {
+			name   string
+			params GetParams
+			want   *models.LoggingSettings
+			err    error
+		}{
+			name: fmt.Sprintf("succeeds for %s", c),
+			params: GetParams{Params: Params{
+				Component: c,
+				ID:        "an-id",
+				Region:    "us-east-1",
+				API: api.NewMock(mock.New200ResponseAssertion(&mock.RequestAssertion{
+					Header: api.DefaultReadMockHeaders,
+					Method: "GET",
+					Host:   api.DefaultMockHost,
+					Path:   loggingSettingsPath(c, "an-id"),
+				}, mock.NewStringBody(`{"logging_levels": {"root": "INFO"}}`))),
+			}},
+			want: &models.LoggingSettings{LoggingLevels: map[string]string{"root": "INFO"}},
+		})
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Get(tt.params)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSet(t *testing.T) {
+	tests := []struct {
+		name   string
+		params SetParams
+		want   *models.LoggingSettings
+		err    error
+	}{
+		{
+			name: "fails due to parameter validation",
+			err: multierror.NewPrefixed("invalid logging settings set params",
+				apierror.ErrMissingAPI,
+				errors.New(`invalid component "", valid components are [adminconsole allocator constructor runner]`),
+				errors.New("id cannot be empty"),
+				errors.New("region not specified and is required for this operation"),
+			),
+		},
+		{
+			name: "fails due to API failure",
+			params: SetParams{Params: Params{
+				Component: Allocator,
+				ID:        "an-id",
+				Region:    "us-east-1",
+				API:       api.NewMock(mock.New500Response(mock.NewStringBody(`{"error": "some error"}`))),
+			}},
+			err: errors.New(`{"error": "some error"}`),
+		},
+	}
+	for _, c := range Components {
+		tests = append(tests, struct {
+			name   string
+			params SetParams
+			want   *models.LoggingSettings
+			err    error
+		}{
+			name: fmt.Sprintf("succeeds for %s", c),
+			params: SetParams{
+				Levels: map[string]string{"root": "WARN"},
+				Params: Params{
+					Component: c,
+					ID:        "an-id",
+					Region:    "us-east-1",
+					API: api.NewMock(mock.New200ResponseAssertion(&mock.RequestAssertion{
+						Header: api.DefaultWriteMockHeaders,
+						Method: "PUT",
+						Host:   api.DefaultMockHost,
+						Path:   loggingSettingsPath(c, "an-id"),
+						Body:   mock.NewStringBody(`{"logging_levels":{"root":"WARN"}}` + "\n"),
+					}, mock.NewStringBody(`{"logging_levels": {"root": "WARN"}}`))),
+				},
+			},
+			want: &models.LoggingSettings{LoggingLevels: map[string]string{"root": "WARN"}},
+		})
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Set(tt.params)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	tests := []struct {
+		name   string
+		params UpdateParams
+		want   *models.LoggingSettings
+		err    error
+	}{
+		{
+			name: "fails due to parameter validation",
+			params: UpdateParams{Params: Params{
+				Component: Constructor,
+			}},
+			err: multierror.NewPrefixed("invalid logging settings update params",
+				apierror.ErrMissingAPI,
+				errors.New("id cannot be empty"),
+				errors.New("region not specified and is required for this operation"),
+				errors.New("at least one logging level must be specified"),
+			),
+		},
+		{
+			name: "fails due to API failure",
+			params: UpdateParams{
+				Levels: map[string]string{"root": "DEBUG"},
+				Params: Params{
+					Component: Constructor,
+					ID:        "an-id",
+					Region:    "us-east-1",
+					API:       api.NewMock(mock.New500Response(mock.NewStringBody(`{"error": "some error"}`))),
+				},
+			},
+			err: errors.New(`{"error": "some error"}`),
+		},
+	}
+	for _, c := range Components {
+		tests = append(tests, struct {
+			name   string
+			params UpdateParams
+			want   *models.LoggingSettings
+			err    error
+		}{
+			name: fmt.Sprintf("succeeds for %s", c),
+			params: UpdateParams{
+				Levels: map[string]string{"root": "DEBUG"},
+				Params: Params{
+					Component: c,
+					ID:        "an-id",
+					Region:    "us-east-1",
+					API: api.NewMock(mock.New200ResponseAssertion(&mock.RequestAssertion{
+						Header: api.DefaultWriteMockHeaders,
+						Method: "PATCH",
+						Host:   api.DefaultMockHost,
+						Path:   loggingSettingsPath(c, "an-id"),
+						Body:   mock.NewStringBody(`"{\"logging_levels\":{\"root\":\"DEBUG\"}}"` + "\n"),
+					}, mock.NewStringBody(`{"logging_levels": {"root": "DEBUG"}}`))),
+				},
+			},
+			want: &models.LoggingSettings{LoggingLevels: map[string]string{"root": "DEBUG"}},
+		})
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Update(tt.params)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestReset(t *testing.T) {
+	tests := []struct {
+		name   string
+		params ResetParams
+		want   *models.LoggingSettings
+		err    error
+	}{
+		{
+			name: "fails due to parameter validation",
+			params: ResetParams{Params: Params{
+				Component: Adminconsole,
+				ID:        "an-id",
+			}},
+			err: multierror.NewPrefixed("invalid logging settings reset params",
+				apierror.ErrMissingAPI,
+				errors.New("region not specified and is required for this operation"),
+			),
+		},
+		{
+			name: "fails due to API failure",
+			params: ResetParams{Params: Params{
+				Component: Adminconsole,
+				ID:        "an-id",
+				Region:    "us-east-1",
+				API:       api.NewMock(mock.New500Response(mock.NewStringBody(`{"error": "some error"}`))),
+			}},
+			err: errors.New(`{"error": "some error"}`),
+		},
+	}
+	for _, c := range Components {
+		tests = append(tests, struct {
+			name   string
+			params ResetParams
+			want   *models.LoggingSettings
+			err    error
+		}{
+			name: fmt.Sprintf("succeeds for %s", c),
+			params: ResetParams{Params: Params{
+				Component: c,
+				ID:        "an-id",
+				Region:    "us-east-1",
+				API: api.NewMock(mock.New200ResponseAssertion(&mock.RequestAssertion{
+					Header: api.DefaultWriteMockHeaders,
+					Method: "DELETE",
+					Host:   api.DefaultMockHost,
+					Path:   loggingSettingsPath(c, "an-id"),
+				}, mock.NewStringBody(`{"logging_levels": {}}`))),
+			}},
+			want: &models.LoggingSettings{LoggingLevels: map[string]string{}},
+		})
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Reset(tt.params)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
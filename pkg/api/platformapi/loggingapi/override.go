@@ -0,0 +1,150 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package loggingapi
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+)
+
+// OverrideParams is consumed by Override.
+type OverrideParams struct {
+	Params
+
+	// Levels to temporarily set by logger name, i.e. {"root": "DEBUG"}.
+	Levels map[string]string
+
+	// Optional time after which the previous logging levels are restored
+	// automatically. When not set, the levels are only restored through
+	// Restorer.Restore.
+	TTL time.Duration
+}
+
+// Validate ensures the parameters are usable by Override.
+func (params OverrideParams) Validate() error {
+	var merr = multierror.NewPrefixed("invalid logging settings override params",
+		params.errors()...,
+	)
+	if len(params.Levels) == 0 {
+		merr = merr.Append(errors.New("at least one logging level must be specified"))
+	}
+	if params.TTL < 0 {
+		merr = merr.Append(errors.New("ttl cannot be negative"))
+	}
+	return merr.ErrorOrNil()
+}
+
+// Restorer is returned by Override and restores the logging levels which
+// were set before the override took place.
+type Restorer struct {
+	params   Params
+	previous map[string]string
+	timer    *time.Timer
+	once     sync.Once
+	done     chan struct{}
+	err      error
+}
+
+// Override reads the current logging levels of a component instance and
+// updates them with the specified levels. The returned Restorer sets the
+// previous levels back, or resets the logging settings when there were none,
+// either when Restore is called or once the TTL has elapsed, whichever
+// happens first.
+func Override(params OverrideParams) (*Restorer, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	current, err := Get(GetParams{Params: params.Params})
+	if err != nil {
+		return nil, err
+	}
+
+	var previous = make(map[string]string, len(current.LoggingLevels))
+	for k, v := range current.LoggingLevels {
+		previous[k] = v
+	}
+
+	if _, err := Update(UpdateParams{
+		Params: params.Params,
+		Levels: params.Levels,
+	}); err != nil {
+		return nil, err
+	}
+
+	var r = Restorer{
+		params:   params.Params,
+		previous: previous,
+		done:     make(chan struct{}),
+	}
+
+	if params.TTL > 0 {
+		r.timer = time.AfterFunc(params.TTL, func() { _ = r.restore() })
+	}
+
+	return &r, nil
+}
+
+// Previous returns the logging levels which were set before the override.
+func (r *Restorer) Previous() map[string]string {
+	return r.previous
+}
+
+// Restore sets the logging levels which were set before the override and
+// cancels the TTL if any. Subsequent calls don't perform any API calls and
+// return the result of the first restore.
+func (r *Restorer) Restore() error {
+	if r.timer != nil {
+		r.timer.Stop()
+	}
+	return r.restore()
+}
+
+func (r *Restorer) restore() error {
+	r.once.Do(func() {
+		// Without previous levels the settings are deleted, since setting an
+		// empty set of levels would leave the overridden ones in place.
+		if len(r.previous) == 0 {
+			_, r.err = Reset(ResetParams{Params: r.params})
+		} else {
+			_, r.err = Set(SetParams{Params: r.params, Levels: r.previous})
+		}
+		close(r.done)
+	})
+	return r.err
+}
+
+// Done returns a channel which is closed once the previous logging levels
+// have been restored, successfully or not.
+func (r *Restorer) Done() <-chan struct{} {
+	return r.done
+}
+
+// Err returns the error of the restore, it returns nil while the restore
+// hasn't taken place.
+func (r *Restorer) Err() error {
+	select {
+	case <-r.done:
+		return r.err
+	default:
+		return nil
+	}
+}
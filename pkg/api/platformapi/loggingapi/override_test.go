@@ -0,0 +1,210 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package loggingapi
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/api/mock"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+)
+
+func overrideResponses() []mock.Response {
+	var path = loggingSettingsPath(Runner, "an-id")
+	return []mock.Response{
+		mock.New200ResponseAssertion(&mock.RequestAssertion{
+			Header: api.DefaultReadMockHeaders,
+			Method: "GET",
+			Host:   api.DefaultMockHost,
+			Path:   path,
+		}, mock.NewStringBody(`{"logging_levels": {"root": "INFO", "http": "WARN"}}`)),
+		mock.New200ResponseAssertion(&mock.RequestAssertion{
+			Header: api.DefaultWriteMockHeaders,
+			Method: "PATCH",
+			Host:   api.DefaultMockHost,
+			Path:   path,
+			Body:   mock.NewStringBody(`"{\"logging_levels\":{\"root\":\"DEBUG\"}}"` + "\n"),
+		}, mock.NewStringBody(`{"logging_levels": {"root": "DEBUG", "http": "WARN"}}`)),
+		mock.New200ResponseAssertion(&mock.RequestAssertion{
+			Header: api.DefaultWriteMockHeaders,
+			Method: "PUT",
+			Host:   api.DefaultMockHost,
+			Path:   path,
+			Body:   mock.NewStringBody(`{"logging_levels":{"http":"WARN","root":"INFO"}}` + "\n"),
+		}, mock.NewStringBody(`{"logging_levels": {"root": "INFO", "http": "WARN"}}`)),
+	}
+}
+
+func TestOverride(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   OverrideParams
+		previous map[string]string
+		err      error
+	}{
+		{
+			name: "fails due to parameter validation",
+			params: OverrideParams{
+				Params: Params{Component: Runner, ID: "an-id"},
+				TTL:    -1,
+			},
+			err: multierror.NewPrefixed("invalid logging settings override params",
+				apierror.ErrMissingAPI,
+				errors.New("region not specified and is required for this operation"),
+				errors.New("at least one logging level must be specified"),
+				errors.New("ttl cannot be negative"),
+			),
+		},
+		{
+			name: "fails reading the current levels",
+			params: OverrideParams{
+				Levels: map[string]string{"root": "DEBUG"},
+				Params: Params{
+					Component: Runner,
+					ID:        "an-id",
+					Region:    "us-east-1",
+					API:       api.NewMock(mock.New500Response(mock.NewStringBody(`{"error": "some error"}`))),
+				},
+			},
+			err: errors.New(`{"error": "some error"}`),
+		},
+		{
+			name: "fails updating the levels",
+			params: OverrideParams{
+				Levels: map[string]string{"root": "DEBUG"},
+				Params: Params{
+					Component: Runner,
+					ID:        "an-id",
+					Region:    "us-east-1",
+					API: api.NewMock(
+						overrideResponses()[0],
+						mock.New500Response(mock.NewStringBody(`{"error": "some error"}`)),
+					),
+				},
+			},
+			err: errors.New(`{"error": "some error"}`),
+		},
+		{
+			name: "succeeds",
+			params: OverrideParams{
+				Levels: map[string]string{"root": "DEBUG"},
+				Params: Params{
+					Component: Runner,
+					ID:        "an-id",
+					Region:    "us-east-1",
+					API:       api.NewMock(overrideResponses()...),
+				},
+			},
+			previous: map[string]string{"root": "INFO", "http": "WARN"},
+		},
+		{
+			name: "resets the logging settings when there were no previous levels",
+			params: OverrideParams{
+				Levels: map[string]string{"root": "DEBUG"},
+				Params: Params{
+					Component: Runner,
+					ID:        "an-id",
+					Region:    "us-east-1",
+					API: api.NewMock(
+						mock.New200Response(mock.NewStringBody(`{}`)),
+						overrideResponses()[1],
+						mock.New200ResponseAssertion(&mock.RequestAssertion{
+							Header: api.DefaultWriteMockHeaders,
+							Method: "DELETE",
+							Host:   api.DefaultMockHost,
+							Path:   loggingSettingsPath(Runner, "an-id"),
+						}, mock.NewStringBody(`{}`)),
+					),
+				},
+			},
+			previous: map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Override(tt.params)
+			assert.Equal(t, tt.err, err)
+			if err != nil {
+				assert.Nil(t, got)
+				return
+			}
+
+			assert.Equal(t, tt.previous, got.Previous())
+			assert.NoError(t, got.Err())
+			assert.NoError(t, got.Restore())
+			<-got.Done()
+
+			// A second restore doesn't perform any further API calls.
+			assert.NoError(t, got.Restore())
+		})
+	}
+}
+
+func TestOverrideTTL(t *testing.T) {
+	got, err := Override(OverrideParams{
+		Levels: map[string]string{"root": "DEBUG"},
+		TTL:    time.Millisecond,
+		Params: Params{
+			Component: Runner,
+			ID:        "an-id",
+			Region:    "us-east-1",
+			API:       api.NewMock(overrideResponses()...),
+		},
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	select {
+	case <-got.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("the logging levels were not restored after the TTL elapsed")
+	}
+	assert.NoError(t, got.Err())
+	assert.NoError(t, got.Restore())
+}
+
+func TestOverrideRestoreFailure(t *testing.T) {
+	var responses = overrideResponses()[:2]
+	responses = append(responses,
+		mock.New500Response(mock.NewStringBody(`{"error": "some error"}`)),
+	)
+	got, err := Override(OverrideParams{
+		Levels: map[string]string{"root": "DEBUG"},
+		TTL:    time.Hour,
+		Params: Params{
+			Component: Runner,
+			ID:        "an-id",
+			Region:    "us-east-1",
+			API:       api.NewMock(responses...),
+		},
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var want = errors.New(`{"error": "some error"}`)
+	assert.Equal(t, want, got.Restore())
+	assert.Equal(t, want, got.Err())
+}
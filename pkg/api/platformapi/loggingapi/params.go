@@ -0,0 +1,90 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package loggingapi
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+	"github.com/elastic/cloud-sdk-go/pkg/util/slice"
+)
+
+// Component is a platform component type which has logging settings.
+type Component string
+
+const (
+	// Adminconsole is the admin console component.
+	Adminconsole Component = "adminconsole"
+
+	// Allocator is the allocator component.
+	Allocator Component = "allocator"
+
+	// Constructor is the constructor component.
+	Constructor Component = "constructor"
+
+	// Runner is the runner component.
+	Runner Component = "runner"
+)
+
+// Components contains all of the components which have logging settings.
+var Components = []Component{Adminconsole, Allocator, Constructor, Runner}
+
+// Params is embedded in all of the logging settings parameter structures,
+// it identifies the component instance.
+type Params struct {
+	*api.API
+
+	Region    string
+	Component Component
+	ID        string
+}
+
+func (params Params) errors() []error {
+	var errs []error
+	if params.API == nil {
+		errs = append(errs, apierror.ErrMissingAPI)
+	}
+
+	if !validComponent(params.Component) {
+		errs = append(errs, fmt.Errorf(
+			`invalid component "%s", valid components are %s`,
+			params.Component, Components,
+		))
+	}
+
+	if params.ID == "" {
+		errs = append(errs, errors.New("id cannot be empty"))
+	}
+
+	if err := ec.RequireRegionSet(params.Region); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errs
+}
+
+func validComponent(c Component) bool {
+	var components = make([]string, 0, len(Components))
+	for _, component := range Components {
+		components = append(components, string(component))
+	}
+	return slice.HasString(components, string(c))
+}
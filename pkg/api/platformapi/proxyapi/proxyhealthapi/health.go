@@ -0,0 +1,237 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package proxyhealthapi
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/client/platform_infrastructure"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+// StatusGreen is the status of a healthy proxy fleet or filtered group.
+const StatusGreen = "Green"
+
+// Report contains the health of the proxy fleet and its filtered groups.
+type Report struct {
+	// Status is one of "Green", "Yellow" or "Red".
+	Status          string
+	ExpectedProxies int32
+	ObservedProxies int32
+
+	// Healthy is true when the status is green and the observed proxies
+	// match or exceed the expected proxies.
+	Healthy bool
+
+	Allocations []Allocation
+	Groups      []GroupHealth
+}
+
+// UnmetGroups returns the filtered groups which don't meet their expected
+// proxy count.
+func (r Report) UnmetGroups() []GroupHealth {
+	var groups []GroupHealth
+	for _, g := range r.Groups {
+		if !g.MeetsExpected {
+			groups = append(groups, g)
+		}
+	}
+	return groups
+}
+
+// Allocation contains the allocations usage of the proxy fleet by type.
+type Allocation struct {
+	Type                    string
+	MaxAllocations          int32
+	ProxiesAtMaxAllocations int32
+}
+
+// GroupHealth contains the health of a proxies filtered group.
+type GroupHealth struct {
+	ID string
+
+	// Status is one of "Green", "Yellow" or "Red".
+	Status          string
+	Filters         map[string]string
+	ExpectedProxies int32
+	ObservedProxies int32
+
+	// MeetsExpected is true when the observed proxies match or exceed the
+	// expected proxies of the group.
+	MeetsExpected bool
+}
+
+// ReportParams is consumed by GetReport.
+type ReportParams struct {
+	*api.API
+	Region string
+}
+
+// Validate ensures the parameters are usable.
+func (params ReportParams) Validate() error {
+	var merr = multierror.NewPrefixed("invalid proxy health report params")
+	if params.API == nil {
+		merr = merr.Append(apierror.ErrMissingAPI)
+	}
+
+	if err := ec.RequireRegionSet(params.Region); err != nil {
+		merr = merr.Append(err)
+	}
+
+	return merr.ErrorOrNil()
+}
+
+// GetReport obtains the health of the proxy fleet and its filtered groups.
+func GetReport(params ReportParams) (*Report, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	res, err := params.V1API.PlatformInfrastructure.GetProxiesHealth(
+		platform_infrastructure.NewGetProxiesHealthParams().
+			WithContext(api.WithRegion(context.Background(), params.Region)),
+		params.AuthWriter,
+	)
+	if err != nil {
+		return nil, api.UnwrapError(err)
+	}
+
+	return newReport(res.Payload), nil
+}
+
+// GroupParams is consumed by GetGroup.
+type GroupParams struct {
+	*api.API
+	Region string
+	ID     string
+}
+
+// Validate ensures the parameters are usable.
+func (params GroupParams) Validate() error {
+	var merr = multierror.NewPrefixed("invalid proxy filtered group health params")
+	if params.API == nil {
+		merr = merr.Append(apierror.ErrMissingAPI)
+	}
+
+	if params.ID == "" {
+		merr = merr.Append(errors.New("filtered group id is not specified and is required for the operation"))
+	}
+
+	if err := ec.RequireRegionSet(params.Region); err != nil {
+		merr = merr.Append(err)
+	}
+
+	return merr.ErrorOrNil()
+}
+
+// GetGroup obtains the health of a single proxies filtered group.
+func GetGroup(params GroupParams) (*GroupHealth, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	res, err := params.V1API.PlatformInfrastructure.GetProxiesFilteredGroupHealth(
+		platform_infrastructure.NewGetProxiesFilteredGroupHealthParams().
+			WithContext(api.WithRegion(context.Background(), params.Region)).
+			WithProxiesFilteredGroupID(params.ID),
+		params.AuthWriter,
+	)
+	if err != nil {
+		return nil, api.UnwrapError(err)
+	}
+
+	var group = newGroupHealth(res.Payload)
+	return &group, nil
+}
+
+func newReport(health *models.ProxiesHealth) *Report {
+	var report = Report{
+		Status:          stringValue(health.Status),
+		ExpectedProxies: int32Value(health.ExpectedProxiesCount),
+		ObservedProxies: int32Value(health.ObservedProxiesCount),
+	}
+	report.Healthy = report.Status == StatusGreen &&
+		report.ObservedProxies >= report.ExpectedProxies
+
+	for _, a := range health.Allocations {
+		if a == nil {
+			continue
+		}
+		report.Allocations = append(report.Allocations, Allocation{
+			Type:                    stringValue(a.AllocationsType),
+			MaxAllocations:          int32Value(a.MaxAllocations),
+			ProxiesAtMaxAllocations: int32Value(a.ProxiesAtMaxAllocations),
+		})
+	}
+
+	for _, g := range health.FilteredGroups {
+		if g == nil {
+			continue
+		}
+		report.Groups = append(report.Groups, newGroupHealth(g))
+	}
+	sort.SliceStable(report.Groups, func(i, j int) bool {
+		return report.Groups[i].ID < report.Groups[j].ID
+	})
+
+	return &report
+}
+
+func newGroupHealth(health *models.ProxiesFilteredGroupHealth) GroupHealth {
+	var group = GroupHealth{
+		Status:          stringValue(health.Status),
+		ObservedProxies: int32Value(health.ObservedProxiesCount),
+	}
+
+	if g := health.Group; g != nil {
+		group.ID = g.ID
+		group.ExpectedProxies = int32Value(g.ExpectedProxiesCount)
+		for _, f := range g.Filters {
+			if f == nil {
+				continue
+			}
+			if group.Filters == nil {
+				group.Filters = make(map[string]string)
+			}
+			group.Filters[stringValue(f.Key)] = stringValue(f.Value)
+		}
+	}
+	group.MeetsExpected = group.ObservedProxies >= group.ExpectedProxies
+
+	return group
+}
+
+func int32Value(v *int32) int32 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
+
+func stringValue(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
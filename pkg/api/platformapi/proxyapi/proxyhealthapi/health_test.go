@@ -0,0 +1,199 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package proxyhealthapi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/api/mock"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+)
+
+const proxiesPath = "/api/v1/regions/us-east-1/platform/infrastructure/proxies"
+
+const proxiesHealthResponse = `{
+  "status": "Yellow",
+  "expected_proxies_count": 3,
+  "observed_proxies_count": 3,
+  "allocations": [
+    {"allocations_type": "elasticsearch", "max_allocations": 100, "proxies_at_max_allocations": 1}
+  ],
+  "filtered_groups": [
+    {
+      "status": "Red",
+      "observed_proxies_count": 1,
+      "group": {
+        "id": "zone-b",
+        "expected_proxies_count": 2,
+        "filters": [{"key": "proxyZone", "value": "us-east-1b"}]
+      }
+    },
+    {
+      "status": "Green",
+      "observed_proxies_count": 2,
+      "group": {
+        "id": "zone-a",
+        "expected_proxies_count": 2,
+        "filters": [{"key": "proxyZone", "value": "us-east-1a"}]
+      }
+    }
+  ]
+}`
+
+func TestGetReport(t *testing.T) {
+	tests := []struct {
+		name   string
+		params ReportParams
+		want   *Report
+		err    error
+	}{
+		{
+			name: "fails due to parameter validation",
+			err: multierror.NewPrefixed("invalid proxy health report params",
+				apierror.ErrMissingAPI,
+				errors.New("region not specified and is required for this operation"),
+			),
+		},
+		{
+			name: "fails due to API failure",
+			params: ReportParams{
+				Region: "us-east-1",
+				API:    api.NewMock(mock.New500Response(mock.NewStringBody(`{"error": "some error"}`))),
+			},
+			err: errors.New(`{"error": "some error"}`),
+		},
+		{
+			name: "succeeds",
+			params: ReportParams{
+				Region: "us-east-1",
+				API: api.NewMock(mock.New200ResponseAssertion(&mock.RequestAssertion{
+					Header: api.DefaultReadMockHeaders,
+					Method: "GET",
+					Host:   api.DefaultMockHost,
+					Path:   proxiesPath + "/health",
+				}, mock.NewStringBody(proxiesHealthResponse))),
+			},
+			want: &Report{
+				Status:          "Yellow",
+				ExpectedProxies: 3,
+				ObservedProxies: 3,
+				Allocations: []Allocation{{
+					Type:                    "elasticsearch",
+					MaxAllocations:          100,
+					ProxiesAtMaxAllocations: 1,
+				}},
+				Groups: []GroupHealth{
+					{
+						ID:              "zone-a",
+						Status:          "Green",
+						Filters:         map[string]string{"proxyZone": "us-east-1a"},
+						ExpectedProxies: 2,
+						ObservedProxies: 2,
+						MeetsExpected:   true,
+					},
+					{
+						ID:              "zone-b",
+						Status:          "Red",
+						Filters:         map[string]string{"proxyZone": "us-east-1b"},
+						ExpectedProxies: 2,
+						ObservedProxies: 1,
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetReport(tt.params)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestReport_UnmetGroups(t *testing.T) {
+	var report = Report{Groups: []GroupHealth{
+		{ID: "zone-a", MeetsExpected: true},
+		{ID: "zone-b"},
+	}}
+	assert.Equal(t, []GroupHealth{{ID: "zone-b"}}, report.UnmetGroups())
+	assert.Empty(t, Report{}.UnmetGroups())
+}
+
+func TestGetGroup(t *testing.T) {
+	tests := []struct {
+		name   string
+		params GroupParams
+		want   *GroupHealth
+		err    error
+	}{
+		{
+			name: "fails due to parameter validation",
+			err: multierror.NewPrefixed("invalid proxy filtered group health params",
+				apierror.ErrMissingAPI,
+				errors.New("filtered group id is not specified and is required for the operation"),
+				errors.New("region not specified and is required for this operation"),
+			),
+		},
+		{
+			name: "fails due to API failure",
+			params: GroupParams{
+				ID:     "zone-a",
+				Region: "us-east-1",
+				API:    api.NewMock(mock.New500Response(mock.NewStringBody(`{"error": "some error"}`))),
+			},
+			err: errors.New(`{"error": "some error"}`),
+		},
+		{
+			name: "succeeds",
+			params: GroupParams{
+				ID:     "zone-a",
+				Region: "us-east-1",
+				API: api.NewMock(mock.New200ResponseAssertion(&mock.RequestAssertion{
+					Header: api.DefaultReadMockHeaders,
+					Method: "GET",
+					Host:   api.DefaultMockHost,
+					Path:   proxiesPath + "/filtered-groups/zone-a/health",
+				}, mock.NewStringBody(`{
+  "status": "Green",
+  "observed_proxies_count": 3,
+  "group": {"id": "zone-a", "expected_proxies_count": 2, "filters": []}
+}`))),
+			},
+			want: &GroupHealth{
+				ID:              "zone-a",
+				Status:          "Green",
+				ExpectedProxies: 2,
+				ObservedProxies: 3,
+				MeetsExpected:   true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetGroup(tt.params)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,206 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package proxyhealthapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/client/platform_infrastructure"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+var errSettingsCannotBeNil = errors.New("settings cannot be nil")
+
+// VersionedSettings contains the proxy settings and their version, which
+// can be sent on a subsequent SetSettings or UpdateSettings call to detect
+// concurrent modifications.
+type VersionedSettings struct {
+	Settings *models.ProxiesSettings
+
+	// Version of the settings, nil when the API didn't return any.
+	Version *int64
+}
+
+// GetSettingsParams is consumed by GetSettings.
+type GetSettingsParams struct {
+	*api.API
+	Region string
+}
+
+// Validate ensures the parameters are usable.
+func (params GetSettingsParams) Validate() error {
+	var merr = multierror.NewPrefixed("invalid proxy settings get params")
+	if params.API == nil {
+		merr = merr.Append(apierror.ErrMissingAPI)
+	}
+
+	if err := ec.RequireRegionSet(params.Region); err != nil {
+		merr = merr.Append(err)
+	}
+
+	return merr.ErrorOrNil()
+}
+
+// GetSettings obtains the global proxy settings and their version.
+func GetSettings(params GetSettingsParams) (*VersionedSettings, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	res, err := params.V1API.PlatformInfrastructure.GetProxiesSettings(
+		platform_infrastructure.NewGetProxiesSettingsParams().
+			WithContext(api.WithRegion(context.Background(), params.Region)),
+		params.AuthWriter,
+	)
+	if err != nil {
+		return nil, api.UnwrapError(err)
+	}
+
+	return newVersionedSettings(res.Payload, res.XCloudResourceVersion)
+}
+
+// SetSettingsParams is consumed by SetSettings and UpdateSettings.
+type SetSettingsParams struct {
+	*api.API
+	Region   string
+	Settings *models.ProxiesSettings
+
+	// Optional version of the settings, the operation fails with a conflict
+	// when it doesn't match the current version.
+	Version *int64
+}
+
+// Validate ensures the parameters are usable.
+func (params SetSettingsParams) Validate() error {
+	var merr = multierror.NewPrefixed("invalid proxy settings set params")
+	if params.API == nil {
+		merr = merr.Append(apierror.ErrMissingAPI)
+	}
+
+	if params.Settings == nil {
+		merr = merr.Append(errSettingsCannotBeNil)
+	}
+
+	if err := ec.RequireRegionSet(params.Region); err != nil {
+		merr = merr.Append(err)
+	}
+
+	return merr.ErrorOrNil()
+}
+
+// SetSettings replaces the global proxy settings.
+func SetSettings(params SetSettingsParams) (*VersionedSettings, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	res, err := params.V1API.PlatformInfrastructure.SetProxiesSettings(
+		platform_infrastructure.NewSetProxiesSettingsParams().
+			WithContext(api.WithRegion(context.Background(), params.Region)).
+			WithVersion(params.Version).
+			WithBody(params.Settings),
+		params.AuthWriter,
+	)
+	if err != nil {
+		return nil, api.UnwrapError(err)
+	}
+
+	return newVersionedSettings(res.Payload, res.XCloudResourceVersion)
+}
+
+// UpdateSettings applies the non empty settings as a partial update of the
+// global proxy settings.
+func UpdateSettings(params SetSettingsParams) (*VersionedSettings, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	body, err := mergePatch(params.Settings)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := params.V1API.PlatformInfrastructure.UpdateProxiesSettings(
+		platform_infrastructure.NewUpdateProxiesSettingsParams().
+			WithContext(api.WithRegion(context.Background(), params.Region)).
+			WithVersion(params.Version).
+			WithBody(body),
+		params.AuthWriter,
+	)
+	if err != nil {
+		return nil, api.UnwrapError(err)
+	}
+
+	return newVersionedSettings(res.Payload, res.XCloudResourceVersion)
+}
+
+func newVersionedSettings(settings *models.ProxiesSettings, version string) (*VersionedSettings, error) {
+	var res = VersionedSettings{Settings: settings}
+	if version == "" {
+		return &res, nil
+	}
+
+	v, err := strconv.ParseInt(version, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy settings version %s: %s", version, err)
+	}
+	res.Version = &v
+
+	return &res, nil
+}
+
+// mergePatch encodes the settings as a JSON merge patch, since the null values
+// of a merge patch remove the settings, any of the unset fields are dropped.
+func mergePatch(settings *models.ProxiesSettings) (string, error) {
+	b, err := json.Marshal(settings)
+	if err != nil {
+		return "", err
+	}
+
+	var patch map[string]interface{}
+	if err := json.Unmarshal(b, &patch); err != nil {
+		return "", err
+	}
+	dropNulls(patch)
+
+	b, err = json.Marshal(patch)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+func dropNulls(m map[string]interface{}) {
+	for k, v := range m {
+		switch value := v.(type) {
+		case nil:
+			delete(m, k)
+		case map[string]interface{}:
+			dropNulls(value)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package proxyhealthapi
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/api/mock"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+const proxiesSettingsResponse = `{"expected_proxies_count": 3, "signature_secret": "secret"}`
+
+func versionedResponse(assertion *mock.RequestAssertion, version string) mock.Response {
+	return mock.Response{
+		Response: http.Response{
+			StatusCode: 200,
+			Header:     http.Header{"X-Cloud-Resource-Version": {version}},
+			Body:       mock.NewStringBody(proxiesSettingsResponse),
+		},
+		Assert: assertion,
+	}
+}
+
+var wantSettings = &models.ProxiesSettings{
+	ExpectedProxiesCount: ec.Int32(3),
+	SignatureSecret:      ec.String("secret"),
+}
+
+func TestGetSettings(t *testing.T) {
+	tests := []struct {
+		name   string
+		params GetSettingsParams
+		want   *VersionedSettings
+		err    string
+	}{
+		{
+			name: "fails due to parameter validation",
+			err: multierror.NewPrefixed("invalid proxy settings get params",
+				apierror.ErrMissingAPI,
+				errors.New("region not specified and is required for this operation"),
+			).Error(),
+		},
+		{
+			name: "fails due to API failure",
+			params: GetSettingsParams{
+				Region: "us-east-1",
+				API:    api.NewMock(mock.New500Response(mock.NewStringBody(`{"error": "some error"}`))),
+			},
+			err: `{"error": "some error"}`,
+		},
+		{
+			name: "fails due to an invalid version",
+			params: GetSettingsParams{
+				Region: "us-east-1",
+				API:    api.NewMock(versionedResponse(nil, "abc")),
+			},
+			err: `invalid proxy settings version abc: strconv.ParseInt: parsing "abc": invalid syntax`,
+		},
+		{
+			name: "succeeds without version",
+			params: GetSettingsParams{
+				Region: "us-east-1",
+				API:    api.NewMock(mock.New200Response(mock.NewStringBody(proxiesSettingsResponse))),
+			},
+			want: &VersionedSettings{Settings: wantSettings},
+		},
+		{
+			name: "succeeds",
+			params: GetSettingsParams{
+				Region: "us-east-1",
+				API: api.NewMock(versionedResponse(&mock.RequestAssertion{
+					Header: api.DefaultReadMockHeaders,
+					Method: "GET",
+					Host:   api.DefaultMockHost,
+					Path:   proxiesPath + "/settings",
+				}, "4")),
+			},
+			want: &VersionedSettings{Settings: wantSettings, Version: ec.Int64(4)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetSettings(tt.params)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSetSettings(t *testing.T) {
+	tests := []struct {
+		name   string
+		params SetSettingsParams
+		want   *VersionedSettings
+		err    string
+	}{
+		{
+			name: "fails due to parameter validation",
+			err: multierror.NewPrefixed("invalid proxy settings set params",
+				apierror.ErrMissingAPI,
+				errSettingsCannotBeNil,
+				errors.New("region not specified and is required for this operation"),
+			).Error(),
+		},
+		{
+			name: "fails due to a version conflict",
+			params: SetSettingsParams{
+				Region:   "us-east-1",
+				Settings: wantSettings,
+				Version:  ec.Int64(3),
+				API: api.NewMock(mock.Response{Response: http.Response{
+					StatusCode: 409,
+					Body:       mock.NewStringBody(`{"errors": [{"code": "settings.version_conflict", "message": "version conflict"}]}`),
+				}}),
+			},
+			err: multierror.NewPrefixed("api error",
+				errors.New("settings.version_conflict: version conflict"),
+			).Error(),
+		},
+		{
+			name: "succeeds",
+			params: SetSettingsParams{
+				Region:   "us-east-1",
+				Settings: wantSettings,
+				Version:  ec.Int64(3),
+				API: api.NewMock(versionedResponse(&mock.RequestAssertion{
+					Header: api.DefaultWriteMockHeaders,
+					Method: "PUT",
+					Host:   api.DefaultMockHost,
+					Path:   proxiesPath + "/settings",
+					Query:  url.Values{"version": {"3"}},
+					Body:   mock.NewStringBody(`{"expected_proxies_count":3,"http_settings":null,"signature_secret":"secret","signature_valid_for_millis":null}` + "\n"),
+				}, "4")),
+			},
+			want: &VersionedSettings{Settings: wantSettings, Version: ec.Int64(4)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SetSettings(tt.params)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestUpdateSettings(t *testing.T) {
+	tests := []struct {
+		name   string
+		params SetSettingsParams
+		want   *VersionedSettings
+		err    error
+	}{
+		{
+			name: "fails due to parameter validation",
+			err: multierror.NewPrefixed("invalid proxy settings set params",
+				apierror.ErrMissingAPI,
+				errSettingsCannotBeNil,
+				errors.New("region not specified and is required for this operation"),
+			),
+		},
+		{
+			name: "fails due to API failure",
+			params: SetSettingsParams{
+				Region:   "us-east-1",
+				Settings: wantSettings,
+				API:      api.NewMock(mock.New500Response(mock.NewStringBody(`{"error": "some error"}`))),
+			},
+			err: errors.New(`{"error": "some error"}`),
+		},
+		{
+			name: "succeeds sending only the set fields",
+			params: SetSettingsParams{
+				Region: "us-east-1",
+				Settings: &models.ProxiesSettings{
+					ExpectedProxiesCount: ec.Int32(3),
+					HTTPSettings: &models.ProxiesHTTPSettings{
+						MinimumProxyServices: ec.Int32(2),
+					},
+				},
+				Version: ec.Int64(3),
+				API: api.NewMock(versionedResponse(&mock.RequestAssertion{
+					Header: api.DefaultWriteMockHeaders,
+					Method: "PATCH",
+					Host:   api.DefaultMockHost,
+					Path:   proxiesPath + "/settings",
+					Query:  url.Values{"version": {"3"}},
+					Body:   mock.NewStringBody(`"{\"expected_proxies_count\":3,\"http_settings\":{\"minimum_proxy_services\":2}}"` + "\n"),
+				}, "4")),
+			},
+			want: &VersionedSettings{Settings: wantSettings, Version: ec.Int64(4)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UpdateSettings(tt.params)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
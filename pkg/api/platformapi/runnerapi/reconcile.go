@@ -0,0 +1,338 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package runnerapi
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/api/platformapi/roleapi"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+	"github.com/elastic/cloud-sdk-go/pkg/util/slice"
+)
+
+// RoleSelector selects runners by ID, zone or any of their existing roles.
+// Each of the populated criteria must match for a runner to be selected,
+// while any of the values of a criteria can match.
+type RoleSelector struct {
+	IDs   []string
+	Zones []string
+	Roles []string
+}
+
+// Matches returns true when the runner is selected by the selector.
+func (s RoleSelector) Matches(runner *models.RunnerInfo) bool {
+	if s.empty() || runner == nil {
+		return false
+	}
+
+	if len(s.IDs) > 0 && !slice.HasString(s.IDs, runnerID(runner)) {
+		return false
+	}
+
+	if len(s.Zones) > 0 && !slice.HasString(s.Zones, runner.Zone) {
+		return false
+	}
+
+	if len(s.Roles) > 0 {
+		var found bool
+		for _, role := range runnerRoles(runner) {
+			if slice.HasString(s.Roles, role) {
+				found = true
+				break
+			}
+		}
+		return found
+	}
+
+	return true
+}
+
+func (s RoleSelector) empty() bool {
+	return len(s.IDs) == 0 && len(s.Zones) == 0 && len(s.Roles) == 0
+}
+
+// RoleAssignment declares the roles which the runners matched by the
+// selector must have.
+type RoleAssignment struct {
+	Selector RoleSelector
+	Roles    []string
+}
+
+// ReconcileRolesParams is consumed by ReconcileRoles.
+type ReconcileRolesParams struct {
+	*api.API
+	Region string
+
+	// Assignments is the desired role layout. A runner which is matched by
+	// more than one assignment has the union of their roles. Runners which
+	// aren't matched by any assignment are left untouched.
+	Assignments []RoleAssignment
+
+	// Optional runner IDs to restrict the reconciliation to. When set, each
+	// runner is obtained through Show, otherwise all the runners are listed.
+	Runners []string
+
+	// DryRun computes the role changes without applying them.
+	DryRun bool
+}
+
+// Validate checks the parameters
+func (params ReconcileRolesParams) Validate() error {
+	var merr = multierror.NewPrefixed("invalid runner role reconcile params")
+	if params.API == nil {
+		merr = merr.Append(apierror.ErrMissingAPI)
+	}
+
+	if len(params.Assignments) == 0 {
+		merr = merr.Append(errors.New("at least one role assignment must be specified"))
+	}
+
+	for i, a := range params.Assignments {
+		if a.Selector.empty() {
+			merr = merr.Append(fmt.Errorf("assignment %d: selector cannot be empty", i))
+		}
+		if len(a.Roles) == 0 {
+			merr = merr.Append(fmt.Errorf("assignment %d: roles cannot be empty", i))
+		}
+	}
+
+	if err := ec.RequireRegionSet(params.Region); err != nil {
+		merr = merr.Append(err)
+	}
+
+	return merr.ErrorOrNil()
+}
+
+// RoleChange contains the role additions and removals of a runner.
+type RoleChange struct {
+	RunnerID string
+	Zone     string
+	Current  []string
+	Desired  []string
+	Add      []string
+	Remove   []string
+}
+
+// RoleReconcileReport contains the role changes computed by ReconcileRoles.
+type RoleReconcileReport struct {
+	Changes []RoleChange
+	DryRun  bool
+}
+
+// ReconcileRoles compares the declared role assignments with the roles of
+// the runners and applies the role additions and removals, updating the
+// blessings of each affected role accordingly. When DryRun is set, the
+// changes are only computed and returned.
+func ReconcileRoles(params ReconcileRolesParams) (*RoleReconcileReport, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	runners, err := reconcileRunners(params)
+	if err != nil {
+		return nil, err
+	}
+
+	var report = RoleReconcileReport{
+		Changes: computeRoleChanges(runners, params.Assignments),
+		DryRun:  params.DryRun,
+	}
+	if params.DryRun || len(report.Changes) == 0 {
+		return &report, nil
+	}
+
+	var merr = multierror.NewPrefixed("runner role reconcile")
+	var applied = make([]RoleChange, 0, len(report.Changes))
+	for _, change := range report.Changes {
+		if _, err := SetRoles(SetRolesParams{
+			API:    params.API,
+			Region: params.Region,
+			ID:     change.RunnerID,
+			Roles:  change.Desired,
+		}); err != nil {
+			merr = merr.Append(fmt.Errorf("runner %s: %w", change.RunnerID, err))
+			continue
+		}
+		applied = append(applied, change)
+	}
+
+	if err := reconcileBlessings(params, applied); err != nil {
+		merr = merr.Append(err)
+	}
+
+	return &report, merr.ErrorOrNil()
+}
+
+func reconcileRunners(params ReconcileRolesParams) ([]*models.RunnerInfo, error) {
+	if len(params.Runners) == 0 {
+		res, err := List(ListParams{API: params.API, Region: params.Region})
+		if err != nil {
+			return nil, err
+		}
+		return res.Runners, nil
+	}
+
+	var runners = make([]*models.RunnerInfo, 0, len(params.Runners))
+	for _, id := range params.Runners {
+		res, err := Show(ShowParams{API: params.API, Region: params.Region, ID: id})
+		if err != nil {
+			return nil, err
+		}
+		runners = append(runners, res)
+	}
+	return runners, nil
+}
+
+func computeRoleChanges(runners []*models.RunnerInfo, assignments []RoleAssignment) []RoleChange {
+	var changes []RoleChange
+	for _, runner := range runners {
+		var desired []string
+		var matched bool
+		for _, a := range assignments {
+			if !a.Selector.Matches(runner) {
+				continue
+			}
+			matched = true
+			for _, role := range a.Roles {
+				if !slice.HasString(desired, role) {
+					desired = append(desired, role)
+				}
+			}
+		}
+		if !matched {
+			continue
+		}
+		sort.Strings(desired)
+
+		var current = runnerRoles(runner)
+		var change = RoleChange{
+			RunnerID: runnerID(runner),
+			Zone:     runner.Zone,
+			Current:  current,
+			Desired:  desired,
+			Add:      difference(desired, current),
+			Remove:   difference(current, desired),
+		}
+		if len(change.Add) > 0 || len(change.Remove) > 0 {
+			changes = append(changes, change)
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].RunnerID < changes[j].RunnerID
+	})
+	return changes
+}
+
+// reconcileBlessings blesses the runners for the roles they've been added to
+// and removes the blessings of the roles they've been removed from.
+func reconcileBlessings(params ReconcileRolesParams, changes []RoleChange) error {
+	var blessings = make(map[string]map[string]bool)
+	for _, change := range changes {
+		for _, role := range change.Add {
+			if blessings[role] == nil {
+				blessings[role] = make(map[string]bool)
+			}
+			blessings[role][change.RunnerID] = true
+		}
+		for _, role := range change.Remove {
+			if blessings[role] == nil {
+				blessings[role] = make(map[string]bool)
+			}
+			blessings[role][change.RunnerID] = false
+		}
+	}
+
+	var roles = make([]string, 0, len(blessings))
+	for role := range blessings {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+
+	var merr = multierror.NewPrefixed("role blessings")
+	for _, role := range roles {
+		res, err := roleapi.Show(roleapi.ShowParams{
+			API: params.API, Region: params.Region, ID: role,
+		})
+		if err != nil {
+			merr = merr.Append(fmt.Errorf("role %s: %w", role, err))
+			continue
+		}
+
+		var blessed = make(map[string]models.Blessing)
+		if res.Blessings != nil && res.Blessings.Value != nil {
+			for id, b := range res.Blessings.Value.RunnerIdsToBlessing {
+				blessed[id] = b
+			}
+		}
+
+		for id, bless := range blessings[role] {
+			if bless {
+				blessed[id] = models.Blessing{Value: ec.Bool(true)}
+			} else {
+				delete(blessed, id)
+			}
+		}
+
+		if err := roleapi.SetBlessings(roleapi.SetBlessingsParams{
+			API:       params.API,
+			Region:    params.Region,
+			ID:        role,
+			Blessings: &models.Blessings{RunnerIdsToBlessing: blessed},
+		}); err != nil {
+			merr = merr.Append(fmt.Errorf("role %s: %w", role, err))
+		}
+	}
+
+	return merr.ErrorOrNil()
+}
+
+func runnerID(runner *models.RunnerInfo) string {
+	if runner.RunnerID == nil {
+		return ""
+	}
+	return *runner.RunnerID
+}
+
+func runnerRoles(runner *models.RunnerInfo) []string {
+	var roles = make([]string, 0, len(runner.Roles))
+	for _, role := range runner.Roles {
+		if role != nil && role.RoleName != nil {
+			roles = append(roles, *role.RoleName)
+		}
+	}
+	sort.Strings(roles)
+	return roles
+}
+
+// difference returns the elements of a which aren't in b.
+func difference(a, b []string) []string {
+	var diff []string
+	for _, s := range a {
+		if !slice.HasString(b, s) {
+			diff = append(diff, s)
+		}
+	}
+	return diff
+}
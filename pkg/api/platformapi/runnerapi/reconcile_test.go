@@ -0,0 +1,245 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package runnerapi
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/api/mock"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+const (
+	runnersPath = "/api/v1/regions/us-east-1/platform/infrastructure/runners"
+	rolesPath   = "/api/v1/regions/us-east-1/platform/infrastructure/blueprinter/roles"
+
+	reconcileRunnersList = `{"runners": [
+  {"runner_id": "r1", "zone": "zone-1", "connected": true, "roles": [{"role_name": "allocator"}]},
+  {"runner_id": "r2", "zone": "zone-2", "connected": true, "roles": [{"role_name": "allocator"}, {"role_name": "proxy"}]},
+  {"runner_id": "r3", "zone": "zone-1", "connected": true, "roles": [{"role_name": "proxy"}]},
+  {"runner_id": "r4", "zone": "zone-3", "connected": true, "roles": [{"role_name": "zookeeper"}]}
+]}`
+)
+
+var reconcileAssignments = []RoleAssignment{
+	{Selector: RoleSelector{Zones: []string{"zone-1"}}, Roles: []string{"proxy", "allocator"}},
+	{Selector: RoleSelector{IDs: []string{"r2"}}, Roles: []string{"allocator"}},
+}
+
+var reconcileChanges = []RoleChange{
+	{
+		RunnerID: "r1", Zone: "zone-1",
+		Current: []string{"allocator"}, Desired: []string{"allocator", "proxy"},
+		Add: []string{"proxy"},
+	},
+	{
+		RunnerID: "r2", Zone: "zone-2",
+		Current: []string{"allocator", "proxy"}, Desired: []string{"allocator"},
+		Remove: []string{"proxy"},
+	},
+	{
+		RunnerID: "r3", Zone: "zone-1",
+		Current: []string{"proxy"}, Desired: []string{"allocator", "proxy"},
+		Add: []string{"allocator"},
+	},
+}
+
+func setRolesResponse(id, body string) mock.Response {
+	return mock.New200ResponseAssertion(&mock.RequestAssertion{
+		Header: api.DefaultWriteMockHeaders,
+		Method: "PUT",
+		Host:   api.DefaultMockHost,
+		Path:   runnersPath + "/" + id + "/roles",
+		Query:  url.Values{"bless": {"false"}},
+		Body:   mock.NewStringBody(body + "\n"),
+	}, mock.NewStringBody(body))
+}
+
+func TestRoleSelector_Matches(t *testing.T) {
+	var runner = &models.RunnerInfo{
+		RunnerID: ec.String("r1"),
+		Zone:     "zone-1",
+		Roles:    []*models.RunnerRoleInfo{{RoleName: ec.String("proxy")}},
+	}
+	tests := []struct {
+		name     string
+		selector RoleSelector
+		want     bool
+	}{
+		{name: "empty selector matches nothing"},
+		{name: "matches by id", selector: RoleSelector{IDs: []string{"r2", "r1"}}, want: true},
+		{name: "matches by zone", selector: RoleSelector{Zones: []string{"zone-1"}}, want: true},
+		{name: "matches by role", selector: RoleSelector{Roles: []string{"proxy"}}, want: true},
+		{
+			name:     "matches all the criteria",
+			selector: RoleSelector{Zones: []string{"zone-1"}, Roles: []string{"proxy"}},
+			want:     true,
+		},
+		{
+			name:     "doesn't match when one of the criteria doesn't",
+			selector: RoleSelector{Zones: []string{"zone-1"}, Roles: []string{"allocator"}},
+		},
+		{name: "doesn't match a different zone", selector: RoleSelector{Zones: []string{"zone-2"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.selector.Matches(runner))
+		})
+	}
+}
+
+func TestReconcileRoles(t *testing.T) {
+	var listResponse = func() mock.Response {
+		return mock.New200ResponseAssertion(&mock.RequestAssertion{
+			Header: api.DefaultReadMockHeaders,
+			Method: "GET",
+			Host:   api.DefaultMockHost,
+			Path:   runnersPath,
+		}, mock.NewStringBody(reconcileRunnersList))
+	}
+
+	tests := []struct {
+		name   string
+		params ReconcileRolesParams
+		want   *RoleReconcileReport
+		err    string
+	}{
+		{
+			name: "fails due to parameter validation",
+			params: ReconcileRolesParams{Assignments: []RoleAssignment{
+				{Roles: []string{"proxy"}},
+				{Selector: RoleSelector{IDs: []string{"r1"}}},
+			}},
+			err: multierror.NewPrefixed("invalid runner role reconcile params",
+				apierror.ErrMissingAPI,
+				errors.New("assignment 0: selector cannot be empty"),
+				errors.New("assignment 1: roles cannot be empty"),
+				errors.New("region not specified and is required for this operation"),
+			).Error(),
+		},
+		{
+			name: "fails listing the runners",
+			params: ReconcileRolesParams{
+				Region:      "us-east-1",
+				Assignments: reconcileAssignments,
+				API:         api.NewMock(mock.New500Response(mock.NewStringBody(`{"error": "some error"}`))),
+			},
+			err: `{"error": "some error"}`,
+		},
+		{
+			name: "computes the changes on dry run",
+			params: ReconcileRolesParams{
+				Region:      "us-east-1",
+				Assignments: reconcileAssignments,
+				DryRun:      true,
+				API:         api.NewMock(listResponse()),
+			},
+			want: &RoleReconcileReport{Changes: reconcileChanges, DryRun: true},
+		},
+		{
+			name: "obtains the specified runners on dry run",
+			params: ReconcileRolesParams{
+				Region:      "us-east-1",
+				Assignments: reconcileAssignments,
+				Runners:     []string{"r2"},
+				DryRun:      true,
+				API: api.NewMock(mock.New200ResponseAssertion(&mock.RequestAssertion{
+					Header: api.DefaultReadMockHeaders,
+					Method: "GET",
+					Host:   api.DefaultMockHost,
+					Path:   runnersPath + "/r2",
+				}, mock.NewStringBody(`{"runner_id": "r2", "zone": "zone-2", "connected": true, "roles": [{"role_name": "allocator"}, {"role_name": "proxy"}]}`))),
+			},
+			want: &RoleReconcileReport{Changes: reconcileChanges[1:2], DryRun: true},
+		},
+		{
+			name: "applies the role changes and blessings",
+			params: ReconcileRolesParams{
+				Region:      "us-east-1",
+				Assignments: reconcileAssignments,
+				API: api.NewMock(
+					listResponse(),
+					setRolesResponse("r1", `{"roles":[{"role_name":"allocator"},{"role_name":"proxy"}]}`),
+					setRolesResponse("r2", `{"roles":[{"role_name":"allocator"}]}`),
+					setRolesResponse("r3", `{"roles":[{"role_name":"allocator"},{"role_name":"proxy"}]}`),
+					mock.New200ResponseAssertion(&mock.RequestAssertion{
+						Header: api.DefaultReadMockHeaders,
+						Method: "GET",
+						Host:   api.DefaultMockHost,
+						Path:   rolesPath + "/allocator",
+					}, mock.NewStringBody(`{"id": "allocator", "blessings": {"value": {"runner_ids_to_blessing": {"r1": {"value": true}, "r2": {"value": true}}}}}`)),
+					mock.New200ResponseAssertion(&mock.RequestAssertion{
+						Header: api.DefaultWriteMockHeaders,
+						Method: "PUT",
+						Host:   api.DefaultMockHost,
+						Path:   rolesPath + "/allocator/blessings",
+						Body:   mock.NewStringBody(`{"runner_ids_to_blessing":{"r1":{"value":true},"r2":{"value":true},"r3":{"value":true}}}` + "\n"),
+					}, mock.NewStringBody(`{}`)),
+					mock.New200ResponseAssertion(&mock.RequestAssertion{
+						Header: api.DefaultReadMockHeaders,
+						Method: "GET",
+						Host:   api.DefaultMockHost,
+						Path:   rolesPath + "/proxy",
+					}, mock.NewStringBody(`{"id": "proxy", "blessings": {"value": {"runner_ids_to_blessing": {"r2": {"value": true}, "r3": {"value": true}}}}}`)),
+					mock.New200ResponseAssertion(&mock.RequestAssertion{
+						Header: api.DefaultWriteMockHeaders,
+						Method: "PUT",
+						Host:   api.DefaultMockHost,
+						Path:   rolesPath + "/proxy/blessings",
+						Body:   mock.NewStringBody(`{"runner_ids_to_blessing":{"r1":{"value":true},"r3":{"value":true}}}` + "\n"),
+					}, mock.NewStringBody(`{}`)),
+				),
+			},
+			want: &RoleReconcileReport{Changes: reconcileChanges},
+		},
+		{
+			name: "skips the blessings of the runners which failed to be updated",
+			params: ReconcileRolesParams{
+				Region:      "us-east-1",
+				Assignments: reconcileAssignments[1:],
+				API: api.NewMock(
+					listResponse(),
+					mock.New500Response(mock.NewStringBody(`{"error": "some error"}`)),
+				),
+			},
+			want: &RoleReconcileReport{Changes: reconcileChanges[1:2]},
+			err: multierror.NewPrefixed("runner role reconcile",
+				errors.New(`runner r2: {"error": "some error"}`),
+			).Error(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReconcileRoles(tt.params)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
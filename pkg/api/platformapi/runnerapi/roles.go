@@ -0,0 +1,86 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package runnerapi
+
+import (
+	"context"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/client/platform_infrastructure"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+// SetRolesParams is consumed by SetRoles.
+type SetRolesParams struct {
+	*api.API
+	Region string
+	ID     string
+
+	// Roles which the runner will have, replacing any existing roles.
+	Roles []string
+
+	// Bless assigns the runner to the roles.
+	Bless bool
+}
+
+// Validate checks the parameters
+func (params SetRolesParams) Validate() error {
+	var merr = multierror.NewPrefixed("invalid runner set roles params")
+	if params.API == nil {
+		merr = merr.Append(apierror.ErrMissingAPI)
+	}
+
+	if params.ID == "" {
+		merr = merr.Append(errIDCannotBeEmpty)
+	}
+
+	if err := ec.RequireRegionSet(params.Region); err != nil {
+		merr = merr.Append(err)
+	}
+
+	return merr.ErrorOrNil()
+}
+
+// SetRoles replaces the roles of a runner.
+func SetRoles(params SetRolesParams) (*models.RunnerRolesInfo, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	var roles = make([]*models.RunnerRoleInfo, 0, len(params.Roles))
+	for _, role := range params.Roles {
+		roles = append(roles, &models.RunnerRoleInfo{RoleName: ec.String(role)})
+	}
+
+	res, err := params.API.V1API.PlatformInfrastructure.SetRunnerRoles(
+		platform_infrastructure.NewSetRunnerRolesParams().
+			WithContext(api.WithRegion(context.Background(), params.Region)).
+			WithRunnerID(params.ID).
+			WithBless(ec.Bool(params.Bless)).
+			WithBody(&models.RunnerRolesInfo{Roles: roles}),
+		params.AuthWriter,
+	)
+	if err != nil {
+		return nil, api.UnwrapError(err)
+	}
+
+	return res.Payload, nil
+}
@@ -0,0 +1,89 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package runnerapi
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/api/mock"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+func TestSetRoles(t *testing.T) {
+	tests := []struct {
+		name   string
+		params SetRolesParams
+		want   *models.RunnerRolesInfo
+		err    error
+	}{
+		{
+			name: "fails due to parameter validation",
+			err: multierror.NewPrefixed("invalid runner set roles params",
+				apierror.ErrMissingAPI,
+				errIDCannotBeEmpty,
+				errors.New("region not specified and is required for this operation"),
+			),
+		},
+		{
+			name: "fails due to API failure",
+			params: SetRolesParams{
+				ID:     "192.168.44.10",
+				Region: "us-east-1",
+				Roles:  []string{"proxy"},
+				API:    api.NewMock(mock.New500Response(mock.NewStringBody(`{"error": "some error"}`))),
+			},
+			err: errors.New(`{"error": "some error"}`),
+		},
+		{
+			name: "succeeds",
+			params: SetRolesParams{
+				ID:     "192.168.44.10",
+				Region: "us-east-1",
+				Roles:  []string{"allocator", "proxy"},
+				Bless:  true,
+				API: api.NewMock(mock.New200ResponseAssertion(&mock.RequestAssertion{
+					Header: api.DefaultWriteMockHeaders,
+					Method: "PUT",
+					Host:   api.DefaultMockHost,
+					Path:   "/api/v1/regions/us-east-1/platform/infrastructure/runners/192.168.44.10/roles",
+					Query:  url.Values{"bless": {"true"}},
+					Body:   mock.NewStringBody(`{"roles":[{"role_name":"allocator"},{"role_name":"proxy"}]}` + "\n"),
+				}, mock.NewStringBody(`{"roles":[{"role_name":"allocator"},{"role_name":"proxy"}]}`))),
+			},
+			want: &models.RunnerRolesInfo{Roles: []*models.RunnerRoleInfo{
+				{RoleName: ec.String("allocator")},
+				{RoleName: ec.String("proxy")},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SetRoles(tt.params)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,111 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package runnerapi
+
+import (
+	"context"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/client/platform_infrastructure"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+	"github.com/elastic/cloud-sdk-go/pkg/util/pagination"
+)
+
+// SearchIteratorParams is consumed by NewSearchIterator.
+type SearchIteratorParams struct {
+	*api.API
+	Region string
+
+	// Request used to search the runners, its From and Size are set by
+	// the iterator. When nil, all the runners are matched. The request
+	// should be sorted for the pages to be consistent.
+	Request *models.SearchRequest
+
+	// PageSize is the number of runners fetched on each request,
+	// defaults to pagination.DefaultSize.
+	PageSize int32
+}
+
+// Validate ensures the parameters are usable.
+func (params SearchIteratorParams) Validate() error {
+	var merr = multierror.NewPrefixed("invalid runner search iterator params")
+	if params.API == nil {
+		merr = merr.Append(apierror.ErrMissingAPI)
+	}
+
+	if err := ec.RequireRegionSet(params.Region); err != nil {
+		merr = merr.Append(err)
+	}
+
+	return merr.ErrorOrNil()
+}
+
+// SearchIterator iterates over the runners matching a search request,
+// lazily fetching a page of runners when the previous one has been
+// consumed.
+type SearchIterator struct {
+	pager *pagination.Pager
+	page  []*models.RunnerInfo
+}
+
+// NewSearchIterator returns an iterator over the runners matching the
+// search request. The iteration stops when the context is cancelled.
+func NewSearchIterator(ctx context.Context, params SearchIteratorParams) (*SearchIterator, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	var it SearchIterator
+	it.pager = pagination.New(ctx, params.PageSize, func(ctx context.Context, from, size int32) (int, error) {
+		var req models.SearchRequest
+		if params.Request != nil {
+			req = *params.Request
+		}
+		req.From, req.Size = from, size
+
+		res, err := params.V1API.PlatformInfrastructure.SearchRunners(
+			platform_infrastructure.NewSearchRunnersParams().
+				WithContext(api.WithRegion(ctx, params.Region)).
+				WithBody(&req),
+			params.AuthWriter,
+		)
+		if err != nil {
+			return 0, apierror.Unwrap(err)
+		}
+
+		it.page = res.Payload.Runners
+		return len(it.page), nil
+	})
+
+	return &it, nil
+}
+
+// Next advances the iterator to the next runner, returning false when
+// there are no more runners or the iteration has failed.
+func (it *SearchIterator) Next() bool { return it.pager.Next() }
+
+// Runner returns the current runner.
+func (it *SearchIterator) Runner() *models.RunnerInfo {
+	return it.page[it.pager.Index()]
+}
+
+// Err returns the error which stopped the iteration, if any.
+func (it *SearchIterator) Err() error { return it.pager.Err() }
@@ -0,0 +1,92 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package runnerapi
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/api/mock"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+func TestSearchIterator(t *testing.T) {
+	var pageResponse = func(from, size int32, ids ...string) mock.Response {
+		var res models.RunnerOverview
+		for _, id := range ids {
+			res.Runners = append(res.Runners, &models.RunnerInfo{RunnerID: ec.String(id)})
+		}
+		return mock.New200ResponseAssertion(&mock.RequestAssertion{
+			Header: api.DefaultWriteMockHeaders,
+			Method: "POST",
+			Host:   api.DefaultMockHost,
+			Path:   "/api/v1/regions/us-east-1/platform/infrastructure/runners/_search",
+			Body:   mock.NewStructBody(models.SearchRequest{From: from, Size: size}),
+		}, mock.NewStructBody(res))
+	}
+
+	t.Run("fails due to parameter validation", func(t *testing.T) {
+		got, err := NewSearchIterator(context.Background(), SearchIteratorParams{})
+		assert.Nil(t, got)
+		assert.Equal(t, multierror.NewPrefixed("invalid runner search iterator params",
+			apierror.ErrMissingAPI,
+			errors.New("region not specified and is required for this operation"),
+		), err)
+	})
+
+	t.Run("iterates over all the pages", func(t *testing.T) {
+		it, err := NewSearchIterator(context.Background(), SearchIteratorParams{
+			API: api.NewMock(
+				pageResponse(0, 2, "r1", "r2"),
+				pageResponse(2, 2),
+			),
+			Region:   "us-east-1",
+			PageSize: 2,
+		})
+		assert.NoError(t, err)
+
+		var got []string
+		for it.Next() {
+			got = append(got, *it.Runner().RunnerID)
+		}
+		assert.NoError(t, it.Err())
+		assert.Equal(t, []string{"r1", "r2"}, got)
+	})
+
+	t.Run("stops when the context is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		it, err := NewSearchIterator(ctx, SearchIteratorParams{
+			API:      api.NewMock(pageResponse(0, 1, "r1")),
+			Region:   "us-east-1",
+			PageSize: 1,
+		})
+		assert.NoError(t, err)
+		assert.True(t, it.Next())
+
+		cancel()
+		assert.False(t, it.Next())
+		assert.Equal(t, context.Canceled, it.Err())
+	})
+}
@@ -0,0 +1,76 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package userapi
+
+import (
+	"context"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/client/users"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/util/pagination"
+)
+
+// ListIterator iterates over the users. The users API isn't paginated, so
+// all the users are fetched on the first call to Next, the iterator allows
+// the users to be consumed like the rest of the paginated resources.
+type ListIterator struct {
+	pager *pagination.Pager
+	page  []*models.User
+}
+
+// NewListIterator returns an iterator over the users. The iteration stops
+// when the context is cancelled.
+func NewListIterator(ctx context.Context, params ListParams) (*ListIterator, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	var it ListIterator
+	it.pager = pagination.New(ctx, 0, func(ctx context.Context, from, _ int32) (int, error) {
+		if from > 0 {
+			it.page = nil
+			return 0, nil
+		}
+
+		res, err := params.V1API.Users.GetUsers(
+			users.NewGetUsersParams().WithContext(ctx),
+			params.AuthWriter,
+		)
+		if err != nil {
+			return 0, apierror.Unwrap(err)
+		}
+
+		it.page = res.Payload.Users
+		return len(it.page), nil
+	})
+
+	return &it, nil
+}
+
+// Next advances the iterator to the next user, returning false when there
+// are no more users or the iteration has failed.
+func (it *ListIterator) Next() bool { return it.pager.Next() }
+
+// User returns the current user.
+func (it *ListIterator) User() *models.User {
+	return it.page[it.pager.Index()]
+}
+
+// Err returns the error which stopped the iteration, if any.
+func (it *ListIterator) Err() error { return it.pager.Err() }
@@ -0,0 +1,70 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package userapi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/cloud-sdk-go/pkg/api"
+	"github.com/elastic/cloud-sdk-go/pkg/api/apierror"
+	"github.com/elastic/cloud-sdk-go/pkg/api/mock"
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+func TestListIterator(t *testing.T) {
+	t.Run("fails due to parameter validation", func(t *testing.T) {
+		got, err := NewListIterator(context.Background(), ListParams{})
+		assert.Nil(t, got)
+		assert.Equal(t, apierror.ErrMissingAPI, err)
+	})
+
+	t.Run("iterates over the users", func(t *testing.T) {
+		it, err := NewListIterator(context.Background(), ListParams{
+			API: api.NewMock(mock.New200ResponseAssertion(&mock.RequestAssertion{
+				Header: api.DefaultReadMockHeaders,
+				Method: "GET",
+				Host:   api.DefaultMockHost,
+				Path:   "/api/v1/users",
+			}, mock.NewStructBody(models.UserList{Users: []*models.User{
+				{UserName: ec.String("admin")},
+				{UserName: ec.String("viewer")},
+			}}))),
+		})
+		assert.NoError(t, err)
+
+		var got []string
+		for it.Next() {
+			got = append(got, *it.User().UserName)
+		}
+		assert.NoError(t, it.Err())
+		assert.Equal(t, []string{"admin", "viewer"}, got)
+	})
+
+	t.Run("stops when the users fail to be fetched", func(t *testing.T) {
+		it, err := NewListIterator(context.Background(), ListParams{
+			API: api.NewMock(mock.New500Response(mock.NewStringBody(`{"error": "some error"}`))),
+		})
+		assert.NoError(t, err)
+		assert.False(t, it.Next())
+		assert.EqualError(t, it.Err(), `{"error": "some error"}`)
+	})
+}
@@ -21,18 +21,14 @@ import (
 	"fmt"
 
 	"github.com/elastic/cloud-sdk-go/pkg/models"
-	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+	"github.com/elastic/cloud-sdk-go/pkg/query"
 )
 
 // NewReverseLookupQuery can be used to look up a deployment's ID by specifying
 // the resource kind and ID (elasticsearch, 6779ce55fc0646309ef812d007bb2526).
 func NewReverseLookupQuery(resourceID, kind string) *models.SearchRequest {
-	return &models.SearchRequest{Query: &models.QueryContainer{
-		Nested: &models.NestedQuery{
-			Path: ec.String(fmt.Sprint("resources.", kind)),
-			Query: &models.QueryContainer{Match: map[string]models.MatchQuery{
-				fmt.Sprint("resources.", kind, ".id"): {Query: &resourceID},
-			}},
-		},
-	}}
+	return &models.SearchRequest{Query: query.Nested(
+		fmt.Sprint("resources.", kind),
+		query.Match(fmt.Sprint("resources.", kind, ".id"), resourceID),
+	)}
 }
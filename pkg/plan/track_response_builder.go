@@ -26,14 +26,14 @@ import (
 	"github.com/go-openapi/strfmt"
 
 	"github.com/elastic/cloud-sdk-go/pkg/models"
-	"github.com/elastic/cloud-sdk-go/pkg/util"
+	"github.com/elastic/cloud-sdk-go/pkg/resourcekind"
 )
 
 const (
-	pendingPlanPath = "Info.PlanInfo.Pending"
-	currentPlanPath = "Info.PlanInfo.Current"
-	historyPlanPath = "Info.PlanInfo.History"
-	planAttemptLog  = "PlanAttemptLog"
+	pendingPlan    = "Pending"
+	currentPlan    = "Current"
+	historyPlan    = "History"
+	planAttemptLog = "PlanAttemptLog"
 )
 
 var (
@@ -53,72 +53,37 @@ var (
 // to create properly.
 func buildTrackResponse(res *models.DeploymentResources, getCurrentPlan bool) []TrackResponse {
 	var pending = make([]TrackResponse, 0)
-	for _, info := range res.Elasticsearch {
-		p, err := parseResourceInfo(info, util.Elasticsearch, getCurrentPlan)
-		if err != nil {
-			continue
+	for _, kind := range resourcekind.All() {
+		for _, info := range kind.Infos(res) {
+			p, err := parseResourceInfo(info, kind, getCurrentPlan)
+			if err != nil {
+				continue
+			}
+			pending = append(pending, p)
 		}
-		pending = append(pending, p)
-	}
-
-	for _, info := range res.Kibana {
-		p, err := parseResourceInfo(info, util.Kibana, getCurrentPlan)
-		if err != nil {
-			continue
-		}
-		pending = append(pending, p)
-	}
-
-	for _, info := range res.Apm {
-		p, err := parseResourceInfo(info, util.Apm, getCurrentPlan)
-		if err != nil {
-			continue
-		}
-		pending = append(pending, p)
-	}
-
-	for _, info := range res.Appsearch {
-		p, err := parseResourceInfo(info, util.Appsearch, getCurrentPlan)
-		if err != nil {
-			continue
-		}
-		pending = append(pending, p)
-	}
-
-	for _, info := range res.EnterpriseSearch {
-		p, err := parseResourceInfo(info, util.EnterpriseSearch, getCurrentPlan)
-		if err != nil {
-			continue
-		}
-		pending = append(pending, p)
 	}
 
 	return pending
 }
 
-// parseResourceInfo takes in a <kind>ResourceInfo type along with the Kind to
+// parseResourceInfo takes in a <kind>ResourceInfo type along with its Kind to
 // be able to obtain the resource's plan using reflection, which is deferred to
 // getPlanStepInfo. This function builds the TrackResponse structure.
-func parseResourceInfo(info interface{}, kind string, getCurrentPlan bool) (TrackResponse, error) {
-	stepLog, err := getPlanStepInfo(info, getCurrentPlan)
+func parseResourceInfo(info interface{}, kind resourcekind.Kind, getCurrentPlan bool) (TrackResponse, error) {
+	stepLog, err := getPlanStepInfo(info, kind.PlanInfoPath, getCurrentPlan)
 	if err != nil {
 		return TrackResponse{}, err
 	}
 
-	var id, refID string
-	if v := reflect.ValueOf(info); v.IsValid() {
-		id, refID = stringPFieldValue(v, "ID"), stringPFieldValue(v, "RefID")
-	}
-
 	step, err := GetStepName(stepLog)
 	if step == "" {
 		return TrackResponse{}, ErrPlanFinished
 	}
 
 	return TrackResponse{
-		Kind:     kind,
-		ID:       id,
-		RefID:    refID,
+		Kind:     kind.Name,
+		ID:       kind.ID(info),
+		RefID:    kind.RefID(info),
 		Step:     step,
 		Err:      err,
 		Finished: step == planCompleted,
@@ -133,10 +98,10 @@ func parseResourceInfo(info interface{}, kind string, getCurrentPlan bool) (Trac
 //   2. Obtain the Current plan step log when getCurrentPlan is true.
 //   3. (if getCurrentPlan == true and the Current plan is empty) obtains the
 //       "Current" plan accessing the last item in the plan history slice.
-func getPlanStepInfo(workload interface{}, getCurrentPlan bool) ([]*models.ClusterPlanStepInfo, error) {
-	var planName = pendingPlanPath
+func getPlanStepInfo(workload interface{}, planInfoPath string, getCurrentPlan bool) ([]*models.ClusterPlanStepInfo, error) {
+	var planName = planInfoPath + "." + pendingPlan
 	if getCurrentPlan {
-		planName = currentPlanPath
+		planName = planInfoPath + "." + currentPlan
 	}
 
 	payloadValue := reflect.ValueOf(workload)
@@ -168,7 +133,7 @@ func getPlanStepInfo(workload interface{}, getCurrentPlan bool) ([]*models.Clust
 	// history trail is obtained.
 	var currentPlanIsNilAndPlanLogIsNil = plan.IsNil() && getCurrentPlan || planLog.IsNil()
 	if currentPlanIsNilAndPlanLogIsNil {
-		if history := reflectElemFieldPath(payloadValue, historyPlanPath); history.Len() > 0 {
+		if history := reflectElemFieldPath(payloadValue, planInfoPath+"."+historyPlan); history.Len() > 0 {
 			var lastPlan = history.Index(history.Len() - 1)
 			planLog = lastPlan.Elem().FieldByName(planAttemptLog)
 		}
@@ -185,15 +150,6 @@ func getPlanDuration(log []*models.ClusterPlanStepInfo) strfmt.Duration {
 	return 0
 }
 
-// stringPFieldValue obtains the value of a string pointer field.
-func stringPFieldValue(v reflect.Value, field string) string {
-	if value := v.Elem().FieldByName(field); value.IsValid() {
-		valueStrP := value.Interface().(*string)
-		return *valueStrP
-	}
-	return ""
-}
-
 // reflectElemFieldPath obtains the reflect.Value at the end of the specified
 // path. Path is in the format of <Property>.<Property> as many times as
 // required to obtain the end field.
@@ -0,0 +1,27 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package query provides typed constructors to build the search requests
+// consumed by the deployment, allocator and runner search APIs, as well as a
+// set of presets for common searches.
+//
+//	req := query.NewRequest(query.NewBool().
+//		Filter(query.Term("zone_id", "us-east-1a")).
+//		MustNot(query.Exists("status.maintenance_mode")).
+//		Query(),
+//	).Size(100).SortBy("allocator_id", query.Asc).Build()
+package query
@@ -0,0 +1,87 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package query
+
+import (
+	"fmt"
+
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/resourcekind"
+)
+
+// DeploymentsWithResource returns the deployments which have a resource of
+// the kind, running the version when not empty.
+func DeploymentsWithResource(kind, version string) *models.SearchRequest {
+	var path = fmt.Sprint("resources.", kind)
+	var q = NewBool().Filter(Exists(path + ".id"))
+	if version != "" {
+		q.Filter(Term(fmt.Sprintf(
+			"%s.info.plan_info.current.plan.%s.version", path, kind,
+		), version))
+	}
+
+	return NewRequest(Nested(path, q.Query())).Build()
+}
+
+// DeploymentsWithPendingPlans returns the deployments which have any resource
+// with a pending plan.
+func DeploymentsWithPendingPlans() *models.SearchRequest {
+	var q = NewBool().MinimumShouldMatch(1)
+	for _, kind := range resourcekind.Names() {
+		var path = fmt.Sprint("resources.", kind)
+		q.Should(Nested(path, Exists(path+".info.plan_info.pending")))
+	}
+
+	return NewRequest(q.Query()).Build()
+}
+
+// AllocatorsInZone returns the connected and healthy allocators in the zone
+// which aren't in maintenance mode, thus able to receive new instances. The
+// free capacity of an allocator can't be searched, see FilterFreeMemory.
+func AllocatorsInZone(zone string) *models.SearchRequest {
+	return NewRequest(NewBool().Filter(
+		Term("zone_id", zone),
+		Term("status.connected", true),
+		Term("status.healthy", true),
+		Term("status.maintenance_mode", false),
+	).Query()).SortBy("allocator_id", Asc).Build()
+}
+
+// FilterFreeMemory returns the allocators with at least the specified free
+// memory capacity in MB.
+func FilterFreeMemory(allocators []*models.AllocatorInfo, memory int32) []*models.AllocatorInfo {
+	var res []*models.AllocatorInfo
+	for _, a := range allocators {
+		if a.Capacity == nil || a.Capacity.Memory == nil {
+			continue
+		}
+
+		var total, used int32
+		if a.Capacity.Memory.Total != nil {
+			total = *a.Capacity.Memory.Total
+		}
+		if a.Capacity.Memory.Used != nil {
+			used = *a.Capacity.Memory.Used
+		}
+
+		if total-used >= memory {
+			res = append(res, a)
+		}
+	}
+	return res
+}
@@ -0,0 +1,82 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/resourcekind"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+func TestDeploymentsWithResource(t *testing.T) {
+	assert.Equal(t,
+		`{"query":{"nested":{"path":"resources.kibana","query":{"bool":{"filter":[`+
+			`{"exists":{"field":"resources.kibana.id"}},`+
+			`{"term":{"resources.kibana.info.plan_info.current.plan.kibana.version":{"value":"7.10.0"}}}]}}}},"sort":null}`,
+		toJSON(t, DeploymentsWithResource("kibana", "7.10.0")),
+	)
+	assert.Equal(t,
+		`{"query":{"nested":{"path":"resources.apm","query":{"bool":{"filter":[`+
+			`{"exists":{"field":"resources.apm.id"}}]}}}},"sort":null}`,
+		toJSON(t, DeploymentsWithResource("apm", "")),
+	)
+}
+
+func TestDeploymentsWithPendingPlans(t *testing.T) {
+	var req = DeploymentsWithPendingPlans()
+	assert.Equal(t, int32(1), req.Query.Bool.MinimumShouldMatch)
+	assert.Len(t, req.Query.Bool.Should, len(resourcekind.Names()))
+	assert.Equal(t,
+		`{"nested":{"path":"resources.elasticsearch","query":{"exists":{"field":"resources.elasticsearch.info.plan_info.pending"}}}}`,
+		toJSON(t, req.Query.Bool.Should[0]),
+	)
+}
+
+func TestAllocatorsInZone(t *testing.T) {
+	assert.Equal(t,
+		`{"query":{"bool":{"filter":[{"term":{"zone_id":{"value":"us-east-1a"}}},`+
+			`{"term":{"status.connected":{"value":true}}},{"term":{"status.healthy":{"value":true}}},`+
+			`{"term":{"status.maintenance_mode":{"value":false}}}]}},"sort":[{"allocator_id":{"order":"asc"}}]}`,
+		toJSON(t, AllocatorsInZone("us-east-1a")),
+	)
+}
+
+func TestFilterFreeMemory(t *testing.T) {
+	var allocator = func(id string, total, used int32) *models.AllocatorInfo {
+		return &models.AllocatorInfo{
+			AllocatorID: ec.String(id),
+			Capacity: &models.AllocatorCapacity{Memory: &models.AllocatorCapacityMemory{
+				Total: ec.Int32(total), Used: ec.Int32(used),
+			}},
+		}
+	}
+	var allocators = []*models.AllocatorInfo{
+		allocator("full", 8192, 8192),
+		allocator("half", 8192, 4096),
+		allocator("empty", 8192, 0),
+		{AllocatorID: ec.String("unknown")},
+	}
+
+	assert.Equal(t, allocators[1:3], FilterFreeMemory(allocators, 4096))
+	assert.Equal(t, allocators[2:3], FilterFreeMemory(allocators, 4097))
+	assert.Nil(t, FilterFreeMemory(allocators, 10000))
+}
@@ -0,0 +1,135 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package query
+
+import (
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+// MatchAll returns a query which matches all the documents.
+func MatchAll() *models.QueryContainer {
+	return &models.QueryContainer{MatchAll: map[string]interface{}{}}
+}
+
+// Term returns a query which matches the documents where the field contains
+// the exact value.
+func Term(field string, value interface{}) *models.QueryContainer {
+	return &models.QueryContainer{Term: map[string]models.TermQuery{
+		field: {Value: value},
+	}}
+}
+
+// Match returns a full text query on the field.
+func Match(field, text string) *models.QueryContainer {
+	return &models.QueryContainer{Match: map[string]models.MatchQuery{
+		field: {Query: ec.String(text)},
+	}}
+}
+
+// Prefix returns a query which matches the documents where the field starts
+// with the prefix.
+func Prefix(field, prefix string) *models.QueryContainer {
+	return &models.QueryContainer{Prefix: map[string]models.PrefixQuery{
+		field: {Value: ec.String(prefix)},
+	}}
+}
+
+// Exists returns a query which matches the documents where the field has a
+// value.
+func Exists(field string) *models.QueryContainer {
+	return &models.QueryContainer{Exists: &models.ExistsQuery{Field: ec.String(field)}}
+}
+
+// Nested returns a query on the nested objects found in the path, i.e. the
+// resources of a kind in a deployment.
+func Nested(path string, query *models.QueryContainer) *models.QueryContainer {
+	return &models.QueryContainer{Nested: &models.NestedQuery{
+		Path: ec.String(path), Query: query,
+	}}
+}
+
+// RangeOption sets a bound of a range query.
+type RangeOption func(*models.RangeQuery)
+
+// Gt sets the exclusive lower bound of a range query.
+func Gt(v interface{}) RangeOption { return func(q *models.RangeQuery) { q.Gt = v } }
+
+// Gte sets the inclusive lower bound of a range query.
+func Gte(v interface{}) RangeOption { return func(q *models.RangeQuery) { q.Gte = v } }
+
+// Lt sets the exclusive upper bound of a range query.
+func Lt(v interface{}) RangeOption { return func(q *models.RangeQuery) { q.Lt = v } }
+
+// Lte sets the inclusive upper bound of a range query.
+func Lte(v interface{}) RangeOption { return func(q *models.RangeQuery) { q.Lte = v } }
+
+// Range returns a query which matches the documents where the field is within
+// the specified bounds.
+func Range(field string, opts ...RangeOption) *models.QueryContainer {
+	var q models.RangeQuery
+	for _, opt := range opts {
+		opt(&q)
+	}
+	return &models.QueryContainer{Range: map[string]models.RangeQuery{field: q}}
+}
+
+// Bool builds a boolean query out of other queries.
+type Bool struct {
+	query models.BoolQuery
+}
+
+// NewBool returns an empty boolean query builder.
+func NewBool() *Bool { return new(Bool) }
+
+// Must adds queries which the documents must match.
+func (b *Bool) Must(queries ...*models.QueryContainer) *Bool {
+	b.query.Must = append(b.query.Must, queries...)
+	return b
+}
+
+// MustNot adds queries which the documents must not match.
+func (b *Bool) MustNot(queries ...*models.QueryContainer) *Bool {
+	b.query.MustNot = append(b.query.MustNot, queries...)
+	return b
+}
+
+// Should adds queries which the documents should match.
+func (b *Bool) Should(queries ...*models.QueryContainer) *Bool {
+	b.query.Should = append(b.query.Should, queries...)
+	return b
+}
+
+// Filter adds queries which the documents must match, without scoring.
+func (b *Bool) Filter(queries ...*models.QueryContainer) *Bool {
+	b.query.Filter = append(b.query.Filter, queries...)
+	return b
+}
+
+// MinimumShouldMatch sets the number of should queries which the documents
+// must match.
+func (b *Bool) MinimumShouldMatch(n int32) *Bool {
+	b.query.MinimumShouldMatch = n
+	return b
+}
+
+// Query returns the boolean query.
+func (b *Bool) Query() *models.QueryContainer {
+	var q = b.query
+	return &models.QueryContainer{Bool: &q}
+}
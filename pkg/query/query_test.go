@@ -0,0 +1,111 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package query
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+)
+
+func toJSON(t *testing.T, v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestQueries(t *testing.T) {
+	tests := []struct {
+		name  string
+		query *models.QueryContainer
+		want  string
+	}{
+		{
+			name:  "match all",
+			query: MatchAll(),
+			want:  `{"match_all":{}}`,
+		},
+		{
+			name:  "term",
+			query: Term("status.healthy", true),
+			want:  `{"term":{"status.healthy":{"value":true}}}`,
+		},
+		{
+			name:  "match",
+			query: Match("name", "my deployment"),
+			want:  `{"match":{"name":{"query":"my deployment"}}}`,
+		},
+		{
+			name:  "prefix",
+			query: Prefix("allocator_id", "i-"),
+			want:  `{"prefix":{"allocator_id":{"value":"i-"}}}`,
+		},
+		{
+			name:  "exists",
+			query: Exists("resources.kibana.id"),
+			want:  `{"exists":{"field":"resources.kibana.id"}}`,
+		},
+		{
+			name:  "range",
+			query: Range("capacity.memory.total", Gt(1024), Lte(8192)),
+			want:  `{"range":{"capacity.memory.total":{"gt":1024,"lte":8192}}}`,
+		},
+		{
+			name:  "range with inclusive lower and exclusive upper bounds",
+			query: Range("capacity.memory.used", Gte(0), Lt(10)),
+			want:  `{"range":{"capacity.memory.used":{"gte":0,"lt":10}}}`,
+		},
+		{
+			name:  "nested",
+			query: Nested("resources.apm", Term("resources.apm.ref_id", "main-apm")),
+			want:  `{"nested":{"path":"resources.apm","query":{"term":{"resources.apm.ref_id":{"value":"main-apm"}}}}}`,
+		},
+		{
+			name: "bool",
+			query: NewBool().
+				Must(Term("a", 1)).
+				MustNot(Term("b", 2)).
+				Should(Term("c", 3), Term("d", 4)).
+				Filter(Exists("e")).
+				MinimumShouldMatch(1).
+				Query(),
+			want: `{"bool":{"filter":[{"exists":{"field":"e"}}],"minimum_should_match":1,` +
+				`"must":[{"term":{"a":{"value":1}}}],"must_not":[{"term":{"b":{"value":2}}}],` +
+				`"should":[{"term":{"c":{"value":3}}},{"term":{"d":{"value":4}}}]}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, toJSON(t, tt.query))
+		})
+	}
+}
+
+func TestBool_Query(t *testing.T) {
+	var b = NewBool().Must(Term("a", 1))
+	var first = b.Query()
+	b.Must(Term("b", 2))
+
+	assert.Len(t, first.Bool.Must, 1)
+	assert.Len(t, b.Query().Bool.Must, 2)
+}
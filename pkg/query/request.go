@@ -0,0 +1,75 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package query
+
+import "github.com/elastic/cloud-sdk-go/pkg/models"
+
+// Order of a sorted field.
+type Order string
+
+const (
+	// Asc sorts in ascending order.
+	Asc Order = "asc"
+
+	// Desc sorts in descending order.
+	Desc Order = "desc"
+)
+
+// Sort returns the sort clause of a field.
+func Sort(field string, order Order) interface{} {
+	return map[string]interface{}{field: map[string]string{"order": string(order)}}
+}
+
+// Request builds a search request.
+type Request struct {
+	request models.SearchRequest
+}
+
+// NewRequest returns a search request builder with the query, which matches
+// all the documents when nil.
+func NewRequest(query *models.QueryContainer) *Request {
+	if query == nil {
+		query = MatchAll()
+	}
+	return &Request{request: models.SearchRequest{Query: query}}
+}
+
+// Size sets the maximum number of returned documents.
+func (r *Request) Size(size int32) *Request {
+	r.request.Size = size
+	return r
+}
+
+// From sets the offset of the returned documents.
+func (r *Request) From(from int32) *Request {
+	r.request.From = from
+	return r
+}
+
+// SortBy adds a sorted field, the documents are sorted by the fields in the
+// order they're added.
+func (r *Request) SortBy(field string, order Order) *Request {
+	r.request.Sort = append(r.request.Sort, Sort(field, order))
+	return r
+}
+
+// Build returns the search request.
+func (r *Request) Build() *models.SearchRequest {
+	var req = r.request
+	return &req
+}
@@ -0,0 +1,53 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		request *Request
+		want    string
+	}{
+		{
+			name:    "matches all documents without a query",
+			request: NewRequest(nil),
+			want:    `{"query":{"match_all":{}},"sort":null}`,
+		},
+		{
+			name: "sets the size, offset and sort",
+			request: NewRequest(Term("zone_id", "us-east-1a")).
+				Size(50).
+				From(100).
+				SortBy("zone_id", Asc).
+				SortBy("allocator_id", Desc),
+			want: `{"from":100,"query":{"term":{"zone_id":{"value":"us-east-1a"}}},"size":50,` +
+				`"sort":[{"zone_id":{"order":"asc"}},{"allocator_id":{"order":"desc"}}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, toJSON(t, tt.request.Build()))
+		})
+	}
+}
@@ -0,0 +1,30 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package resourcekind is the registry of the deployment resource kinds
+// (elasticsearch, kibana, apm, appsearch, enterprise_search). Each kind
+// declares its payload and info models, where its resources and plans are
+// found in those models and which stateless operations it supports. The
+// packages which need to handle every kind iterate over the registry rather
+// than hardcoding each of them, so a new kind only needs to be added to it.
+//
+//	for _, kind := range resourcekind.All() {
+//		for _, info := range kind.Infos(res.Resources) {
+//			fmt.Println(kind.Name, kind.RefID(info))
+//		}
+//	}
+package resourcekind
@@ -0,0 +1,470 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package resourcekind
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+)
+
+// Operation is a deployment resource operation which is done through the
+// stateless resource API endpoints.
+type Operation string
+
+const (
+	// Upgrade upgrades the resource to the version of its Elasticsearch.
+	Upgrade Operation = "upgrade"
+
+	// Shutdown shuts the resource down.
+	Shutdown Operation = "shutdown"
+
+	// Delete deletes a shut down resource.
+	Delete Operation = "delete"
+)
+
+// Kind describes a deployment resource kind. The resource models are accessed
+// through reflection using the Field and PlanInfoPath, so the kind's Field
+// must be the same in models.DeploymentResources, the create and update
+// resources and the kind's plan configuration.
+type Kind struct {
+	// Name of the kind as used by the API, i.e. "enterprise_search".
+	Name string
+
+	// Field of the kind in models.DeploymentResources,
+	// models.DeploymentCreateResources, models.DeploymentUpdateResources and
+	// the kind's plan, i.e. "EnterpriseSearch".
+	Field string
+
+	// PayloadType is the kind's payload model, i.e. models.KibanaPayload.
+	PayloadType reflect.Type
+
+	// InfoType is the kind's resource info model, i.e.
+	// models.KibanaResourceInfo.
+	InfoType reflect.Type
+
+	// PlanInfoPath is the "." separated path to the plans info within the
+	// resource info, i.e. "Info.PlanInfo".
+	PlanInfoPath string
+
+	// DisplayNamePath is the "." separated path to the resource's name
+	// within the resource info, i.e. "Info.ClusterName".
+	DisplayNamePath string
+
+	// SettingsPath is the "." separated path to the resource's settings
+	// within the resource info, i.e. "Info.Settings".
+	SettingsPath string
+
+	// DefaultRefID is the RefID of the first resource of the kind, i.e.
+	// "main-kibana".
+	DefaultRefID string
+
+	// MoveField of the kind in models.MoveClustersDetails and
+	// models.MoveClustersRequest, i.e. "KibanaClusters".
+	MoveField string
+
+	// StatelessOperations which the kind supports.
+	StatelessOperations []Operation
+}
+
+// Validate ensures the kind's Field, models and PlanInfoPath match the
+// deployment models.
+func (k Kind) Validate() error {
+	var merr = multierror.NewPrefixed(fmt.Sprintf("resource kind %s", k.Name))
+	if k.Name == "" {
+		merr = merr.Append(errors.New("name cannot be empty"))
+	}
+
+	if k.PayloadType == nil || k.InfoType == nil {
+		return merr.Append(errors.New("payload and info types must be set")).ErrorOrNil()
+	}
+
+	for _, t := range []reflect.Type{
+		reflect.TypeOf(models.DeploymentCreateResources{}),
+		reflect.TypeOf(models.DeploymentUpdateResources{}),
+	} {
+		if err := checkSliceField(t, k.Field, k.PayloadType); err != nil {
+			merr = merr.Append(err)
+		}
+	}
+
+	var resources = reflect.TypeOf(models.DeploymentResources{})
+	if err := checkSliceField(resources, k.Field, k.InfoType); err != nil {
+		merr = merr.Append(err)
+	}
+
+	if k.MoveField != "" {
+		for _, t := range []reflect.Type{
+			reflect.TypeOf(models.MoveClustersDetails{}),
+			reflect.TypeOf(models.MoveClustersRequest{}),
+		} {
+			if f, ok := t.FieldByName(k.MoveField); !ok || f.Type.Kind() != reflect.Slice {
+				merr = merr.Append(fmt.Errorf("move field %s not found in %s", k.MoveField, t))
+			}
+		}
+	}
+
+	for _, p := range []struct{ name, path string }{
+		{"plan info", k.PlanInfoPath},
+		{"display name", k.DisplayNamePath},
+		{"settings", k.SettingsPath},
+	} {
+		if err := checkPath(k.InfoType, p.path); err != nil {
+			merr = merr.Append(fmt.Errorf("%s %s", p.name, err))
+		}
+	}
+
+	return merr.ErrorOrNil()
+}
+
+// Supports returns true when the kind supports the stateless operation.
+func (k Kind) Supports(op Operation) bool {
+	for _, o := range k.StatelessOperations {
+		if o == op {
+			return true
+		}
+	}
+	return false
+}
+
+// Infos returns the kind's resource infos, i.e. *models.KibanaResourceInfo.
+func (k Kind) Infos(res *models.DeploymentResources) []interface{} {
+	return elements(reflect.ValueOf(res), k.Field)
+}
+
+// Payloads returns the kind's payloads, i.e. *models.KibanaPayload, from
+// either a *models.DeploymentCreateResources or a
+// *models.DeploymentUpdateResources.
+func (k Kind) Payloads(resources interface{}) []interface{} {
+	return elements(reflect.ValueOf(resources), k.Field)
+}
+
+// ID returns the ID of a resource info.
+func (k Kind) ID(info interface{}) string {
+	return stringField(reflect.ValueOf(info), "ID")
+}
+
+// RefID returns the RefID of either a resource info or a payload.
+func (k Kind) RefID(resource interface{}) string {
+	return stringField(reflect.ValueOf(resource), "RefID")
+}
+
+// RefIDs returns the RefIDs of all of the kind's resources.
+func (k Kind) RefIDs(res *models.DeploymentResources) []string {
+	var refIDs []string
+	for _, info := range k.Infos(res) {
+		refIDs = append(refIDs, k.RefID(info))
+	}
+	return refIDs
+}
+
+// PlanInfo returns the plans info of a resource info, i.e.
+// *models.KibanaClusterPlansInfo, or nil when it's not set.
+func (k Kind) PlanInfo(info interface{}) interface{} {
+	var v = fieldPath(reflect.ValueOf(info), strings.Split(k.PlanInfoPath, ".")...)
+	if !v.IsValid() || v.Kind() != reflect.Ptr || v.IsNil() {
+		return nil
+	}
+	return v.Interface()
+}
+
+// CurrentVersion returns the version of a resource info's current plan, or an
+// empty string when it can't be obtained.
+func (k Kind) CurrentVersion(info interface{}) string {
+	var path = append(strings.Split(k.PlanInfoPath, "."), "Current", "Plan", k.Field, "Version")
+	if v := fieldPath(reflect.ValueOf(info), path...); v.IsValid() && v.Kind() == reflect.String {
+		return v.String()
+	}
+	return ""
+}
+
+// SetRegion sets the region of a payload when it has none.
+func (k Kind) SetRegion(payload interface{}, region string) {
+	var v = fieldPath(reflect.ValueOf(payload), "Region")
+	if v.IsValid() && v.IsNil() && v.CanSet() {
+		v.Set(reflect.ValueOf(&region))
+	}
+}
+
+// SetVersion sets the version of a payload's plan, doing nothing when the
+// payload has no plan.
+func (k Kind) SetVersion(payload interface{}, version string) {
+	var v = fieldPath(reflect.ValueOf(payload), "Plan", k.Field, "Version")
+	if v.IsValid() && v.Kind() == reflect.String && v.CanSet() {
+		v.SetString(version)
+	}
+}
+
+// NewPayload builds a payload out of a resource info, using the resource's
+// current plan and settings. An error is returned when the resource has no
+// current plan.
+func (k Kind) NewPayload(info interface{}) (interface{}, error) {
+	var v = reflect.ValueOf(info)
+	var current = fieldPath(v, append(strings.Split(k.PlanInfoPath, "."), "Current")...)
+	if !current.IsValid() || current.IsNil() {
+		return nil, fmt.Errorf("%s resource %s has no current plan", k.Name, k.RefID(info))
+	}
+
+	var payload = reflect.New(k.PayloadType)
+	setField(payload, "Plan", fieldPath(current, "Plan"))
+	setField(payload, "Settings", fieldPath(v, strings.Split(k.SettingsPath, ".")...))
+	for _, name := range []string{"RefID", "Region", "ElasticsearchClusterRefID"} {
+		setField(payload, name, fieldPath(v, name))
+	}
+
+	if name := fieldPath(v, strings.Split(k.DisplayNamePath, ".")...); name.Kind() == reflect.Ptr && !name.IsNil() {
+		setField(payload, "DisplayName", name.Elem())
+	}
+
+	return payload.Interface(), nil
+}
+
+// SetPayloads replaces the kind's payloads of either a
+// *models.DeploymentCreateResources or a *models.DeploymentUpdateResources.
+func (k Kind) SetPayloads(resources interface{}, payloads []interface{}) {
+	setElements(reflect.ValueOf(resources), k.Field, payloads)
+}
+
+// Moves returns the kind's cluster moves, i.e. *models.MoveKibanaClusterDetails,
+// from either a *models.MoveClustersDetails or a *models.MoveClustersRequest.
+func (k Kind) Moves(moves interface{}) []interface{} {
+	if k.MoveField == "" {
+		return nil
+	}
+	return elements(reflect.ValueOf(moves), k.MoveField)
+}
+
+// SetMoves replaces the kind's cluster moves of a *models.MoveClustersRequest.
+func (k Kind) SetMoves(moves interface{}, items []interface{}) {
+	if k.MoveField != "" {
+		setElements(reflect.ValueOf(moves), k.MoveField, items)
+	}
+}
+
+// Topology returns the cluster topology elements of a payload's plan, i.e.
+// *models.KibanaClusterTopologyElement.
+func (k Kind) Topology(payload interface{}) []interface{} {
+	return elements(fieldPath(reflect.ValueOf(payload), "Plan"), "ClusterTopology")
+}
+
+// DropTransient removes the transient settings of a payload's plan.
+func (k Kind) DropTransient(payload interface{}) {
+	var v = fieldPath(reflect.ValueOf(payload), "Plan", "Transient")
+	if v.IsValid() && v.CanSet() {
+		v.Set(reflect.Zero(v.Type()))
+	}
+}
+
+// SetRefID sets the RefID of a payload.
+func (k Kind) SetRefID(payload interface{}, refID string) {
+	setField(reflect.ValueOf(payload), "RefID", reflect.ValueOf(&refID))
+}
+
+// ElasticsearchRefID returns the RefID of the Elasticsearch resource which a
+// payload belongs to, or an empty string when it has none.
+func (k Kind) ElasticsearchRefID(payload interface{}) string {
+	return stringField(reflect.ValueOf(payload), "ElasticsearchClusterRefID")
+}
+
+// SetElasticsearchRefID sets the RefID of the Elasticsearch resource which a
+// payload belongs to, doing nothing on the kinds which don't have one.
+func (k Kind) SetElasticsearchRefID(payload interface{}, refID string) {
+	setField(reflect.ValueOf(payload), "ElasticsearchClusterRefID", reflect.ValueOf(&refID))
+}
+
+// ReplaceRegion sets the region of a payload, even when it has one.
+func (k Kind) ReplaceRegion(payload interface{}, region string) {
+	setField(reflect.ValueOf(payload), "Region", reflect.ValueOf(&region))
+}
+
+// InstanceConfigurationID returns the instance configuration ID of a topology
+// element.
+func InstanceConfigurationID(element interface{}) string {
+	var v = fieldPath(reflect.ValueOf(element), "InstanceConfigurationID")
+	if !v.IsValid() || v.Kind() != reflect.String {
+		return ""
+	}
+	return v.String()
+}
+
+// TopologySize returns the size of a topology element, or nil when it has
+// none.
+func TopologySize(element interface{}) *models.TopologySize {
+	var v = fieldPath(reflect.ValueOf(element), "Size")
+	if !v.IsValid() {
+		return nil
+	}
+
+	size, _ := v.Interface().(*models.TopologySize)
+	return size
+}
+
+// ZoneCount returns the number of zones of a topology element.
+func ZoneCount(element interface{}) int32 {
+	var v = fieldPath(reflect.ValueOf(element), "ZoneCount")
+	if !v.IsValid() || v.Kind() != reflect.Int32 {
+		return 0
+	}
+	return int32(v.Int())
+}
+
+// MoveClusterIDs returns the IDs of the clusters of a cluster move, either
+// its ClusterID when it's a move detail or its ClusterIds when it's a move
+// configuration.
+func MoveClusterIDs(move interface{}) []string {
+	if id := stringField(reflect.ValueOf(move), "ClusterID"); id != "" {
+		return []string{id}
+	}
+
+	var v = fieldPath(reflect.ValueOf(move), "ClusterIds")
+	if !v.IsValid() {
+		return nil
+	}
+
+	ids, _ := v.Interface().([]string)
+	return ids
+}
+
+// SetInstanceConfigurationID sets the instance configuration ID of a topology
+// element.
+func SetInstanceConfigurationID(element interface{}, id string) {
+	setField(reflect.ValueOf(element), "InstanceConfigurationID", reflect.ValueOf(id))
+}
+
+// checkPath ensures that the "." separated field path exists in t. An empty
+// path is not checked.
+func checkPath(t reflect.Type, path string) error {
+	if path == "" {
+		return nil
+	}
+
+	var field = t
+	for _, name := range strings.Split(path, ".") {
+		if field.Kind() == reflect.Ptr {
+			field = field.Elem()
+		}
+		f, ok := field.FieldByName(name)
+		if !ok {
+			return fmt.Errorf("path %s not found in %s", path, t)
+		}
+		field = f.Type
+	}
+	return nil
+}
+
+// setField sets the field of v to value when both are valid and the value is
+// assignable to the field.
+func setField(v reflect.Value, field string, value reflect.Value) {
+	var f = fieldPath(v, field)
+	if !f.IsValid() || !f.CanSet() || !value.IsValid() {
+		return
+	}
+
+	if value.Type().AssignableTo(f.Type()) {
+		f.Set(value)
+	}
+}
+
+// checkSliceField ensures that t has a field which is a slice of pointers to
+// elem.
+func checkSliceField(t reflect.Type, field string, elem reflect.Type) error {
+	f, ok := t.FieldByName(field)
+	if !ok {
+		return fmt.Errorf("field %s not found in %s", field, t)
+	}
+
+	if f.Type != reflect.SliceOf(reflect.PtrTo(elem)) {
+		return fmt.Errorf("field %s of %s is %s, not []*%s", field, t, f.Type, elem)
+	}
+
+	return nil
+}
+
+// setElements replaces the elements of the slice field of v, setting it to
+// nil when there are none.
+func setElements(v reflect.Value, field string, items []interface{}) {
+	var f = fieldPath(v, field)
+	if !f.IsValid() || !f.CanSet() {
+		return
+	}
+
+	if len(items) == 0 {
+		f.Set(reflect.Zero(f.Type()))
+		return
+	}
+
+	var s = reflect.MakeSlice(f.Type(), 0, len(items))
+	for _, item := range items {
+		s = reflect.Append(s, reflect.ValueOf(item))
+	}
+	f.Set(s)
+}
+
+// elements returns the elements of the slice field of v.
+func elements(v reflect.Value, field string) []interface{} {
+	var s = fieldPath(v, field)
+	if !s.IsValid() || s.Kind() != reflect.Slice {
+		return nil
+	}
+
+	var res = make([]interface{}, 0, s.Len())
+	for i := 0; i < s.Len(); i++ {
+		res = append(res, s.Index(i).Interface())
+	}
+	return res
+}
+
+// stringField returns the value of a *string field of v, or an empty string
+// when it's not set.
+func stringField(v reflect.Value, field string) string {
+	var f = fieldPath(v, field)
+	if !f.IsValid() || f.Kind() != reflect.Ptr || f.IsNil() {
+		return ""
+	}
+	if f = f.Elem(); f.Kind() != reflect.String {
+		return ""
+	}
+	return f.String()
+}
+
+// fieldPath follows the field path from v, dereferencing any pointers. The
+// returned value is invalid when any of the fields isn't found or is a nil
+// pointer before the end of the path.
+func fieldPath(v reflect.Value, path ...string) reflect.Value {
+	for _, name := range path {
+		for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				return reflect.Value{}
+			}
+			v = v.Elem()
+		}
+
+		if v.Kind() != reflect.Struct {
+			return reflect.Value{}
+		}
+
+		if v = v.FieldByName(name); !v.IsValid() {
+			return reflect.Value{}
+		}
+	}
+	return v
+}
@@ -0,0 +1,320 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package resourcekind
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+	"github.com/elastic/cloud-sdk-go/pkg/multierror"
+	"github.com/elastic/cloud-sdk-go/pkg/util/ec"
+)
+
+func mustGet(t *testing.T, name string) Kind {
+	k, ok := Get(name)
+	if !ok {
+		t.Fatalf("kind %s is not registered", name)
+	}
+	return k
+}
+
+func TestKind_Validate(t *testing.T) {
+	tests := []struct {
+		name string
+		kind Kind
+		err  string
+	}{
+		{
+			name: "fails on an empty kind",
+			kind: Kind{},
+			err: multierror.NewPrefixed("resource kind ",
+				errors.New("name cannot be empty"),
+				errors.New("payload and info types must be set"),
+			).Error(),
+		},
+		{
+			name: "fails on mismatched fields and types",
+			kind: Kind{
+				Name:         "some",
+				Field:        "Kibana",
+				PayloadType:  reflect.TypeOf(models.ApmPayload{}),
+				InfoType:     reflect.TypeOf(models.KibanaResourceInfo{}),
+				PlanInfoPath: "Info.Plans",
+				SettingsPath: "Info.Setting",
+			},
+			err: multierror.NewPrefixed("resource kind some",
+				errors.New("field Kibana of models.DeploymentCreateResources is []*models.KibanaPayload, not []*models.ApmPayload"),
+				errors.New("field Kibana of models.DeploymentUpdateResources is []*models.KibanaPayload, not []*models.ApmPayload"),
+				errors.New("plan info path Info.Plans not found in models.KibanaResourceInfo"),
+				errors.New("settings path Info.Setting not found in models.KibanaResourceInfo"),
+			).Error(),
+		},
+		{
+			name: "fails on an unknown move field",
+			kind: Kind{
+				Name:         "apm",
+				Field:        "Apm",
+				PayloadType:  reflect.TypeOf(models.ApmPayload{}),
+				InfoType:     reflect.TypeOf(models.ApmResourceInfo{}),
+				PlanInfoPath: "Info.PlanInfo",
+				MoveField:    "Apm",
+			},
+			err: multierror.NewPrefixed("resource kind apm",
+				errors.New("move field Apm not found in models.MoveClustersDetails"),
+				errors.New("move field Apm not found in models.MoveClustersRequest"),
+			).Error(),
+		},
+		{
+			name: "fails on an unknown field",
+			kind: Kind{
+				Name:         "integrations_server",
+				Field:        "IntegrationsServer",
+				PayloadType:  reflect.TypeOf(models.ApmPayload{}),
+				InfoType:     reflect.TypeOf(models.ApmResourceInfo{}),
+				PlanInfoPath: "Info.PlanInfo",
+			},
+			err: multierror.NewPrefixed("resource kind integrations_server",
+				errors.New("field IntegrationsServer not found in models.DeploymentCreateResources"),
+				errors.New("field IntegrationsServer not found in models.DeploymentUpdateResources"),
+				errors.New("field IntegrationsServer not found in models.DeploymentResources"),
+			).Error(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.kind.Validate()
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestKind_Supports(t *testing.T) {
+	assert.False(t, mustGet(t, Elasticsearch).Supports(Upgrade))
+	assert.True(t, mustGet(t, Kibana).Supports(Upgrade))
+	assert.True(t, mustGet(t, EnterpriseSearch).Supports(Shutdown))
+	assert.False(t, Kind{}.Supports(Delete))
+}
+
+func TestKind_Infos(t *testing.T) {
+	var res = &models.DeploymentResources{
+		Kibana: []*models.KibanaResourceInfo{
+			{ID: ec.String("1"), RefID: ec.String("main-kibana")},
+			{ID: ec.String("2"), RefID: ec.String("other-kibana")},
+		},
+		EnterpriseSearch: []*models.EnterpriseSearchResourceInfo{
+			{RefID: ec.String("main-enterprise_search"), Info: &models.EnterpriseSearchInfo{
+				PlanInfo: &models.EnterpriseSearchPlansInfo{
+					Current: &models.EnterpriseSearchPlanInfo{Plan: &models.EnterpriseSearchPlan{
+						EnterpriseSearch: &models.EnterpriseSearchConfiguration{Version: "7.10.0"},
+					}},
+				},
+			}},
+		},
+	}
+
+	var kibana = mustGet(t, Kibana)
+	var infos = kibana.Infos(res)
+	assert.Equal(t, []interface{}{res.Kibana[0], res.Kibana[1]}, infos)
+	assert.Equal(t, "1", kibana.ID(infos[0]))
+	assert.Equal(t, []string{"main-kibana", "other-kibana"}, kibana.RefIDs(res))
+	assert.Equal(t, "", kibana.CurrentVersion(infos[0]))
+	assert.Nil(t, kibana.PlanInfo(infos[0]))
+
+	var enterpriseSearch = mustGet(t, EnterpriseSearch)
+	infos = enterpriseSearch.Infos(res)
+	assert.Equal(t, "", enterpriseSearch.ID(infos[0]))
+	assert.Equal(t, "main-enterprise_search", enterpriseSearch.RefID(infos[0]))
+	assert.Equal(t, "7.10.0", enterpriseSearch.CurrentVersion(infos[0]))
+	assert.Equal(t, res.EnterpriseSearch[0].Info.PlanInfo, enterpriseSearch.PlanInfo(infos[0]))
+
+	assert.Empty(t, mustGet(t, Apm).Infos(res))
+	assert.Empty(t, kibana.Infos(nil))
+}
+
+func TestKind_Payloads(t *testing.T) {
+	var resources = &models.DeploymentCreateResources{
+		Apm: []*models.ApmPayload{
+			{Plan: &models.ApmPlan{Apm: &models.ApmConfiguration{Version: "7.9.0"}}},
+			{Region: ec.String("us-west-2")},
+		},
+	}
+
+	var apm = mustGet(t, Apm)
+	for _, payload := range apm.Payloads(resources) {
+		apm.SetRegion(payload, "us-east-1")
+		apm.SetVersion(payload, "7.10.0")
+	}
+
+	assert.Equal(t, &models.DeploymentCreateResources{
+		Apm: []*models.ApmPayload{
+			{
+				Region: ec.String("us-east-1"),
+				Plan:   &models.ApmPlan{Apm: &models.ApmConfiguration{Version: "7.10.0"}},
+			},
+			{Region: ec.String("us-west-2")},
+		},
+	}, resources)
+
+	var update = &models.DeploymentUpdateResources{
+		Kibana: []*models.KibanaPayload{{RefID: ec.String("main-kibana")}},
+	}
+	var kibana = mustGet(t, Kibana)
+	assert.Equal(t, "main-kibana", kibana.RefID(kibana.Payloads(update)[0]))
+}
+
+func TestKind_NewPayload(t *testing.T) {
+	var kibana = mustGet(t, Kibana)
+	payload, err := kibana.NewPayload(&models.KibanaResourceInfo{
+		ElasticsearchClusterRefID: ec.String("main-elasticsearch"),
+		RefID:                     ec.String("main-kibana"),
+		Region:                    ec.String("us-east-1"),
+		Info: &models.KibanaClusterInfo{
+			ClusterName: ec.String("my-kibana"),
+			Settings:    &models.KibanaClusterSettings{},
+			PlanInfo: &models.KibanaClusterPlansInfo{
+				Current: &models.KibanaClusterPlanInfo{Plan: &models.KibanaClusterPlan{
+					Kibana: &models.KibanaConfiguration{Version: "7.10.0"},
+				}},
+			},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &models.KibanaPayload{
+		DisplayName:               "my-kibana",
+		ElasticsearchClusterRefID: ec.String("main-elasticsearch"),
+		RefID:                     ec.String("main-kibana"),
+		Region:                    ec.String("us-east-1"),
+		Settings:                  &models.KibanaClusterSettings{},
+		Plan: &models.KibanaClusterPlan{
+			Kibana: &models.KibanaConfiguration{Version: "7.10.0"},
+		},
+	}, payload)
+
+	var apm = mustGet(t, Apm)
+	payload, err = apm.NewPayload(&models.ApmResourceInfo{
+		RefID: ec.String("main-apm"),
+		Info: &models.ApmInfo{
+			Name: ec.String("my-apm"),
+			PlanInfo: &models.ApmPlansInfo{
+				Current: &models.ApmPlanInfo{Plan: &models.ApmPlan{}},
+			},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &models.ApmPayload{
+		DisplayName: "my-apm", RefID: ec.String("main-apm"), Plan: &models.ApmPlan{},
+	}, payload)
+
+	payload, err = apm.NewPayload(&models.ApmResourceInfo{RefID: ec.String("main-apm")})
+	assert.EqualError(t, err, "apm resource main-apm has no current plan")
+	assert.Nil(t, payload)
+}
+
+func TestKind_SetPayloads(t *testing.T) {
+	var elasticsearch = mustGet(t, Elasticsearch)
+	var create = &models.DeploymentCreateResources{
+		Elasticsearch: []*models.ElasticsearchPayload{{RefID: ec.String("main-elasticsearch")}},
+	}
+
+	var update models.DeploymentUpdateResources
+	elasticsearch.SetPayloads(&update, elasticsearch.Payloads(create))
+	assert.Equal(t, create.Elasticsearch, update.Elasticsearch)
+
+	elasticsearch.SetPayloads(&update, nil)
+	assert.Nil(t, update.Elasticsearch)
+}
+
+func TestKind_modifiers(t *testing.T) {
+	var payload = &models.ApmPayload{
+		ElasticsearchClusterRefID: ec.String("elasticsearch"),
+		Region:                    ec.String("us-west-2"),
+		Plan: &models.ApmPlan{
+			ClusterTopology: []*models.ApmTopologyElement{
+				{InstanceConfigurationID: "apm"},
+			},
+			Transient: &models.TransientApmPlanConfiguration{},
+		},
+	}
+
+	var apm = mustGet(t, Apm)
+	assert.Equal(t, "elasticsearch", apm.ElasticsearchRefID(payload))
+	apm.SetRefID(payload, "main-apm")
+	apm.SetElasticsearchRefID(payload, "main-elasticsearch")
+	apm.ReplaceRegion(payload, "us-east-1")
+	apm.DropTransient(payload)
+	for _, element := range apm.Topology(payload) {
+		assert.Equal(t, "apm", InstanceConfigurationID(element))
+		SetInstanceConfigurationID(element, "apm.highcpu")
+	}
+
+	assert.Equal(t, &models.ApmPayload{
+		ElasticsearchClusterRefID: ec.String("main-elasticsearch"),
+		RefID:                     ec.String("main-apm"),
+		Region:                    ec.String("us-east-1"),
+		Plan: &models.ApmPlan{
+			ClusterTopology: []*models.ApmTopologyElement{
+				{InstanceConfigurationID: "apm.highcpu"},
+			},
+		},
+	}, payload)
+
+	var elasticsearch = mustGet(t, Elasticsearch)
+	var es = &models.ElasticsearchPayload{}
+	elasticsearch.SetElasticsearchRefID(es, "main-elasticsearch")
+	assert.Equal(t, &models.ElasticsearchPayload{}, es)
+	assert.Empty(t, elasticsearch.Topology(es))
+}
+
+func TestKind_Moves(t *testing.T) {
+	var details = &models.MoveClustersDetails{
+		KibanaClusters: []*models.MoveKibanaClusterDetails{
+			{ClusterID: ec.String("1")}, {ClusterID: ec.String("2")},
+		},
+	}
+
+	var kibana = mustGet(t, Kibana)
+	var moves = kibana.Moves(details)
+	assert.Equal(t, []interface{}{details.KibanaClusters[0], details.KibanaClusters[1]}, moves)
+	assert.Equal(t, []string{"2"}, MoveClusterIDs(moves[1]))
+	assert.Empty(t, mustGet(t, Apm).Moves(details))
+	assert.Empty(t, Kind{}.Moves(details))
+
+	var req models.MoveClustersRequest
+	var config = &models.MoveKibanaClusterConfiguration{ClusterIds: []string{"1", "3"}}
+	kibana.SetMoves(&req, []interface{}{config})
+	assert.Equal(t, []*models.MoveKibanaClusterConfiguration{config}, req.KibanaClusters)
+	assert.Equal(t, []string{"1", "3"}, MoveClusterIDs(kibana.Moves(&req)[0]))
+	assert.Nil(t, MoveClusterIDs(nil))
+}
+
+func TestTopologyElement(t *testing.T) {
+	var size = &models.TopologySize{Resource: ec.String("memory"), Value: ec.Int32(1024)}
+	var element = &models.KibanaClusterTopologyElement{Size: size, ZoneCount: 2}
+	assert.Equal(t, size, TopologySize(element))
+	assert.Equal(t, int32(2), ZoneCount(element))
+	assert.Nil(t, TopologySize(nil))
+	assert.Equal(t, int32(0), ZoneCount(nil))
+}
@@ -0,0 +1,161 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package resourcekind
+
+import (
+	"reflect"
+
+	"github.com/elastic/cloud-sdk-go/pkg/models"
+)
+
+const (
+	// Apm kind
+	Apm = "apm"
+
+	// Appsearch kind
+	Appsearch = "appsearch"
+
+	// Elasticsearch kind
+	Elasticsearch = "elasticsearch"
+
+	// EnterpriseSearch kind
+	EnterpriseSearch = "enterprise_search"
+
+	// Kibana kind
+	Kibana = "kibana"
+)
+
+const (
+	// defaultPlanInfoPath is where the plans are found in all of the
+	// resource info models.
+	defaultPlanInfoPath = "Info.PlanInfo"
+
+	// defaultSettingsPath is where the settings are found in all of the
+	// resource info models.
+	defaultSettingsPath = "Info.Settings"
+
+	// clusterNamePath is where the name is found in the Elasticsearch and
+	// Kibana resource info models, the rest of the kinds use namePath.
+	clusterNamePath = "Info.ClusterName"
+	namePath        = "Info.Name"
+)
+
+// statelessOperations are supported by all of the stateless kinds.
+var statelessOperations = []Operation{Upgrade, Shutdown, Delete}
+
+// registry contains all of the known kinds, in the order in which they are
+// tracked and upgraded. Supporting a new kind is done by adding it here.
+var registry = []Kind{
+	{
+		Name:            Elasticsearch,
+		Field:           "Elasticsearch",
+		PayloadType:     reflect.TypeOf(models.ElasticsearchPayload{}),
+		InfoType:        reflect.TypeOf(models.ElasticsearchResourceInfo{}),
+		PlanInfoPath:    defaultPlanInfoPath,
+		DisplayNamePath: clusterNamePath,
+		SettingsPath:    defaultSettingsPath,
+		DefaultRefID:    "main-elasticsearch",
+		MoveField:       "ElasticsearchClusters",
+	},
+	{
+		Name:                Kibana,
+		Field:               "Kibana",
+		PayloadType:         reflect.TypeOf(models.KibanaPayload{}),
+		InfoType:            reflect.TypeOf(models.KibanaResourceInfo{}),
+		PlanInfoPath:        defaultPlanInfoPath,
+		DisplayNamePath:     clusterNamePath,
+		SettingsPath:        defaultSettingsPath,
+		DefaultRefID:        "main-kibana",
+		MoveField:           "KibanaClusters",
+		StatelessOperations: statelessOperations,
+	},
+	{
+		Name:                Apm,
+		Field:               "Apm",
+		PayloadType:         reflect.TypeOf(models.ApmPayload{}),
+		InfoType:            reflect.TypeOf(models.ApmResourceInfo{}),
+		PlanInfoPath:        defaultPlanInfoPath,
+		DisplayNamePath:     namePath,
+		SettingsPath:        defaultSettingsPath,
+		DefaultRefID:        "main-apm",
+		MoveField:           "ApmClusters",
+		StatelessOperations: statelessOperations,
+	},
+	{
+		Name:                Appsearch,
+		Field:               "Appsearch",
+		PayloadType:         reflect.TypeOf(models.AppSearchPayload{}),
+		InfoType:            reflect.TypeOf(models.AppSearchResourceInfo{}),
+		PlanInfoPath:        defaultPlanInfoPath,
+		DisplayNamePath:     namePath,
+		SettingsPath:        defaultSettingsPath,
+		DefaultRefID:        "main-appsearch",
+		MoveField:           "AppsearchClusters",
+		StatelessOperations: statelessOperations,
+	},
+	{
+		Name:                EnterpriseSearch,
+		Field:               "EnterpriseSearch",
+		PayloadType:         reflect.TypeOf(models.EnterpriseSearchPayload{}),
+		InfoType:            reflect.TypeOf(models.EnterpriseSearchResourceInfo{}),
+		PlanInfoPath:        defaultPlanInfoPath,
+		DisplayNamePath:     namePath,
+		SettingsPath:        defaultSettingsPath,
+		DefaultRefID:        "main-enterprise_search",
+		MoveField:           "EnterpriseSearchClusters",
+		StatelessOperations: statelessOperations,
+	},
+}
+
+// All returns all of the registered kinds in their tracking order.
+func All() []Kind {
+	var kinds = make([]Kind, len(registry))
+	copy(kinds, registry)
+	return kinds
+}
+
+// Names returns the names of all of the registered kinds.
+func Names() []string {
+	var names = make([]string, 0, len(registry))
+	for _, k := range registry {
+		names = append(names, k.Name)
+	}
+	return names
+}
+
+// Get returns the registered kind by name, returning false when it's unknown.
+func Get(name string) (Kind, bool) {
+	for _, k := range registry {
+		if k.Name == name {
+			return k, true
+		}
+	}
+	return Kind{}, false
+}
+
+// Supporting returns the registered kinds which support the stateless
+// operation.
+func Supporting(op Operation) []Kind {
+	var kinds []Kind
+	for _, k := range registry {
+		if k.Supports(op) {
+			kinds = append(kinds, k)
+		}
+	}
+	return kinds
+}
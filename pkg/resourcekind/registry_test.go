@@ -0,0 +1,61 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package resourcekind
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry(t *testing.T) {
+	for _, k := range All() {
+		assert.NoError(t, k.Validate(), k.Name)
+	}
+
+	assert.Equal(t, []string{
+		"elasticsearch", "kibana", "apm", "appsearch", "enterprise_search",
+	}, Names())
+}
+
+func TestGet(t *testing.T) {
+	k, ok := Get("enterprise_search")
+	assert.True(t, ok)
+	assert.Equal(t, "EnterpriseSearch", k.Field)
+
+	k, ok = Get("some")
+	assert.False(t, ok)
+	assert.Equal(t, Kind{}, k)
+}
+
+func TestAll(t *testing.T) {
+	var kinds = All()
+	kinds[0].Name = "modified"
+
+	k, ok := Get(Elasticsearch)
+	assert.True(t, ok)
+	assert.Equal(t, Elasticsearch, k.Name)
+}
+
+func TestSupporting(t *testing.T) {
+	var names []string
+	for _, k := range Supporting(Upgrade) {
+		names = append(names, k.Name)
+	}
+	assert.Equal(t, []string{"kibana", "apm", "appsearch", "enterprise_search"}, names)
+}
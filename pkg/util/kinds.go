@@ -17,19 +17,33 @@
 
 package util
 
+import "github.com/elastic/cloud-sdk-go/pkg/resourcekind"
+
+// The resource kinds are defined in the resourcekind registry, these aliases
+// are kept for compatibility.
 const (
-	// Apm kind
-	Apm = "apm"
+	// Apm kind.
+	//
+	// Deprecated: use resourcekind.Apm.
+	Apm = resourcekind.Apm
 
-	// Appsearch kind
-	Appsearch = "appsearch"
+	// Appsearch kind.
+	//
+	// Deprecated: use resourcekind.Appsearch.
+	Appsearch = resourcekind.Appsearch
 
-	// Elasticsearch kind
-	Elasticsearch = "elasticsearch"
+	// Elasticsearch kind.
+	//
+	// Deprecated: use resourcekind.Elasticsearch.
+	Elasticsearch = resourcekind.Elasticsearch
 
-	// EnterpriseSearch kind
-	EnterpriseSearch = "enterprise_search"
+	// EnterpriseSearch kind.
+	//
+	// Deprecated: use resourcekind.EnterpriseSearch.
+	EnterpriseSearch = resourcekind.EnterpriseSearch
 
-	// Kibana kind
-	Kibana = "kibana"
+	// Kibana kind.
+	//
+	// Deprecated: use resourcekind.Kibana.
+	Kibana = resourcekind.Kibana
 )
@@ -0,0 +1,94 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pagination
+
+import "context"
+
+// DefaultSize is the page size used when none is specified.
+const DefaultSize int32 = 100
+
+// FetchFunc fetches the page starting at the from offset with up to size
+// items, storing the items on the caller and returning how many were fetched.
+type FetchFunc func(ctx context.Context, from, size int32) (int, error)
+
+// Pager iterates over the items of a paginated endpoint, fetching each page
+// when the items of the previous one have been consumed. The last page is
+// the one which has less items than the page size.
+type Pager struct {
+	ctx   context.Context
+	fetch FetchFunc
+	size  int32
+
+	from  int32
+	count int
+	index int
+	done  bool
+	err   error
+}
+
+// New returns a pager which fetches pages of the specified size, or
+// DefaultSize when the size is lower than 1.
+func New(ctx context.Context, size int32, fetch FetchFunc) *Pager {
+	if size < 1 {
+		size = DefaultSize
+	}
+
+	return &Pager{ctx: ctx, fetch: fetch, size: size, index: -1}
+}
+
+// Next advances the pager to the next item, fetching the next page when the
+// current one has been consumed. It returns false when there are no more
+// items, when a page fails to be fetched or when the context is cancelled,
+// after which Err returns the error, if any.
+func (p *Pager) Next() bool {
+	if p.err != nil {
+		return false
+	}
+
+	if p.index+1 < p.count {
+		p.index++
+		return true
+	}
+
+	if p.done {
+		return false
+	}
+
+	if err := p.ctx.Err(); err != nil {
+		p.err = err
+		return false
+	}
+
+	count, err := p.fetch(p.ctx, p.from, p.size)
+	if err != nil {
+		p.err = err
+		return false
+	}
+
+	p.from += int32(count)
+	p.count, p.index = count, 0
+	p.done = count < int(p.size)
+
+	return count > 0
+}
+
+// Index returns the position of the current item in the current page.
+func (p *Pager) Index() int { return p.index }
+
+// Err returns the error which stopped the pager, if any.
+func (p *Pager) Err() error { return p.err }
@@ -0,0 +1,110 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pagination
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPager(t *testing.T) {
+	var items = []int{1, 2, 3, 4, 5}
+	var fetchItems = func(calls *[][2]int32, page *[]int) FetchFunc {
+		return func(ctx context.Context, from, size int32) (int, error) {
+			*calls = append(*calls, [2]int32{from, size})
+			*page = nil
+			for i := from; i < from+size && int(i) < len(items); i++ {
+				*page = append(*page, items[i])
+			}
+			return len(*page), nil
+		}
+	}
+
+	tests := []struct {
+		name  string
+		size  int32
+		want  []int
+		calls [][2]int32
+	}{
+		{
+			name:  "fetches pages until a partial one",
+			size:  2,
+			want:  items,
+			calls: [][2]int32{{0, 2}, {2, 2}, {4, 2}},
+		},
+		{
+			name:  "fetches pages until an empty one",
+			size:  5,
+			want:  items,
+			calls: [][2]int32{{0, 5}, {5, 5}},
+		},
+		{
+			name:  "uses the default size",
+			want:  items,
+			calls: [][2]int32{{0, DefaultSize}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls [][2]int32
+			var page []int
+			var got []int
+
+			var p = New(context.Background(), tt.size, fetchItems(&calls, &page))
+			for p.Next() {
+				got = append(got, page[p.Index()])
+			}
+
+			assert.NoError(t, p.Err())
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.calls, calls)
+			assert.False(t, p.Next())
+		})
+	}
+}
+
+func TestPagerErrors(t *testing.T) {
+	t.Run("stops when a page fails to be fetched", func(t *testing.T) {
+		var p = New(context.Background(), 1, func(ctx context.Context, from, size int32) (int, error) {
+			if from > 0 {
+				return 0, errors.New("some error")
+			}
+			return 1, nil
+		})
+
+		assert.True(t, p.Next())
+		assert.False(t, p.Next())
+		assert.EqualError(t, p.Err(), "some error")
+		assert.False(t, p.Next())
+	})
+
+	t.Run("stops when the context is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		var p = New(ctx, 1, func(ctx context.Context, from, size int32) (int, error) {
+			return 1, nil
+		})
+
+		assert.True(t, p.Next())
+		cancel()
+		assert.False(t, p.Next())
+		assert.Equal(t, context.Canceled, p.Err())
+	})
+}